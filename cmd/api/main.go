@@ -1,13 +1,23 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"blog/internal/application"
 	"blog/internal/config"
+	"blog/internal/domain/policy"
 	"blog/internal/domain/service"
-	"blog/internal/infrastructure/persistence"
+	"blog/internal/infrastructure/search"
+	"blog/internal/infrastructure/spam"
 	"blog/internal/interfaces/api"
 	"blog/pkg/middleware"
 
@@ -16,40 +26,132 @@ import (
 
 func main() {
 	// 加载配置
-	cfg := config.NewConfig()
-
-	// 初始化数据库连接
-	dbConn, err := persistence.NewMySQLConnection(&cfg.Database)
+	cfg, flags, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 	if err != nil {
-		log.Fatalf("数据库连接失败: %v", err)
+		log.Fatalf("加载配置失败: %v", err)
+	}
+	if flags.PrintConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			log.Fatalf("打印配置失败: %v", err)
+		}
+		return
 	}
-	defer dbConn.Close()
 
-	// 初始化数据库表
-	err = dbConn.InitTables()
-	if err != nil {
-		log.Fatalf("初始化数据库表失败: %v", err)
+	// 执行子命令
+	if len(flags.Args) > 0 {
+		if flags.Args[0] != "migrate" {
+			log.Fatalf("未知的命令%q，可用的命令: migrate", flags.Args[0])
+		}
+		if err := runMigrate(cfg, flags.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("配置校验失败:\n%v", err)
+	}
+	if cfg.Auth.JWTSecret == "" {
+		secret, err := demoJWTSecret()
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfg.Auth.JWTSecret = secret
+		log.Println("警告: 未设置auth.jwt_secret，使用本次启动随机生成的密钥，重启后已签发的令牌失效")
 	}
 
 	// 初始化存储库
-	postRepo := persistence.NewMySQLPostRepository(dbConn)
-	commentRepo := persistence.NewMySQLCommentRepository(dbConn)
-	categoryRepo := persistence.NewMySQLCategoryRepository(dbConn)
+	repos, err := openRepositories(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer repos.close()
+
+	// 初始化权限策略
+	accessPolicy := policy.NewPolicy()
 
 	// 初始化领域服务
-	postService := service.NewPostService(postRepo, categoryRepo)
-	commentService := service.NewCommentService(commentRepo, postRepo)
-	categoryService := service.NewCategoryService(categoryRepo)
+	postService := service.NewPostService(repos.posts, repos.categories, repos.redirects, repos.revisions, accessPolicy)
+	commentService := service.NewCommentService(repos.comments, repos.posts, accessPolicy, service.CommentSettings{
+		MaxDepth:    cfg.Comment.MaxDepth,
+		AutoApprove: cfg.Comment.AutoApprove,
+	})
+	categoryService := service.NewCategoryService(repos.categories, accessPolicy)
+	userService := service.NewUserService(repos.users, accessPolicy)
+	tagService := service.NewTagService(repos.tags)
+
+	// 使用内存存储时写入演示数据
+	if cfg.Storage == "memory" {
+		if err := seedDemoData(userService, categoryService, postService, tagService, commentService); err != nil {
+			log.Fatalf("写入演示数据失败: %v", err)
+		}
+		log.Printf("使用内存存储，数据在服务重启后丢失；演示账号: admin、editor、author、reader，密码均为 %s", demoPassword)
+	}
+
+	// 为历史文章补全slug
+	if count, err := postService.BackfillSlugs(); err != nil {
+		log.Fatalf("补全文章slug失败: %v", err)
+	} else if count > 0 {
+		log.Printf("已为 %d 篇文章生成slug", count)
+	}
+
+	// 使用内置搜索引擎时，启动时从数据库构建索引
+	if cfg.Search.Engine == "memory" {
+		postService.SetSearchIndex(search.NewMemoryIndex())
+		count, err := postService.BuildSearchIndex()
+		if err != nil {
+			log.Fatalf("构建搜索索引失败: %v", err)
+		}
+		log.Printf("搜索索引构建完成，共 %d 篇文章", count)
+	}
+
+	// 开启垃圾评论检测时，用历史审核结果训练本地分类器，配置了Akismet时在本地检测之后调用
+	if cfg.Spam.Enabled {
+		localChecker := spam.NewLocalChecker(spam.Settings{
+			MaxLinks:       cfg.Spam.MaxLinks,
+			BannedWords:    cfg.Spam.BannedWords,
+			RateLimit:      cfg.Spam.RateLimit,
+			RateWindow:     cfg.Spam.RateWindow,
+			BayesThreshold: cfg.Spam.BayesThreshold,
+		})
+		count, err := commentService.TrainSpamChecker(localChecker, cfg.Spam.TrainingLimit)
+		if err != nil {
+			log.Fatalf("训练垃圾评论检测器失败: %v", err)
+		}
+		log.Printf("垃圾评论检测器训练完成，共 %d 条评论", count)
+
+		if cfg.Spam.Akismet.Enabled {
+			akismet := cfg.Spam.Akismet
+			commentService.SetSpamChecker(spam.NewChainChecker(localChecker, spam.NewAkismetChecker(akismet.Endpoint, akismet.APIKey, akismet.BlogURL, akismet.Timeout)))
+		} else {
+			commentService.SetSpamChecker(localChecker)
+		}
+	}
 
 	// 初始化应用服务
-	postApp := application.NewPostApp(postService, categoryService)
+	postApp := application.NewPostApp(postService, categoryService, tagService)
 	commentApp := application.NewCommentApp(commentService)
 	categoryApp := application.NewCategoryApp(categoryService)
+	tagApp := application.NewTagApp(tagService)
+	userApp := application.NewUserApp(userService, cfg.Auth.JWTSecret, cfg.Auth.TokenTTL)
+
+	// 启动定时发布任务
+	publishScheduler := application.NewPublishScheduler(postService, repos.locker, application.SystemClock{}, cfg.Scheduler.PublishInterval)
+	publishScheduler.Start()
+
+	// 启动回收站清理任务
+	trashPurger := application.NewTrashPurger(postService, commentService, categoryService, repos.locker, application.SystemClock{}, cfg.Trash.PurgeInterval, cfg.Trash.Retention)
+	trashPurger.Start()
 
 	// 初始化处理器
 	postHandler := api.NewPostHandler(postApp)
 	commentHandler := api.NewCommentHandler(commentApp)
 	categoryHandler := api.NewCategoryHandler(categoryApp)
+	userHandler := api.NewUserHandler(userApp)
+	tagHandler := api.NewTagHandler(tagApp, postApp)
 
 	// 设置Gin模式
 	gin.SetMode(gin.ReleaseMode)
@@ -61,15 +163,39 @@ func main() {
 	engine.Use(gin.Recovery())
 	engine.Use(middleware.Logger())
 	engine.Use(middleware.CORS())
+	engine.Use(middleware.ErrorHandler())
 
 	// 注册路由
-	router := api.NewRouter(engine, postHandler, commentHandler, categoryHandler)
+	router := api.NewRouter(engine, middleware.Auth(cfg.Auth.JWTSecret, userApp.LookupUser), userHandler, postHandler, commentHandler, categoryHandler, tagHandler)
 	router.SetupRoutes()
 
 	// 启动服务器
 	port := cfg.Server.Port
-	log.Printf("服务器启动在 http://localhost:%s", port)
-	if err := router.Run(fmt.Sprintf(":%s", port)); err != nil {
-		log.Fatalf("服务器启动失败: %v", err)
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%s", port),
+		Handler: engine,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		log.Printf("服务器启动在 http://localhost:%s", port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("服务器启动失败: %v", err)
+		}
+	}()
+
+	// 等待退出信号后优雅关闭
+	<-ctx.Done()
+	log.Println("正在关闭服务器...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("服务器关闭失败: %v", err)
 	}
+	publishScheduler.Stop()
+	trashPurger.Stop()
+	commentService.WaitLearning()
 }
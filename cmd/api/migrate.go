@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"blog/internal/config"
+	"blog/internal/infrastructure/persistence"
+	"blog/internal/infrastructure/persistence/migrations"
+)
+
+// migrateUsage migrate子命令的用法
+const migrateUsage = `用法:
+  blog migrate up              执行所有未执行的迁移
+  blog migrate down [N]        回滚最近执行的N个迁移，默认为1
+  blog migrate status          查看迁移执行状态
+  blog migrate create <name>   在源码目录下为每种数据库生成新的迁移文件`
+
+// runMigrate 执行migrate子命令
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	// create只生成文件，不需要连接数据库
+	if args[0] == "create" {
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		paths, err := migrations.Create(migrations.SourceDir, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Println("已创建迁移文件，请为每种数据库编写迁移语句:")
+		for _, path := range paths {
+			fmt.Printf("  %s\n", path)
+		}
+		return nil
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("配置校验失败:\n%w", err)
+	}
+	migrator, closeDB, err := newMigrator(&cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("执行了 %d 个迁移\n", len(applied))
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("回滚数量必须是正整数: %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("回滚了 %d 个迁移\n", len(reverted))
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printStatuses(statuses)
+	default:
+		return fmt.Errorf("未知的migrate命令%q\n%s", args[0], migrateUsage)
+	}
+	return nil
+}
+
+// newMigrator 连接数据库并创建使用内置迁移文件的迁移执行器
+func newMigrator(cfg *config.DatabaseConfig) (*migrations.Migrator, func(), error) {
+	list, err := migrations.Embedded(cfg.Driver)
+	if err != nil {
+		return nil, nil, err
+	}
+	dbConn, err := persistence.Open(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("数据库连接失败: %w", err)
+	}
+	return migrations.NewMigrator(dbConn.DB, cfg.Driver, list), func() { dbConn.Close() }, nil
+}
+
+// ensureSchema 服务启动前检查数据库迁移，开启database.auto_migrate时自动执行未执行的迁移，否则拒绝启动
+func ensureSchema(ctx context.Context, dbConn *persistence.Connection, cfg *config.DatabaseConfig) error {
+	list, err := migrations.Embedded(cfg.Driver)
+	if err != nil {
+		return err
+	}
+	migrator := migrations.NewMigrator(dbConn.DB, cfg.Driver, list)
+
+	if cfg.AutoMigrate {
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		if len(applied) > 0 {
+			log.Printf("已执行 %d 个数据库迁移", len(applied))
+		}
+		return nil
+	}
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("有 %d 个未执行的迁移，请先运行 blog migrate up，或开启 database.auto_migrate", len(pending))
+	}
+	return nil
+}
+
+// printStatuses 以表格形式输出迁移状态
+func printStatuses(statuses []migrations.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "版本\t名称\t状态\t执行时间")
+	for _, status := range statuses {
+		state, appliedAt := "未执行", ""
+		if status.AppliedAt != nil {
+			state = "已执行"
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if status.Missing {
+			state = "缺少迁移文件"
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	w.Flush()
+}
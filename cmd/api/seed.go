@@ -0,0 +1,171 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
+	"blog/internal/domain/service"
+)
+
+// demoPassword 演示账号的密码
+const demoPassword = "demo123456"
+
+// demoJWTSecret 生成演示模式使用的随机JWT密钥，仅在本进程内有效
+func demoJWTSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成JWT密钥失败: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// demoUser 演示账号
+type demoUser struct {
+	username string
+	role     entity.Role
+}
+
+// demoUsers 演示账号，第一个注册的用户成为管理员
+var demoUsers = []demoUser{
+	{username: "admin", role: entity.RoleAdmin},
+	{username: "editor", role: entity.RoleEditor},
+	{username: "author", role: entity.RoleAuthor},
+	{username: "reader", role: entity.RoleReader},
+}
+
+// demoPost 演示文章，categories为分类名称
+type demoPost struct {
+	author     string
+	title      string
+	content    string
+	status     entity.PostStatus
+	categories []string
+	tags       []string
+}
+
+// demoPosts 演示文章，按列表中的顺序创建
+var demoPosts = []demoPost{
+	{
+		author:     "admin",
+		title:      "欢迎来到演示博客",
+		content:    "这是内存存储模式下自动生成的演示数据，服务重启后会重新生成。可以使用admin、editor、author、reader登录体验不同角色的权限。",
+		status:     entity.PostStatusPublished,
+		categories: []string{"公告"},
+		tags:       []string{"演示"},
+	},
+	{
+		author:     "author",
+		title:      "Go并发编程入门",
+		content:    "goroutine是Go语言中的轻量级线程，channel用于在goroutine之间传递数据。本文介绍select、sync.WaitGroup和context的基本用法。",
+		status:     entity.PostStatusPublished,
+		categories: []string{"Go"},
+		tags:       []string{"go", "并发"},
+	},
+	{
+		author:     "author",
+		title:      "数据库索引的设计原则",
+		content:    "合适的索引可以让查询快几个数量级。本文从最左前缀、覆盖索引和索引选择性三个方面讨论MySQL、PostgreSQL和SQLite中的索引设计。",
+		status:     entity.PostStatusPublished,
+		categories: []string{"数据库"},
+		tags:       []string{"数据库", "性能"},
+	},
+	{
+		author:     "editor",
+		title:      "用Go实现一个简单的HTTP中间件",
+		content:    "中间件是包装http.Handler的函数，可以在请求前后执行日志、认证和错误处理等通用逻辑。",
+		status:     entity.PostStatusPublished,
+		categories: []string{"Go"},
+		tags:       []string{"go", "http"},
+	},
+	{
+		author:     "author",
+		title:      "草稿：分布式锁的几种实现",
+		content:    "尚未完成的草稿，只有作者、编辑和管理员可以看到。",
+		status:     entity.PostStatusDraft,
+		categories: []string{"编程"},
+		tags:       []string{"并发"},
+	},
+}
+
+// seedDemoData 写入演示账号、分类、文章和评论，供使用内存存储的前端开发和演示使用
+// 数据通过领域服务写入，slug、修订版本和评论审核状态与通过接口创建的数据一致
+func seedDemoData(userService *service.UserService, categoryService *service.CategoryService, postService *service.PostService, tagService *service.TagService, commentService *service.CommentService) error {
+	actors := make(map[string]*policy.Actor, len(demoUsers))
+	var admin *policy.Actor
+	for _, demo := range demoUsers {
+		user, err := userService.Register(demo.username, demoPassword)
+		if err != nil {
+			return fmt.Errorf("创建演示账号%s失败: %w", demo.username, err)
+		}
+		if admin != nil && user.Role != demo.role {
+			if user, err = userService.UpdateUserRole(admin, user.ID, demo.role); err != nil {
+				return fmt.Errorf("设置演示账号%s的角色失败: %w", demo.username, err)
+			}
+		}
+		actors[demo.username] = &policy.Actor{UserID: user.ID, Username: user.Username, Role: user.Role}
+		if admin == nil {
+			admin = actors[demo.username]
+		}
+	}
+
+	// 分类：编程下包含Go和数据库两个子分类
+	categoryIDs := make(map[string]uint)
+	for _, category := range []struct{ name, description, parent string }{
+		{name: "公告", description: "站点公告"},
+		{name: "编程", description: "编程技术文章"},
+		{name: "Go", description: "Go语言", parent: "编程"},
+		{name: "数据库", description: "数据库设计与优化", parent: "编程"},
+	} {
+		var parentID *uint
+		if category.parent != "" {
+			id := categoryIDs[category.parent]
+			parentID = &id
+		}
+		created, err := categoryService.CreateCategory(admin, category.name, category.description, parentID)
+		if err != nil {
+			return fmt.Errorf("创建演示分类%s失败: %w", category.name, err)
+		}
+		categoryIDs[category.name] = created.ID
+	}
+
+	var posts []*entity.Post
+	for _, demo := range demoPosts {
+		post, err := postService.CreatePost(actors[demo.author], demo.title, demo.content, "", "", demo.status, nil)
+		if err != nil {
+			return fmt.Errorf("创建演示文章%s失败: %w", demo.title, err)
+		}
+		for _, name := range demo.categories {
+			if err := postService.AddPostToCategory(post.ID, categoryIDs[name]); err != nil {
+				return fmt.Errorf("设置演示文章%s的分类失败: %w", demo.title, err)
+			}
+		}
+		if _, err := tagService.SetPostTags(post.ID, demo.tags); err != nil {
+			return fmt.Errorf("设置演示文章%s的标签失败: %w", demo.title, err)
+		}
+		posts = append(posts, post)
+	}
+
+	// 定时发布的文章，一天后由定时任务发布
+	publishAt := time.Now().Add(24 * time.Hour)
+	if _, err := postService.CreatePost(actors["editor"], "即将发布：博客新功能预告", "这篇文章将在一天后自动发布。", "", "", entity.PostStatusScheduled, &publishAt); err != nil {
+		return fmt.Errorf("创建演示定时文章失败: %w", err)
+	}
+
+	// 评论：审核者的评论直接公开，读者的评论进入审核队列
+	post := posts[1]
+	comment, err := commentService.CreateComment(actors["admin"], post.ID, nil, "写得很清楚，期待后续关于context的文章。", nil)
+	if err != nil {
+		return fmt.Errorf("创建演示评论失败: %w", err)
+	}
+	if _, err := commentService.CreateComment(actors["editor"], post.ID, &comment.ID, "同意，可以再补充一下errgroup的用法。", nil); err != nil {
+		return fmt.Errorf("创建演示回复失败: %w", err)
+	}
+	if _, err := commentService.CreateComment(actors["reader"], post.ID, nil, "请问channel关闭后还能读取吗？", nil); err != nil {
+		return fmt.Errorf("创建演示待审核评论失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"blog/internal/application"
+	"blog/internal/config"
+	"blog/internal/domain/repository"
+	"blog/internal/infrastructure/persistence"
+)
+
+// repositories 服务使用的存储库以及定时任务的互斥锁
+type repositories struct {
+	posts      repository.PostRepository
+	comments   repository.CommentRepository
+	categories repository.CategoryRepository
+	users      repository.UserRepository
+	redirects  repository.SlugRedirectRepository
+	tags       repository.TagRepository
+	revisions  repository.PostRevisionRepository
+	locker     application.Locker
+	// close 释放存储占用的资源
+	close func() error
+}
+
+// openRepositories 按storage配置创建存储库，使用数据库时连接数据库并检查数据库迁移
+func openRepositories(cfg *config.Config) (*repositories, error) {
+	if cfg.Storage == "memory" {
+		return memoryRepositories(), nil
+	}
+
+	dbConn, err := persistence.Open(&cfg.Database)
+	if err != nil {
+		return nil, fmt.Errorf("数据库连接失败: %w", err)
+	}
+	if err := ensureSchema(context.Background(), dbConn, &cfg.Database); err != nil {
+		dbConn.Close()
+		return nil, fmt.Errorf("数据库迁移失败: %w", err)
+	}
+
+	// 定时任务的互斥锁：MySQL和PostgreSQL多副本部署时使用数据库的咨询锁，SQLite只支持单实例，使用进程内的锁
+	var locker application.Locker
+	switch dbConn.Dialect {
+	case persistence.MySQL:
+		locker = persistence.NewMySQLLocker(dbConn)
+	case persistence.Postgres:
+		locker = persistence.NewPostgresLocker(dbConn)
+	default:
+		locker = persistence.NewLocalLocker()
+	}
+
+	return &repositories{
+		posts:      persistence.NewSQLPostRepository(dbConn),
+		comments:   persistence.NewSQLCommentRepository(dbConn),
+		categories: persistence.NewSQLCategoryRepository(dbConn),
+		users:      persistence.NewSQLUserRepository(dbConn),
+		redirects:  persistence.NewSQLSlugRedirectRepository(dbConn),
+		tags:       persistence.NewSQLTagRepository(dbConn),
+		revisions:  persistence.NewSQLPostRevisionRepository(dbConn),
+		locker:     locker,
+		close:      dbConn.Close,
+	}, nil
+}
+
+// memoryRepositories 创建共享同一份内存数据的存储库，数据只保存在进程内，只支持单实例
+func memoryRepositories() *repositories {
+	store := persistence.NewMemoryStore()
+	return &repositories{
+		posts:      persistence.NewMemoryPostRepository(store),
+		comments:   persistence.NewMemoryCommentRepository(store),
+		categories: persistence.NewMemoryCategoryRepository(store),
+		users:      persistence.NewMemoryUserRepository(store),
+		redirects:  persistence.NewMemorySlugRedirectRepository(store),
+		tags:       persistence.NewMemoryTagRepository(store),
+		revisions:  persistence.NewMemoryPostRevisionRepository(store),
+		locker:     persistence.NewLocalLocker(),
+		close:      func() error { return nil },
+	}
+}
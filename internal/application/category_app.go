@@ -2,6 +2,8 @@ package application
 
 import (
 	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
+	"blog/internal/domain/repository"
 	"blog/internal/domain/service"
 	"blog/internal/interfaces/dto"
 )
@@ -19,8 +21,8 @@ func NewCategoryApp(categoryService *service.CategoryService) *CategoryApp {
 }
 
 // CreateCategory 创建分类
-func (a *CategoryApp) CreateCategory(req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
-	category, err := a.categoryService.CreateCategory(req.Name, req.Description)
+func (a *CategoryApp) CreateCategory(actor *policy.Actor, req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
+	category, err := a.categoryService.CreateCategory(actor, req.Name, req.Description, req.ParentID)
 	if err != nil {
 		return nil, err
 	}
@@ -48,9 +50,19 @@ func (a *CategoryApp) GetAllCategories() ([]*dto.CategoryResponse, error) {
 	return convertToCategoryResponses(categories), nil
 }
 
-// UpdateCategory 更新分类
-func (a *CategoryApp) UpdateCategory(id uint, req *dto.UpdateCategoryRequest) (*dto.CategoryResponse, error) {
-	category, err := a.categoryService.UpdateCategory(id, req.Name, req.Description)
+// GetCategoryTree 获取分类树
+func (a *CategoryApp) GetCategoryTree() ([]*dto.CategoryTreeResponse, error) {
+	nodes, err := a.categoryService.GetCategoryTree()
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToCategoryTree(nodes), nil
+}
+
+// UpdateCategory 更新分类，version为客户端读取分类时的版本号
+func (a *CategoryApp) UpdateCategory(actor *policy.Actor, id, version uint, req *dto.UpdateCategoryRequest) (*dto.CategoryResponse, error) {
+	category, err := a.categoryService.UpdateCategory(actor, id, version, req.Name, req.Description, req.ParentID)
 	if err != nil {
 		return nil, err
 	}
@@ -58,9 +70,40 @@ func (a *CategoryApp) UpdateCategory(id uint, req *dto.UpdateCategoryRequest) (*
 	return convertToCategoryResponse(category), nil
 }
 
-// DeleteCategory 删除分类
-func (a *CategoryApp) DeleteCategory(id uint) error {
-	return a.categoryService.DeleteCategory(id)
+// DeleteCategory 将分类移入回收站
+func (a *CategoryApp) DeleteCategory(actor *policy.Actor, id uint) error {
+	return a.categoryService.DeleteCategory(actor, id)
+}
+
+// ListTrashedCategories 分页获取回收站中的分类
+func (a *CategoryApp) ListTrashedCategories(actor *policy.Actor, req *dto.TrashQuery) (*dto.CategoryListResult, error) {
+	query := &repository.TrashQuery{Page: req.Page, PageSize: req.PageSize}
+	categories, total, err := a.categoryService.ListTrashedCategories(actor, query)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryResponses := make([]*dto.CategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		categoryResponses = append(categoryResponses, convertToCategoryResponse(category))
+	}
+
+	return &dto.CategoryListResult{
+		Categories: categoryResponses,
+		Total:      total,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+	}, nil
+}
+
+// RestoreCategory 将分类移出回收站
+func (a *CategoryApp) RestoreCategory(actor *policy.Actor, id uint) (*dto.CategoryResponse, error) {
+	category, err := a.categoryService.RestoreCategory(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToCategoryResponse(category), nil
 }
 
 // 转换为分类响应
@@ -69,5 +112,20 @@ func convertToCategoryResponse(category *entity.Category) *dto.CategoryResponse
 		ID:          category.ID,
 		Name:        category.Name,
 		Description: category.Description,
+		ParentID:    category.ParentID,
+		Version:     category.Version,
+		DeletedAt:   category.DeletedAt,
+	}
+}
+
+// 转换为分类树响应
+func convertToCategoryTree(nodes []*entity.CategoryNode) []*dto.CategoryTreeResponse {
+	responses := make([]*dto.CategoryTreeResponse, 0, len(nodes))
+	for _, node := range nodes {
+		responses = append(responses, &dto.CategoryTreeResponse{
+			CategoryResponse: convertToCategoryResponse(node.Category),
+			Children:         convertToCategoryTree(node.Children),
+		})
 	}
+	return responses
 }
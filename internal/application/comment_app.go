@@ -2,6 +2,8 @@ package application
 
 import (
 	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
+	"blog/internal/domain/repository"
 	"blog/internal/domain/service"
 	"blog/internal/interfaces/dto"
 )
@@ -18,9 +20,12 @@ func NewCommentApp(commentService *service.CommentService) *CommentApp {
 	}
 }
 
-// CreateComment 创建评论
-func (a *CommentApp) CreateComment(req *dto.CreateCommentRequest) (*dto.CommentResponse, error) {
-	comment, err := a.commentService.CreateComment(req.PostID, req.Content, req.Author)
+// CreateComment 创建评论，作者为当前登录用户，client为提交评论的客户端信息
+func (a *CommentApp) CreateComment(actor *policy.Actor, req *dto.CreateCommentRequest, client *service.CommentClient) (*dto.CommentResponse, error) {
+	if client != nil {
+		client.Honeypot = req.Website
+	}
+	comment, err := a.commentService.CreateComment(actor, req.PostID, req.ParentID, req.Content, client)
 	if err != nil {
 		return nil, err
 	}
@@ -29,8 +34,8 @@ func (a *CommentApp) CreateComment(req *dto.CreateCommentRequest) (*dto.CommentR
 }
 
 // GetCommentByID 根据ID获取评论
-func (a *CommentApp) GetCommentByID(id uint) (*dto.CommentResponse, error) {
-	comment, err := a.commentService.GetCommentByID(id)
+func (a *CommentApp) GetCommentByID(actor *policy.Actor, id uint) (*dto.CommentResponse, error) {
+	comment, err := a.commentService.GetCommentByID(actor, id)
 	if err != nil {
 		return nil, err
 	}
@@ -38,24 +43,116 @@ func (a *CommentApp) GetCommentByID(id uint) (*dto.CommentResponse, error) {
 	return convertToCommentResponse(comment), nil
 }
 
-// GetCommentsByPostID 根据文章ID获取评论
-func (a *CommentApp) GetCommentsByPostID(postID uint) ([]*dto.CommentResponse, error) {
-	comments, err := a.commentService.GetCommentsByPostID(postID)
+// GetCommentsByPostID 根据文章ID按游标分页获取评论，分页以顶级评论为单位
+func (a *CommentApp) GetCommentsByPostID(actor *policy.Actor, postID uint, req *dto.CommentListQuery) (*dto.CommentCursorResult, error) {
+	cursor, err := repository.DecodeCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &repository.CursorQuery{Limit: req.PageSize, Cursor: cursor}
+	threads, hasMore, err := a.commentService.GetCommentsByPostID(actor, postID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments interface{}
+	if req.Format == dto.CommentFormatFlat {
+		flat := make([]*dto.CommentFlatResponse, 0, len(threads))
+		for _, thread := range threads {
+			flat = flattenCommentTree(flat, thread, nil)
+		}
+		comments = flat
+	} else {
+		comments = convertToCommentTree(threads)
+	}
+
+	var first, last *repository.Cursor
+	if len(threads) > 0 {
+		head, tail := threads[0].Comment, threads[len(threads)-1].Comment
+		first = &repository.Cursor{CreatedAt: head.CreatedAt, ID: head.ID}
+		last = &repository.Cursor{CreatedAt: tail.CreatedAt, ID: tail.ID}
+	}
+	next, prev := pageCursors(page, hasMore, first, last)
+
+	return &dto.CommentCursorResult{
+		Comments:   comments,
+		PageSize:   page.Limit,
+		NextCursor: next,
+		PrevCursor: prev,
+	}, nil
+}
+
+// ListComments 分页获取审核队列中的评论
+func (a *CommentApp) ListComments(actor *policy.Actor, req *dto.CommentModerationQuery) (*dto.CommentListResult, error) {
+	query := &repository.CommentQuery{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Status:   entity.CommentStatus(req.Status),
+		PostID:   req.PostID,
+	}
+	comments, total, err := a.commentService.ListComments(actor, query)
+	if err != nil {
+		return nil, err
+	}
+
+	commentResponses := make([]*dto.CommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		commentResponses = append(commentResponses, convertToCommentResponse(comment))
+	}
+
+	return &dto.CommentListResult{
+		Comments: commentResponses,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
+}
+
+// ModerateComments 批量修改评论的审核状态
+func (a *CommentApp) ModerateComments(actor *policy.Actor, req *dto.ModerateCommentsRequest) (*dto.ModerateCommentsResponse, error) {
+	updated, err := a.commentService.ModerateComments(actor, req.IDs, entity.CommentStatus(req.Status))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ModerateCommentsResponse{Updated: updated}, nil
+}
+
+// DeleteComment 将评论移入回收站
+func (a *CommentApp) DeleteComment(actor *policy.Actor, id uint) error {
+	return a.commentService.DeleteComment(actor, id)
+}
+
+// ListTrashedComments 分页获取回收站中的评论
+func (a *CommentApp) ListTrashedComments(actor *policy.Actor, req *dto.TrashQuery) (*dto.CommentListResult, error) {
+	query := &repository.TrashQuery{Page: req.Page, PageSize: req.PageSize}
+	comments, total, err := a.commentService.ListTrashedComments(actor, query)
 	if err != nil {
 		return nil, err
 	}
 
-	var commentResponses []*dto.CommentResponse
+	commentResponses := make([]*dto.CommentResponse, 0, len(comments))
 	for _, comment := range comments {
 		commentResponses = append(commentResponses, convertToCommentResponse(comment))
 	}
 
-	return commentResponses, nil
+	return &dto.CommentListResult{
+		Comments: commentResponses,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
 }
 
-// DeleteComment 删除评论
-func (a *CommentApp) DeleteComment(id uint) error {
-	return a.commentService.DeleteComment(id)
+// RestoreComment 将评论移出回收站
+func (a *CommentApp) RestoreComment(actor *policy.Actor, id uint) (*dto.CommentResponse, error) {
+	comment, err := a.commentService.RestoreComment(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToCommentResponse(comment), nil
 }
 
 // 转换为评论响应
@@ -63,8 +160,40 @@ func convertToCommentResponse(comment *entity.Comment) *dto.CommentResponse {
 	return &dto.CommentResponse{
 		ID:        comment.ID,
 		PostID:    comment.PostID,
+		ParentID:  comment.ParentID,
+		Depth:     comment.Depth,
 		Content:   comment.Content,
 		Author:    comment.Author,
+		Status:    string(comment.Status),
 		CreatedAt: comment.CreatedAt,
+		DeletedAt: comment.DeletedAt,
+	}
+}
+
+// 转换为评论树响应
+func convertToCommentTree(nodes []*entity.CommentNode) []*dto.CommentTreeResponse {
+	responses := make([]*dto.CommentTreeResponse, 0, len(nodes))
+	for _, node := range nodes {
+		responses = append(responses, &dto.CommentTreeResponse{
+			CommentResponse: convertToCommentResponse(node.Comment),
+			Replies:         convertToCommentTree(node.Replies),
+		})
+	}
+	return responses
+}
+
+// 按深度优先顺序将评论树展开为平铺列表，回复紧跟在父评论之后
+func flattenCommentTree(result []*dto.CommentFlatResponse, node *entity.CommentNode, parentPath []uint) []*dto.CommentFlatResponse {
+	path := make([]uint, len(parentPath), len(parentPath)+1)
+	copy(path, parentPath)
+	path = append(path, node.Comment.ID)
+
+	result = append(result, &dto.CommentFlatResponse{
+		CommentResponse: convertToCommentResponse(node.Comment),
+		Path:            path,
+	})
+	for _, reply := range node.Replies {
+		result = flattenCommentTree(result, reply, path)
 	}
+	return result
 }
@@ -0,0 +1,40 @@
+package application
+
+import (
+	"blog/internal/domain/repository"
+)
+
+// pageCursors 根据当前页首尾记录计算上一页和下一页的游标
+// hasMore表示沿当前翻页方向是否还有更多数据
+func pageCursors(page *repository.CursorQuery, hasMore bool, first, last *repository.Cursor) (string, string) {
+	backward := page.Cursor != nil && page.Cursor.Backward
+
+	// 当前页为空时只能返回原方向的反方向
+	if first == nil || last == nil {
+		if page.Cursor == nil {
+			return "", ""
+		}
+		reverse := *page.Cursor
+		reverse.Backward = !backward
+		if backward {
+			return repository.EncodeCursor(reverse), ""
+		}
+		return "", repository.EncodeCursor(reverse)
+	}
+
+	var next, prev string
+	if backward {
+		next = repository.EncodeCursor(*last)
+		if hasMore {
+			prev = repository.EncodeCursor(repository.Cursor{CreatedAt: first.CreatedAt, ID: first.ID, Backward: true})
+		}
+	} else {
+		if hasMore {
+			next = repository.EncodeCursor(*last)
+		}
+		if page.Cursor != nil {
+			prev = repository.EncodeCursor(repository.Cursor{CreatedAt: first.CreatedAt, ID: first.ID, Backward: true})
+		}
+	}
+	return next, prev
+}
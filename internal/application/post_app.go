@@ -2,27 +2,35 @@ package application
 
 import (
 	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
+	"blog/internal/domain/repository"
 	"blog/internal/domain/service"
 	"blog/internal/interfaces/dto"
+	"blog/pkg/utils"
 )
 
+// searchSnippetLength 搜索结果摘要的最大字符数
+const searchSnippetLength = 160
+
 // PostApp 文章应用服务
 type PostApp struct {
 	postService     *service.PostService
 	categoryService *service.CategoryService
+	tagService      *service.TagService
 }
 
 // NewPostApp 创建文章应用服务
-func NewPostApp(postService *service.PostService, categoryService *service.CategoryService) *PostApp {
+func NewPostApp(postService *service.PostService, categoryService *service.CategoryService, tagService *service.TagService) *PostApp {
 	return &PostApp{
 		postService:     postService,
 		categoryService: categoryService,
+		tagService:      tagService,
 	}
 }
 
-// CreatePost 创建文章
-func (a *PostApp) CreatePost(req *dto.CreatePostRequest) (*dto.PostResponse, error) {
-	post, err := a.postService.CreatePost(req.Title, req.Content, req.Author, req.TitleURL)
+// CreatePost 创建文章，作者为当前登录用户
+func (a *PostApp) CreatePost(actor *policy.Actor, req *dto.CreatePostRequest) (*dto.PostResponse, error) {
+	post, err := a.postService.CreatePost(actor, req.Title, req.Content, req.TitleURL, req.Slug, entity.PostStatus(req.Status), req.PublishAt)
 	if err != nil {
 		return nil, err
 	}
@@ -37,60 +45,307 @@ func (a *PostApp) CreatePost(req *dto.CreatePostRequest) (*dto.PostResponse, err
 		}
 	}
 
-	return convertToPostResponse(post), nil
+	response := convertToPostResponse(post)
+	if len(req.Tags) > 0 {
+		tags, err := a.tagService.SetPostTags(post.ID, req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		response.Tags = tagNames(tags)
+	}
+
+	return response, nil
 }
 
 // GetPostByID 根据ID获取文章
-func (a *PostApp) GetPostByID(id uint) (*dto.PostDetailResponse, error) {
-	post, err := a.postService.GetPostByID(id)
+func (a *PostApp) GetPostByID(actor *policy.Actor, id uint) (*dto.PostDetailResponse, error) {
+	post, err := a.postService.GetPostByID(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.viewPost(post)
+}
+
+// GetPostBySlug 根据slug获取文章
+func (a *PostApp) GetPostBySlug(actor *policy.Actor, slug string) (*dto.PostDetailResponse, error) {
+	post, err := a.postService.GetPostBySlug(actor, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.viewPost(post)
+}
+
+// ListSlugRedirects 获取slug重定向列表
+func (a *PostApp) ListSlugRedirects(actor *policy.Actor, req *dto.SlugRedirectQuery) ([]*dto.SlugRedirectResponse, error) {
+	redirects, err := a.postService.ListSlugRedirects(actor, req.PostID)
 	if err != nil {
 		return nil, err
 	}
 
+	responses := make([]*dto.SlugRedirectResponse, 0, len(redirects))
+	for _, redirect := range redirects {
+		responses = append(responses, &dto.SlugRedirectResponse{
+			ID:        redirect.ID,
+			OldSlug:   redirect.OldSlug,
+			PostID:    redirect.PostID,
+			CreatedAt: redirect.CreatedAt,
+		})
+	}
+	return responses, nil
+}
+
+// DeleteSlugRedirect 删除slug重定向
+func (a *PostApp) DeleteSlugRedirect(actor *policy.Actor, id uint) error {
+	return a.postService.DeleteSlugRedirect(actor, id)
+}
+
+// viewPost 增加阅读量并组装文章详情
+func (a *PostApp) viewPost(post *entity.Post) (*dto.PostDetailResponse, error) {
 	// 增加阅读量
-	err = a.postService.IncrementViewCount(id)
+	err := a.postService.IncrementViewCount(post.ID)
 	if err != nil {
 		return nil, err
 	}
 	post.ViewCount++
 
 	// 获取文章分类
-	categories, err := a.categoryService.GetCategoriesByPostID(id)
+	categories, err := a.categoryService.GetCategoriesByPostID(post.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 获取文章标签
+	tags, err := a.tagService.GetTagsByPostID(post.ID)
 	if err != nil {
 		return nil, err
 	}
 
 	return &dto.PostDetailResponse{
-		ID:         post.ID,
-		Title:      post.Title,
-		Content:    post.Content,
-		Author:     post.Author,
-		TitleURL:   post.TitleURL,
-		ViewCount:  post.ViewCount,
-		CreatedAt:  post.CreatedAt,
-		UpdatedAt:  post.UpdatedAt,
-		Categories: convertToCategoryResponses(categories),
+		ID:          post.ID,
+		Title:       post.Title,
+		Content:     post.Content,
+		Author:      post.Author,
+		TitleURL:    post.TitleURL,
+		Slug:        post.Slug,
+		ViewCount:   post.ViewCount,
+		Status:      string(post.Status),
+		PublishedAt: post.PublishedAt,
+		CreatedAt:   post.CreatedAt,
+		UpdatedAt:   post.UpdatedAt,
+		Version:     post.Version,
+		Categories:  convertToCategoryResponses(categories),
+		Tags:        tagNames(tags),
+	}, nil
+}
+
+// ListPosts 按条件分页获取文章列表
+func (a *PostApp) ListPosts(actor *policy.Actor, req *dto.PostListQuery) (*dto.PostListResult, error) {
+	query := buildPostQuery(req)
+	posts, total, err := a.postService.ListPosts(actor, query)
+	if err != nil {
+		return nil, err
+	}
+
+	postResponses := make([]*dto.PostResponse, 0, len(posts))
+	for _, post := range posts {
+		postResponses = append(postResponses, convertToPostResponse(post))
+	}
+
+	return &dto.PostListResult{
+		Posts:    postResponses,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
 	}, nil
 }
 
-// GetAllPosts 获取所有文章
-func (a *PostApp) GetAllPosts() ([]*dto.PostResponse, error) {
-	posts, err := a.postService.GetAllPosts()
+// ListPostsByCursor 按游标分页获取文章列表
+func (a *PostApp) ListPostsByCursor(actor *policy.Actor, req *dto.PostListQuery) (*dto.PostCursorResult, error) {
+	cursor, err := repository.DecodeCursor(*req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &repository.CursorQuery{Limit: req.PageSize, Cursor: cursor}
+	posts, hasMore, err := a.postService.ListPostsByCursor(actor, buildPostQuery(req), page)
 	if err != nil {
 		return nil, err
 	}
 
-	var postResponses []*dto.PostResponse
+	postResponses := make([]*dto.PostResponse, 0, len(posts))
 	for _, post := range posts {
 		postResponses = append(postResponses, convertToPostResponse(post))
 	}
 
-	return postResponses, nil
+	var first, last *repository.Cursor
+	if len(posts) > 0 {
+		first = &repository.Cursor{CreatedAt: posts[0].CreatedAt, ID: posts[0].ID}
+		last = &repository.Cursor{CreatedAt: posts[len(posts)-1].CreatedAt, ID: posts[len(posts)-1].ID}
+	}
+	next, prev := pageCursors(page, hasMore, first, last)
+
+	return &dto.PostCursorResult{
+		Posts:      postResponses,
+		PageSize:   page.Limit,
+		NextCursor: next,
+		PrevCursor: prev,
+	}, nil
+}
+
+// SearchPosts 全文搜索文章，返回带高亮片段的结果
+func (a *PostApp) SearchPosts(actor *policy.Actor, req *dto.PostSearchQuery) (*dto.PostSearchResult, error) {
+	query := &repository.PostSearchQuery{
+		PostQuery: repository.PostQuery{
+			Page:       req.Page,
+			PageSize:   req.PageSize,
+			Status:     entity.PostStatus(req.Status),
+			Author:     req.Author,
+			CategoryID: req.CategoryID,
+		},
+		Keyword: req.Q,
+	}
+	hits, total, err := a.postService.SearchPosts(actor, query)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := utils.SearchTerms(query.Keyword)
+	results := make([]*dto.PostSearchResponse, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, &dto.PostSearchResponse{
+			PostResponse:   convertToPostResponse(hit.Post),
+			Score:          hit.Score,
+			TitleHighlight: utils.Highlight(hit.Post.Title, terms, 0),
+			Snippet:        utils.Highlight(hit.Post.Content, terms, searchSnippetLength),
+		})
+	}
+
+	return &dto.PostSearchResult{
+		Posts:    results,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
+}
+
+// RebuildSearchIndex 重建独立搜索索引
+func (a *PostApp) RebuildSearchIndex(actor *policy.Actor) (*dto.RebuildSearchIndexResponse, error) {
+	count, err := a.postService.RebuildSearchIndex(actor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RebuildSearchIndexResponse{Indexed: count}, nil
+}
+
+// UpdatePost 更新文章，version为客户端读取文章时的版本号
+func (a *PostApp) UpdatePost(actor *policy.Actor, id, version uint, req *dto.UpdatePostRequest) (*dto.PostResponse, error) {
+	post, err := a.postService.UpdatePost(actor, id, version, req.Title, req.Content, req.TitleURL, req.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	response := convertToPostResponse(post)
+	if req.Tags != nil {
+		tags, err := a.tagService.SetPostTags(post.ID, *req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		response.Tags = tagNames(tags)
+	}
+
+	return response, nil
+}
+
+// ListRevisions 获取文章的修订历史
+func (a *PostApp) ListRevisions(actor *policy.Actor, postID uint) ([]*dto.PostRevisionResponse, error) {
+	revisions, err := a.postService.ListRevisions(actor, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.PostRevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		responses = append(responses, convertToPostRevisionResponse(revision))
+	}
+	return responses, nil
+}
+
+// DiffRevisions 比较文章的两个修订版本
+func (a *PostApp) DiffRevisions(actor *policy.Actor, postID uint, req *dto.RevisionDiffQuery) (*dto.RevisionDiffResponse, error) {
+	diff, err := a.postService.DiffRevisions(actor, postID, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.RevisionDiffResponse{
+		PostID: postID,
+		From:   convertToPostRevisionResponse(diff.From),
+		To:     convertToPostRevisionResponse(diff.To),
+		Lines:  make([]*dto.DiffLineResponse, 0, len(diff.Lines)),
+	}
+	for _, line := range diff.Lines {
+		switch line.Op {
+		case utils.DiffInsert:
+			response.Added++
+		case utils.DiffDelete:
+			response.Removed++
+		}
+		response.Lines = append(response.Lines, &dto.DiffLineResponse{
+			Op:      string(line.Op),
+			Text:    line.Text,
+			OldLine: line.OldLine,
+			NewLine: line.NewLine,
+		})
+	}
+	return response, nil
+}
+
+// RestoreRevision 将文章恢复为指定修订版本
+func (a *PostApp) RestoreRevision(actor *policy.Actor, postID uint, revision int) (*dto.PostResponse, error) {
+	post, err := a.postService.RestoreRevision(actor, postID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToPostResponse(post), nil
+}
+
+// PublishPost 立即发布文章
+func (a *PostApp) PublishPost(actor *policy.Actor, id uint) (*dto.PostResponse, error) {
+	post, err := a.postService.PublishPost(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToPostResponse(post), nil
+}
+
+// UnpublishPost 将文章撤回为草稿
+func (a *PostApp) UnpublishPost(actor *policy.Actor, id uint) (*dto.PostResponse, error) {
+	post, err := a.postService.UnpublishPost(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToPostResponse(post), nil
+}
+
+// SchedulePost 设置文章定时发布
+func (a *PostApp) SchedulePost(actor *policy.Actor, id uint, req *dto.SchedulePostRequest) (*dto.PostResponse, error) {
+	post, err := a.postService.SchedulePost(actor, id, req.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToPostResponse(post), nil
 }
 
-// UpdatePost 更新文章
-func (a *PostApp) UpdatePost(id uint, req *dto.UpdatePostRequest) (*dto.PostResponse, error) {
-	post, err := a.postService.UpdatePost(id, req.Title, req.Content, req.TitleURL)
+// ArchivePost 归档文章
+func (a *PostApp) ArchivePost(actor *policy.Actor, id uint) (*dto.PostResponse, error) {
+	post, err := a.postService.ArchivePost(actor, id)
 	if err != nil {
 		return nil, err
 	}
@@ -98,36 +353,78 @@ func (a *PostApp) UpdatePost(id uint, req *dto.UpdatePostRequest) (*dto.PostResp
 	return convertToPostResponse(post), nil
 }
 
-// DeletePost 删除文章
-func (a *PostApp) DeletePost(id uint) error {
-	return a.postService.DeletePost(id)
+// DeletePost 将文章移入回收站
+func (a *PostApp) DeletePost(actor *policy.Actor, id uint) error {
+	return a.postService.DeletePost(actor, id)
 }
 
-// GetPostsByCategory 根据分类获取文章
-func (a *PostApp) GetPostsByCategory(categoryID uint) ([]*dto.PostResponse, error) {
-	posts, err := a.postService.GetPostsByCategory(categoryID)
+// ListTrashedPosts 分页获取回收站中的文章
+func (a *PostApp) ListTrashedPosts(actor *policy.Actor, req *dto.TrashQuery) (*dto.PostListResult, error) {
+	query := &repository.TrashQuery{Page: req.Page, PageSize: req.PageSize}
+	posts, total, err := a.postService.ListTrashedPosts(actor, query)
 	if err != nil {
 		return nil, err
 	}
 
-	var postResponses []*dto.PostResponse
+	postResponses := make([]*dto.PostResponse, 0, len(posts))
 	for _, post := range posts {
 		postResponses = append(postResponses, convertToPostResponse(post))
 	}
 
-	return postResponses, nil
+	return &dto.PostListResult{
+		Posts:    postResponses,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
+}
+
+// RestorePost 将文章移出回收站
+func (a *PostApp) RestorePost(actor *policy.Actor, id uint) (*dto.PostResponse, error) {
+	post, err := a.postService.RestorePost(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToPostResponse(post), nil
+}
+
+// 将列表查询参数转换为仓储查询条件
+func buildPostQuery(req *dto.PostListQuery) *repository.PostQuery {
+	query := &repository.PostQuery{
+		Page:               req.Page,
+		PageSize:           req.PageSize,
+		Status:             entity.PostStatus(req.Status),
+		Author:             req.Author,
+		CategoryID:         req.CategoryID,
+		Tag:                req.Tag,
+		IncludeDescendants: req.IncludeDescendants,
+		CreatedFrom:        req.From,
+		SortBy:             repository.PostSortField(req.Sort),
+		Ascending:          req.Order == "asc",
+	}
+	// 结束日期包含当天
+	if !req.To.IsZero() {
+		query.CreatedTo = req.To.AddDate(0, 0, 1)
+	}
+	return query
 }
 
 // 转换为文章响应
 func convertToPostResponse(post *entity.Post) *dto.PostResponse {
 	return &dto.PostResponse{
-		ID:        post.ID,
-		Title:     post.Title,
-		Author:    post.Author,
-		TitleURL:  post.TitleURL,
-		ViewCount: post.ViewCount,
-		CreatedAt: post.CreatedAt,
-		UpdatedAt: post.UpdatedAt,
+		ID:          post.ID,
+		Title:       post.Title,
+		Author:      post.Author,
+		TitleURL:    post.TitleURL,
+		Slug:        post.Slug,
+		ViewCount:   post.ViewCount,
+		Status:      string(post.Status),
+		PublishedAt: post.PublishedAt,
+		CreatedAt:   post.CreatedAt,
+		UpdatedAt:   post.UpdatedAt,
+		Version:     post.Version,
+		DeletedAt:   post.DeletedAt,
 	}
 }
 
@@ -135,11 +432,18 @@ func convertToPostResponse(post *entity.Post) *dto.PostResponse {
 func convertToCategoryResponses(categories []*entity.Category) []*dto.CategoryResponse {
 	var categoryResponses []*dto.CategoryResponse
 	for _, category := range categories {
-		categoryResponses = append(categoryResponses, &dto.CategoryResponse{
-			ID:          category.ID,
-			Name:        category.Name,
-			Description: category.Description,
-		})
+		categoryResponses = append(categoryResponses, convertToCategoryResponse(category))
 	}
 	return categoryResponses
 }
+
+// 转换为修订版本响应
+func convertToPostRevisionResponse(revision *entity.PostRevision) *dto.PostRevisionResponse {
+	return &dto.PostRevisionResponse{
+		Revision:  revision.Revision,
+		Title:     revision.Title,
+		TitleURL:  revision.TitleURL,
+		Author:    revision.Author,
+		CreatedAt: revision.CreatedAt,
+	}
+}
@@ -0,0 +1,105 @@
+package application
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"blog/internal/domain/service"
+)
+
+// publishLockName 定时发布任务使用的锁名
+const publishLockName = "blog:publish_scheduler"
+
+// Clock 时钟，测试时可注入固定时间
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock 系统时钟
+type SystemClock struct{}
+
+// Now 返回当前时间
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Locker 互斥锁，保证多副本部署时同一时刻只有一个实例执行任务
+type Locker interface {
+	// TryLock 尝试获取锁，不等待；获取成功时返回释放函数
+	TryLock(ctx context.Context, name string) (unlock func(), acquired bool, err error)
+}
+
+// PublishScheduler 定时发布任务，周期性地发布到期的定时文章
+type PublishScheduler struct {
+	postService *service.PostService
+	locker      Locker
+	clock       Clock
+	interval    time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewPublishScheduler 创建定时发布任务
+func NewPublishScheduler(postService *service.PostService, locker Locker, clock Clock, interval time.Duration) *PublishScheduler {
+	return &PublishScheduler{
+		postService: postService,
+		locker:      locker,
+		clock:       clock,
+		interval:    interval,
+	}
+}
+
+// Start 在后台启动任务
+func (s *PublishScheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.RunOnce(ctx); err != nil {
+				log.Printf("定时发布任务执行失败: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop 停止任务并等待正在执行的一轮结束
+func (s *PublishScheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// RunOnce 执行一轮发布，未获取到锁时直接跳过；返回本轮发布的文章数量
+func (s *PublishScheduler) RunOnce(ctx context.Context) (int, error) {
+	unlock, acquired, err := s.locker.TryLock(ctx, publishLockName)
+	if err != nil {
+		return 0, err
+	}
+	if !acquired {
+		return 0, nil
+	}
+	defer unlock()
+
+	posts, err := s.postService.PublishDuePosts(s.clock.Now())
+	for _, post := range posts {
+		log.Printf("定时发布文章: %d %s", post.ID, post.Title)
+	}
+	return len(posts), err
+}
@@ -0,0 +1,60 @@
+package application
+
+import (
+	"blog/internal/domain/entity"
+	"blog/internal/domain/service"
+	"blog/internal/interfaces/dto"
+)
+
+// TagApp 标签应用服务
+type TagApp struct {
+	tagService *service.TagService
+}
+
+// NewTagApp 创建标签应用服务
+func NewTagApp(tagService *service.TagService) *TagApp {
+	return &TagApp{
+		tagService: tagService,
+	}
+}
+
+// GetTagByName 根据名称获取标签
+func (a *TagApp) GetTagByName(name string) (*dto.TagResponse, error) {
+	tag, err := a.tagService.GetTagByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TagResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		CreatedAt: tag.CreatedAt,
+	}, nil
+}
+
+// GetTagCloud 获取标签云
+func (a *TagApp) GetTagCloud(req *dto.TagCloudQuery) ([]*dto.TagCountResponse, error) {
+	counts, err := a.tagService.GetTagCloud(req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.TagCountResponse, 0, len(counts))
+	for _, count := range counts {
+		responses = append(responses, &dto.TagCountResponse{
+			ID:        count.Tag.ID,
+			Name:      count.Tag.Name,
+			PostCount: count.PostCount,
+		})
+	}
+	return responses, nil
+}
+
+// 提取标签名列表
+func tagNames(tags []*entity.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"blog/internal/domain/service"
+)
+
+// trashPurgeLockName 回收站清理任务使用的锁名
+const trashPurgeLockName = "blog:trash_purger"
+
+// TrashPurger 回收站清理任务，周期性地永久删除超过保留期的文章、评论和分类
+type TrashPurger struct {
+	postService     *service.PostService
+	commentService  *service.CommentService
+	categoryService *service.CategoryService
+	locker          Locker
+	clock           Clock
+	interval        time.Duration
+	retention       time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewTrashPurger 创建回收站清理任务，retention为回收站中数据的保留时长
+func NewTrashPurger(postService *service.PostService, commentService *service.CommentService, categoryService *service.CategoryService, locker Locker, clock Clock, interval, retention time.Duration) *TrashPurger {
+	return &TrashPurger{
+		postService:     postService,
+		commentService:  commentService,
+		categoryService: categoryService,
+		locker:          locker,
+		clock:           clock,
+		interval:        interval,
+		retention:       retention,
+	}
+}
+
+// Start 在后台启动任务
+func (p *TrashPurger) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := p.RunOnce(ctx); err != nil {
+				log.Printf("回收站清理任务执行失败: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop 停止任务并等待正在执行的一轮结束
+func (p *TrashPurger) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+}
+
+// RunOnce 执行一轮清理，未获取到锁时直接跳过；返回本轮永久删除的记录数量
+// 某类数据清理失败时继续清理其余数据，错误合并后返回
+func (p *TrashPurger) RunOnce(ctx context.Context) (int64, error) {
+	unlock, acquired, err := p.locker.TryLock(ctx, trashPurgeLockName)
+	if err != nil {
+		return 0, err
+	}
+	if !acquired {
+		return 0, nil
+	}
+	defer unlock()
+
+	before := p.clock.Now().Add(-p.retention)
+	var total int64
+	var errs []error
+	// 先清理评论和文章，文章的永久删除会级联删除其评论
+	for _, purge := range []struct {
+		name string
+		run  func(time.Time) (int64, error)
+	}{
+		{"评论", p.commentService.PurgeTrash},
+		{"文章", p.postService.PurgeTrash},
+		{"分类", p.categoryService.PurgeTrash},
+	} {
+		count, err := purge.run(before)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if count > 0 {
+			log.Printf("回收站清理: 永久删除 %d 条%s", count, purge.name)
+		}
+		total += count
+	}
+	return total, errors.Join(errs...)
+}
@@ -0,0 +1,85 @@
+package application
+
+import (
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
+	"blog/internal/domain/service"
+	"blog/internal/interfaces/dto"
+	"blog/pkg/utils"
+)
+
+// UserApp 用户应用服务
+type UserApp struct {
+	userService *service.UserService
+	jwtSecret   string
+	tokenTTL    time.Duration
+}
+
+// NewUserApp 创建用户应用服务
+func NewUserApp(userService *service.UserService, jwtSecret string, tokenTTL time.Duration) *UserApp {
+	return &UserApp{
+		userService: userService,
+		jwtSecret:   jwtSecret,
+		tokenTTL:    tokenTTL,
+	}
+}
+
+// Register 注册用户
+func (a *UserApp) Register(req *dto.RegisterRequest) (*dto.UserResponse, error) {
+	user, err := a.userService.Register(req.Username, req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToUserResponse(user), nil
+}
+
+// Login 登录并签发令牌
+func (a *UserApp) Login(req *dto.LoginRequest) (*dto.LoginResponse, error) {
+	user, err := a.userService.Authenticate(req.Username, req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := utils.GenerateToken(a.jwtSecret, user.ID, user.Username, string(user.Role), a.tokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LoginResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+		User:      convertToUserResponse(user),
+	}, nil
+}
+
+// LookupUser 查询用户当前的用户名和角色，供认证中间件使用，角色修改后无需等待令牌过期即可生效
+func (a *UserApp) LookupUser(userID uint) (string, string, error) {
+	user, err := a.userService.GetUserByID(userID)
+	if err != nil {
+		return "", "", err
+	}
+	return user.Username, string(user.Role), nil
+}
+
+// UpdateUserRole 修改用户角色
+func (a *UserApp) UpdateUserRole(actor *policy.Actor, id uint, req *dto.UpdateUserRoleRequest) (*dto.UserResponse, error) {
+	user, err := a.userService.UpdateUserRole(actor, id, entity.Role(req.Role))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToUserResponse(user), nil
+}
+
+// 转换为用户响应
+func convertToUserResponse(user *entity.User) *dto.UserResponse {
+	return &dto.UserResponse{
+		ID:        user.ID,
+		Username:  user.Username,
+		Role:      string(user.Role),
+		CreatedAt: user.CreatedAt,
+	}
+}
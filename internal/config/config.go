@@ -1,46 +1,296 @@
 package config
 
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
 // Config 应用配置
+// 字段的config标签为配置项名称，配置文件、环境变量和命令行参数均使用该名称，
+// 标记为secret的配置项在打印时脱敏，并支持通过<名称>_file从文件读取
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
+	// Storage 存储方式：database使用database中配置的数据库，memory将数据保存在进程内并在启动时写入演示数据，重启后数据丢失
+	Storage   string          `config:"storage"`
+	Server    ServerConfig    `config:"server"`
+	Database  DatabaseConfig  `config:"database"`
+	Auth      AuthConfig      `config:"auth"`
+	Scheduler SchedulerConfig `config:"scheduler"`
+	Search    SearchConfig    `config:"search"`
+	Comment   CommentConfig   `config:"comment"`
+	Spam      SpamConfig      `config:"spam"`
+	Trash     TrashConfig     `config:"trash"`
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Port string
+	Port string `config:"port"`
 }
 
-// DatabaseConfig 数据库配置
+// DatabaseConfig 数据库配置，Driver为mysql或postgres时使用Host等连接参数，为sqlite时只使用Path
 type DatabaseConfig struct {
-	Driver   string
-	Host     string
-	Port     string
-	Username string
-	Password string
-	DBName   string
-	Charset  string
+	// Driver 数据库驱动：mysql、postgres或sqlite
+	Driver   string `config:"driver"`
+	Host     string `config:"host"`
+	Port     string `config:"port"`
+	Username string `config:"username"`
+	Password string `config:"password,secret"`
+	DBName   string `config:"db_name"`
+	Charset  string `config:"charset"`
+	// SSLMode PostgreSQL的sslmode连接参数
+	SSLMode string `config:"ssl_mode"`
+	// Path SQLite数据库文件路径
+	Path string `config:"path"`
+	// AutoMigrate 为true时服务启动时自动执行未执行的数据库迁移，否则存在未执行的迁移时拒绝启动
+	AutoMigrate bool `config:"auto_migrate"`
+}
+
+// AuthConfig 认证配置
+type AuthConfig struct {
+	JWTSecret string        `config:"jwt_secret,secret"`
+	TokenTTL  time.Duration `config:"token_ttl"`
+}
+
+// SchedulerConfig 后台任务配置
+type SchedulerConfig struct {
+	PublishInterval time.Duration `config:"publish_interval"`
+}
+
+// SearchConfig 搜索配置
+type SearchConfig struct {
+	// Engine 搜索引擎：database使用数据库自身的搜索（MySQL为FULLTEXT索引，PostgreSQL为tsvector，SQLite为子串匹配），
+	// memory使用内置的内存倒排索引；mysql为database的旧名称
+	Engine string `config:"engine"`
+}
+
+// CommentConfig 评论配置
+type CommentConfig struct {
+	// MaxDepth 回复的最大嵌套层级，顶级评论为第0层，为0时不允许回复
+	MaxDepth int `config:"max_depth"`
+	// AutoApprove 为true时新评论直接公开，否则需编辑或管理员审核
+	AutoApprove bool `config:"auto_approve"`
+}
+
+// SpamConfig 垃圾评论检测配置，数值为0时关闭对应的检测
+type SpamConfig struct {
+	Enabled bool `config:"enabled"`
+	// MaxLinks 单条评论允许的最多链接数
+	MaxLinks int `config:"max_links"`
+	// BannedWords 违禁词，不区分大小写
+	BannedWords []string `config:"banned_words"`
+	// RateLimit 同一IP在RateWindow内允许提交的评论数
+	RateLimit  int           `config:"rate_limit"`
+	RateWindow time.Duration `config:"rate_window"`
+	// BayesThreshold 贝叶斯分类器判定为垃圾评论的概率阈值
+	BayesThreshold float64 `config:"bayes_threshold"`
+	// TrainingLimit 启动时用于训练分类器的历史垃圾评论和正常评论各自的最大数量
+	TrainingLimit int           `config:"training_limit"`
+	Akismet       AkismetConfig `config:"akismet"`
+}
+
+// AkismetConfig Akismet兼容的外部垃圾评论检测服务配置
+type AkismetConfig struct {
+	Enabled  bool   `config:"enabled"`
+	Endpoint string `config:"endpoint"`
+	APIKey   string `config:"api_key,secret"`
+	// BlogURL 站点地址，用于生成文章链接
+	BlogURL string `config:"blog_url"`
+	// Timeout 单次请求的超时时间，超时后放行评论
+	Timeout time.Duration `config:"timeout"`
+}
+
+// TrashConfig 回收站配置
+type TrashConfig struct {
+	// Retention 回收站中的数据保留时长，超过后永久删除
+	Retention time.Duration `config:"retention"`
+	// PurgeInterval 清理任务的执行间隔
+	PurgeInterval time.Duration `config:"purge_interval"`
 }
 
-// NewConfig 创建默认配置
+// NewConfig 创建默认配置，默认配置不包含数据库密码和JWT密钥，需通过配置文件、环境变量或命令行参数提供
 func NewConfig() *Config {
 	return &Config{
+		Storage: "database",
 		Server: ServerConfig{
 			Port: "8080",
 		},
 		Database: DatabaseConfig{
 			Driver:   "mysql",
-			Host:     "192.168.1.11", // 使用本地数据库
+			Host:     "127.0.0.1",
 			Port:     "3306",
-			Username: "knandlkb_test",
-			Password: "KnB8LFM?#6nT", // 无密码
-			DBName:   "a",
+			Username: "blog",
+			DBName:   "blog",
 			Charset:  "utf8mb4",
+			SSLMode:  "prefer",
+			Path:     "blog.db",
+		},
+		Auth: AuthConfig{
+			TokenTTL: 24 * time.Hour,
+		},
+		Scheduler: SchedulerConfig{
+			PublishInterval: time.Minute,
+		},
+		Search: SearchConfig{
+			Engine: "database",
+		},
+		Comment: CommentConfig{
+			MaxDepth:    5,
+			AutoApprove: false,
+		},
+		Spam: SpamConfig{
+			Enabled:        true,
+			MaxLinks:       2,
+			RateLimit:      5,
+			RateWindow:     time.Minute,
+			BayesThreshold: 0.9,
+			TrainingLimit:  1000,
+			Akismet: AkismetConfig{
+				Enabled:  false,
+				Endpoint: "https://rest.akismet.com",
+				BlogURL:  "http://localhost:8080",
+				Timeout:  3 * time.Second,
+			},
+		},
+		Trash: TrashConfig{
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
 		},
 	}
 }
 
+// Validate 校验配置，返回所有不合法的配置项
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	if !validPort(c.Server.Port) {
+		invalid("server.port", "端口必须是1-65535之间的整数，当前为%q", c.Server.Port)
+	}
+
+	switch c.Storage {
+	case "database":
+		switch c.Database.Driver {
+		case "mysql", "postgres":
+			if c.Database.Host == "" {
+				invalid("database.host", "不能为空")
+			}
+			if !validPort(c.Database.Port) {
+				invalid("database.port", "端口必须是1-65535之间的整数，当前为%q", c.Database.Port)
+			}
+			if c.Database.Username == "" {
+				invalid("database.username", "不能为空")
+			}
+			if c.Database.DBName == "" {
+				invalid("database.db_name", "不能为空")
+			}
+			if c.Database.Driver == "postgres" && !validSSLMode(c.Database.SSLMode) {
+				invalid("database.ssl_mode", "不支持的sslmode%q，可选值为disable、allow、prefer、require、verify-ca、verify-full", c.Database.SSLMode)
+			}
+		case "sqlite":
+			if c.Database.Path == "" {
+				invalid("database.path", "使用sqlite时不能为空")
+			}
+		default:
+			invalid("database.driver", "不支持的数据库驱动%q，可选值为mysql、postgres、sqlite", c.Database.Driver)
+		}
+	case "memory":
+		// 内存存储不使用数据库配置
+	default:
+		invalid("storage", "不支持的存储方式%q，可选值为database、memory", c.Storage)
+	}
+
+	// 内存存储的演示模式未设置密钥时，启动时生成随机密钥
+	if c.Auth.JWTSecret == "" && c.Storage != "memory" {
+		invalid("auth.jwt_secret", "不能为空，请通过BLOG_AUTH_JWT_SECRET或auth.jwt_secret_file设置")
+	}
+	if c.Auth.TokenTTL <= 0 {
+		invalid("auth.token_ttl", "必须大于0")
+	}
+
+	if c.Scheduler.PublishInterval <= 0 {
+		invalid("scheduler.publish_interval", "必须大于0")
+	}
+
+	switch c.Search.Engine {
+	case "database", "mysql", "memory":
+	default:
+		invalid("search.engine", "不支持的搜索引擎%q，可选值为database、memory", c.Search.Engine)
+	}
+
+	if c.Comment.MaxDepth < 0 {
+		invalid("comment.max_depth", "不能为负数")
+	}
+
+	if c.Spam.MaxLinks < 0 {
+		invalid("spam.max_links", "不能为负数")
+	}
+	if c.Spam.RateLimit < 0 {
+		invalid("spam.rate_limit", "不能为负数")
+	}
+	if c.Spam.RateLimit > 0 && c.Spam.RateWindow <= 0 {
+		invalid("spam.rate_window", "开启频率限制时必须大于0")
+	}
+	if c.Spam.BayesThreshold < 0 || c.Spam.BayesThreshold > 1 {
+		invalid("spam.bayes_threshold", "必须在0到1之间，当前为%v", c.Spam.BayesThreshold)
+	}
+	if c.Spam.TrainingLimit < 0 {
+		invalid("spam.training_limit", "不能为负数")
+	}
+	if c.Spam.Akismet.Enabled {
+		if c.Spam.Akismet.Endpoint == "" {
+			invalid("spam.akismet.endpoint", "启用Akismet时不能为空")
+		}
+		if c.Spam.Akismet.APIKey == "" {
+			invalid("spam.akismet.api_key", "启用Akismet时不能为空")
+		}
+		if c.Spam.Akismet.Timeout <= 0 {
+			invalid("spam.akismet.timeout", "必须大于0")
+		}
+	}
+
+	if c.Trash.Retention <= 0 {
+		invalid("trash.retention", "必须大于0")
+	}
+	if c.Trash.PurgeInterval <= 0 {
+		invalid("trash.purge_interval", "必须大于0")
+	}
+
+	return errors.Join(errs...)
+}
+
+// validPort 判断端口号是否合法
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
+
+// validSSLMode 判断是否为PostgreSQL支持的sslmode
+func validSSLMode(mode string) bool {
+	switch mode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+		return true
+	}
+	return false
+}
+
 // GetDSN 获取数据库连接字符串
 func (dc *DatabaseConfig) GetDSN() string {
 	return dc.Username + ":" + dc.Password + "@tcp(" + dc.Host + ":" + dc.Port + ")/" + dc.DBName + "?charset=" + dc.Charset + "&parseTime=true&loc=Local"
 }
+
+// GetPostgresDSN 获取PostgreSQL连接字符串，用户名和密码中的特殊字符会被转义
+func (dc *DatabaseConfig) GetPostgresDSN() string {
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(dc.Username, dc.Password),
+		Host:     net.JoinHostPort(dc.Host, dc.Port),
+		Path:     "/" + dc.DBName,
+		RawQuery: url.Values{"sslmode": {dc.SSLMode}}.Encode(),
+	}
+	return dsn.String()
+}
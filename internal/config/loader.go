@@ -0,0 +1,266 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// envPrefix 环境变量前缀，配置项database.host对应环境变量BLOG_DATABASE_HOST
+	envPrefix = "BLOG_"
+	// envConfigFile 指定配置文件路径的环境变量，命令行参数-config优先
+	envConfigFile = "BLOG_CONFIG"
+	// fileSuffix 密钥类配置项加上该后缀后表示从文件读取密钥，如database.password_file
+	fileSuffix = "_file"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Flags 命令行参数中与配置项无关的选项
+type Flags struct {
+	// File 配置文件路径
+	File string
+	// PrintConfig 为true时打印生效的配置后退出
+	PrintConfig bool
+	// Args 子命令及其参数，如["migrate", "up"]，为空时启动服务
+	Args []string
+}
+
+// field 配置项，value为配置结构体中对应字段的可写引用
+type field struct {
+	key    string
+	secret bool
+	value  reflect.Value
+}
+
+// Load 加载配置，优先级从低到高依次为默认值、配置文件、环境变量、命令行参数
+// 配置文件由命令行参数-config或环境变量BLOG_CONFIG指定，按扩展名解析YAML或TOML
+// 选项之前和之后的非选项参数依次作为子命令放入Flags.Args，如"migrate up -config c.yaml"
+// Load不校验配置，调用方需在使用前调用Config.Validate
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, *Flags, error) {
+	cfg := NewConfig()
+	fields := cfg.fields()
+	index := make(map[string]field, len(fields))
+	var keys []string
+	for _, f := range fields {
+		index[f.key] = f
+		keys = append(keys, f.key)
+		if f.secret {
+			keys = append(keys, f.key+fileSuffix)
+		}
+	}
+
+	flags := &Flags{}
+	fs := flag.NewFlagSet("blog", flag.ContinueOnError)
+	fs.StringVar(&flags.File, "config", "", "配置文件路径，支持.yaml、.yml、.toml，也可通过环境变量"+envConfigFile+"指定")
+	fs.BoolVar(&flags.PrintConfig, "print-config", false, "打印生效的配置后退出，密钥已脱敏")
+	var overrides [][2]string
+	for _, key := range keys {
+		key := key
+		fs.Func(key, "配置项"+key, func(value string) error {
+			overrides = append(overrides, [2]string{key, value})
+			return nil
+		})
+	}
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		flags.Args = append(flags.Args, args[0])
+		args = args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	flags.Args = append(flags.Args, fs.Args()...)
+
+	if flags.File == "" {
+		flags.File, _ = lookupEnv(envConfigFile)
+	}
+	if flags.File != "" {
+		if err := loadFile(flags.File, index); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, key := range keys {
+		name := envName(key)
+		if value, ok := lookupEnv(name); ok {
+			if err := set(index, key, value); err != nil {
+				return nil, nil, fmt.Errorf("环境变量 %s: %w", name, err)
+			}
+		}
+	}
+
+	for _, override := range overrides {
+		if err := set(index, override[0], override[1]); err != nil {
+			return nil, nil, fmt.Errorf("命令行参数 -%s: %w", override[0], err)
+		}
+	}
+
+	return cfg, flags, nil
+}
+
+// envName 返回配置项对应的环境变量名
+func envName(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// fields 按结构体字段顺序列出所有配置项
+func (c *Config) fields() []field {
+	return collectFields(reflect.ValueOf(c).Elem(), "", nil)
+}
+
+// collectFields 递归收集带config标签的字段，嵌套结构体的配置项名称以"."连接
+func collectFields(v reflect.Value, prefix string, out []field) []field {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("config")
+		if tag == "" {
+			continue
+		}
+		name, option, _ := strings.Cut(tag, ",")
+		key := prefix + name
+		if v.Field(i).Kind() == reflect.Struct {
+			out = collectFields(v.Field(i), key+".", out)
+			continue
+		}
+		out = append(out, field{key: key, secret: option == "secret", value: v.Field(i)})
+	}
+	return out
+}
+
+// loadFile 读取配置文件并应用其中的配置项
+func loadFile(path string, index map[string]field) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	values := make(map[string]any)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return fmt.Errorf("不支持的配置文件格式%q: %s", ext, path)
+	}
+	if err != nil {
+		return fmt.Errorf("解析配置文件 %s 失败: %w", path, err)
+	}
+
+	if err := applyValues(index, "", values); err != nil {
+		return fmt.Errorf("配置文件 %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyValues 将配置文件解析出的嵌套表展开为配置项并赋值，按名称排序使<名称>_file总在<名称>之后生效
+func applyValues(index map[string]field, prefix string, values map[string]any) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		key := prefix + name
+		switch value := values[name].(type) {
+		case map[string]any:
+			if err := applyValues(index, key+".", value); err != nil {
+				return err
+			}
+		case []any:
+			f, ok := index[key]
+			if !ok || f.value.Kind() != reflect.Slice {
+				return fmt.Errorf("%s: 不是列表类型的配置项", key)
+			}
+			items := make([]string, 0, len(value))
+			for _, item := range value {
+				items = append(items, fmt.Sprint(item))
+			}
+			f.value.Set(reflect.ValueOf(items))
+		case nil:
+			if err := set(index, key, ""); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		default:
+			if err := set(index, key, fmt.Sprint(value)); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// set 按字符串为配置项赋值，<名称>_file形式的密钥配置项读取文件内容作为密钥
+func set(index map[string]field, key, raw string) error {
+	if f, ok := index[key]; ok {
+		return f.set(raw)
+	}
+	if name, ok := strings.CutSuffix(key, fileSuffix); ok {
+		if f, ok := index[name]; ok && f.secret {
+			data, err := os.ReadFile(raw)
+			if err != nil {
+				return fmt.Errorf("读取密钥文件失败: %w", err)
+			}
+			f.value.SetString(strings.TrimRight(string(data), "\r\n"))
+			return nil
+		}
+	}
+	return errors.New("未知的配置项")
+}
+
+// set 将字符串解析为字段类型后赋值，列表使用逗号分隔
+func (f field) set(raw string) error {
+	switch f.value.Kind() {
+	case reflect.String:
+		f.value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("无效的布尔值%q", raw)
+		}
+		f.value.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		if f.value.Type() == durationType {
+			d, err := time.ParseDuration(raw)
+			if err != nil {
+				return fmt.Errorf("无效的时长%q，需要带单位，如30s、5m、24h", raw)
+			}
+			f.value.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("无效的整数%q", raw)
+		}
+		f.value.SetInt(n)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("无效的数值%q", raw)
+		}
+		f.value.SetFloat(n)
+	case reflect.Slice:
+		items := []string{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		f.value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("不支持的配置项类型%s", f.value.Type())
+	}
+	return nil
+}
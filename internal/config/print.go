@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted 打印配置时替换非空密钥的占位符
+const redacted = "******"
+
+// Print 以YAML格式输出配置，非空的密钥替换为占位符，输出可直接作为配置文件使用
+func (c *Config) Print(w io.Writer) error {
+	node, err := redactedNode(reflect.ValueOf(c).Elem())
+	if err != nil {
+		return fmt.Errorf("生成配置失败: %w", err)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return fmt.Errorf("输出配置失败: %w", err)
+	}
+	return encoder.Close()
+}
+
+// redactedNode 按字段顺序将配置结构体转换为YAML节点，时长输出为带单位的字符串
+func redactedNode(v reflect.Value) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("config")
+		if tag == "" {
+			continue
+		}
+		name, option, _ := strings.Cut(tag, ",")
+
+		var value *yaml.Node
+		var err error
+		switch fv := v.Field(i); {
+		case fv.Kind() == reflect.Struct:
+			value, err = redactedNode(fv)
+		case option == "secret" && fv.String() != "":
+			value, err = encodeNode(redacted)
+		case fv.Type() == durationType:
+			value, err = encodeNode(time.Duration(fv.Int()).String())
+		default:
+			value, err = encodeNode(fv.Interface())
+		}
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, value)
+	}
+	return node, nil
+}
+
+// encodeNode 将值编码为YAML节点
+func encodeNode(v any) (*yaml.Node, error) {
+	node := &yaml.Node{}
+	if err := node.Encode(v); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
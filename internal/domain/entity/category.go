@@ -1,16 +1,54 @@
 package entity
 
+import "time"
+
 // Category 分类实体
 type Category struct {
 	ID          uint   `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	// ParentID 父分类ID，为空表示顶级分类
+	ParentID *uint `json:"parent_id"`
+	// Version 乐观锁版本号，每次保存加1
+	Version uint `json:"version"`
+	// DeletedAt 移入回收站的时间，为空表示未删除
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 // NewCategory 创建新分类
-func NewCategory(name, description string) *Category {
+func NewCategory(name, description string, parentID *uint) *Category {
 	return &Category{
 		Name:        name,
 		Description: description,
+		ParentID:    parentID,
+		Version:     1,
+	}
+}
+
+// CategoryNode 分类树节点
+type CategoryNode struct {
+	Category *Category
+	Children []*CategoryNode
+}
+
+// BuildCategoryTree 将分类列表组装为分类树，保持列表中的相对顺序
+// 父分类不在列表中的分类作为顶级节点返回
+func BuildCategoryTree(categories []*Category) []*CategoryNode {
+	nodes := make(map[uint]*CategoryNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &CategoryNode{Category: category}
+	}
+
+	var roots []*CategoryNode
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if category.ParentID != nil {
+			if parent, ok := nodes[*category.ParentID]; ok && parent != node {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
 	}
+	return roots
 }
@@ -4,21 +4,100 @@ import (
 	"time"
 )
 
+// CommentStatus 评论审核状态
+type CommentStatus string
+
+const (
+	// CommentStatusPending 待审核，仅评论者本人和审核者可见
+	CommentStatusPending CommentStatus = "pending"
+	// CommentStatusApproved 已通过，所有人可见
+	CommentStatusApproved CommentStatus = "approved"
+	// CommentStatusSpam 垃圾评论
+	CommentStatusSpam CommentStatus = "spam"
+	// CommentStatusRejected 已拒绝
+	CommentStatusRejected CommentStatus = "rejected"
+)
+
+// IsValid 判断状态是否合法
+func (s CommentStatus) IsValid() bool {
+	switch s {
+	case CommentStatusPending, CommentStatusApproved, CommentStatusSpam, CommentStatusRejected:
+		return true
+	}
+	return false
+}
+
 // Comment 评论实体
 type Comment struct {
-	ID        uint      `json:"id"`
-	PostID    uint      `json:"post_id"`
-	Content   string    `json:"content"`
-	Author    string    `json:"author"`
+	ID     uint `json:"id"`
+	PostID uint `json:"post_id"`
+	// ParentID 回复的评论ID，为空表示顶级评论
+	ParentID *uint `json:"parent_id"`
+	// Depth 嵌套层级，顶级评论为0
+	Depth   int           `json:"depth"`
+	Content string        `json:"content"`
+	Author  string        `json:"author"`
+	Status  CommentStatus `json:"status"`
+	// AuthorIP 评论者IP，用于垃圾评论检测，不对外展示
+	AuthorIP string `json:"-"`
+	// UserAgent 评论者的浏览器标识，用于垃圾评论检测，不对外展示
+	UserAgent string    `json:"-"`
 	CreatedAt time.Time `json:"created_at"`
+	// DeletedAt 移入回收站的时间，为空表示未删除
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
-// NewComment 创建新评论
+// NewComment 创建新评论，新评论默认待审核
 func NewComment(postID uint, content, author string) *Comment {
 	return &Comment{
 		PostID:    postID,
 		Content:   content,
 		Author:    author,
+		Status:    CommentStatusPending,
 		CreatedAt: time.Now(),
 	}
 }
+
+// IsApproved 是否已通过审核
+func (c *Comment) IsApproved() bool {
+	return c.Status == CommentStatusApproved
+}
+
+// NewReply 创建对评论的回复
+func NewReply(parent *Comment, content, author string) *Comment {
+	reply := NewComment(parent.PostID, content, author)
+	reply.ParentID = &parent.ID
+	reply.Depth = parent.Depth + 1
+	return reply
+}
+
+// CommentNode 评论树节点
+type CommentNode struct {
+	Comment *Comment
+	Replies []*CommentNode
+}
+
+// BuildCommentTree 将顶级评论和它们的回复组装为评论树
+// 回复按传入顺序挂到父评论下，找不到父评论的回复会被忽略
+func BuildCommentTree(roots, replies []*Comment) []*CommentNode {
+	nodes := make(map[uint]*CommentNode, len(roots)+len(replies))
+	result := make([]*CommentNode, 0, len(roots))
+	for _, comment := range roots {
+		node := &CommentNode{Comment: comment}
+		nodes[comment.ID] = node
+		result = append(result, node)
+	}
+	for _, comment := range replies {
+		nodes[comment.ID] = &CommentNode{Comment: comment}
+	}
+
+	for _, comment := range replies {
+		if comment.ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*comment.ParentID]; ok {
+			parent.Replies = append(parent.Replies, nodes[comment.ID])
+		}
+	}
+	return result
+}
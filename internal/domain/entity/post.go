@@ -1,22 +1,58 @@
 package entity
 
 import (
+	"fmt"
 	"time"
+
+	"blog/pkg/utils"
 )
 
+// PostStatus 文章状态
+type PostStatus string
+
+const (
+	// PostStatusDraft 草稿，仅作者和编辑可见
+	PostStatusDraft PostStatus = "draft"
+	// PostStatusPublished 已发布，所有人可见
+	PostStatusPublished PostStatus = "published"
+	// PostStatusScheduled 定时发布，到达发布时间前仅作者和编辑可见
+	PostStatusScheduled PostStatus = "scheduled"
+	// PostStatusArchived 已归档，不再出现在公开列表中
+	PostStatusArchived PostStatus = "archived"
+)
+
+// ErrInvalidSchedule 定时发布时间必须晚于当前时间
+var ErrInvalidSchedule = fmt.Errorf("定时发布时间必须晚于当前时间: %w", utils.ErrValidation)
+
+// IsValid 判断状态是否合法
+func (s PostStatus) IsValid() bool {
+	switch s {
+	case PostStatusDraft, PostStatusPublished, PostStatusScheduled, PostStatusArchived:
+		return true
+	}
+	return false
+}
+
 // Post 博客文章实体
 type Post struct {
-	ID        uint      `json:"id"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content"`
-	Author    string    `json:"author"`
-	TitleURL  string    `json:"title_url"`
-	ViewCount uint      `json:"view_count"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Content     string     `json:"content"`
+	Author      string     `json:"author"`
+	TitleURL    string     `json:"title_url"`
+	Slug        string     `json:"slug"`
+	ViewCount   uint       `json:"view_count"`
+	Status      PostStatus `json:"status"`
+	PublishedAt *time.Time `json:"published_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	// Version 乐观锁版本号，每次保存加1
+	Version uint `json:"version"`
+	// DeletedAt 移入回收站的时间，为空表示未删除
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
-// NewPost 创建新文章
+// NewPost 创建新文章，新文章默认为草稿
 func NewPost(title, content, author, titleURL string) *Post {
 	now := time.Now()
 	return &Post{
@@ -25,8 +61,10 @@ func NewPost(title, content, author, titleURL string) *Post {
 		Author:    author,
 		TitleURL:  titleURL,
 		ViewCount: 0,
+		Status:    PostStatusDraft,
 		CreatedAt: now,
 		UpdatedAt: now,
+		Version:   1,
 	}
 }
 
@@ -37,3 +75,41 @@ func (p *Post) Update(title, content, titleURL string) {
 	p.TitleURL = titleURL
 	p.UpdatedAt = time.Now()
 }
+
+// IsPublished 是否已发布
+func (p *Post) IsPublished() bool {
+	return p.Status == PostStatusPublished
+}
+
+// Publish 立即发布文章，已有发布时间的文章保留原发布时间
+func (p *Post) Publish(now time.Time) {
+	if p.PublishedAt == nil || (p.Status == PostStatusScheduled && p.PublishedAt.After(now)) {
+		p.PublishedAt = &now
+	}
+	p.Status = PostStatusPublished
+	p.UpdatedAt = now
+}
+
+// Unpublish 撤回为草稿
+func (p *Post) Unpublish(now time.Time) {
+	p.Status = PostStatusDraft
+	p.PublishedAt = nil
+	p.UpdatedAt = now
+}
+
+// Schedule 设置定时发布
+func (p *Post) Schedule(at, now time.Time) error {
+	if !at.After(now) {
+		return ErrInvalidSchedule
+	}
+	p.Status = PostStatusScheduled
+	p.PublishedAt = &at
+	p.UpdatedAt = now
+	return nil
+}
+
+// Archive 归档文章
+func (p *Post) Archive(now time.Time) {
+	p.Status = PostStatusArchived
+	p.UpdatedAt = now
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+)
+
+// PostRevision 文章修订版本，每次保存文章时记录标题和内容的快照
+type PostRevision struct {
+	ID     uint `json:"id"`
+	PostID uint `json:"post_id"`
+	// Revision 文章内的修订版本号，从1开始递增
+	Revision int    `json:"revision"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+	TitleURL string `json:"title_url"`
+	// Author 保存该版本的用户
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewPostRevision 根据文章当前的标题和内容创建修订版本
+func NewPostRevision(post *Post, author string) *PostRevision {
+	return &PostRevision{
+		PostID:    post.ID,
+		Title:     post.Title,
+		Content:   post.Content,
+		TitleURL:  post.TitleURL,
+		Author:    author,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+)
+
+// SlugRedirect 文章的历史slug，访问旧slug时重定向到文章当前的slug
+type SlugRedirect struct {
+	ID        uint      `json:"id"`
+	OldSlug   string    `json:"old_slug"`
+	PostID    uint      `json:"post_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewSlugRedirect 创建slug重定向记录
+func NewSlugRedirect(oldSlug string, postID uint) *SlugRedirect {
+	return &SlugRedirect{
+		OldSlug:   oldSlug,
+		PostID:    postID,
+		CreatedAt: time.Now(),
+	}
+}
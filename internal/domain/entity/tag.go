@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxTagNameLength 标签名的最大字符数
+const MaxTagNameLength = 50
+
+// Tag 标签实体，与分类不同，标签可由作者自由创建
+type Tag struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewTag 创建新标签
+func NewTag(name string) *Tag {
+	return &Tag{
+		Name:      NormalizeTagName(name),
+		CreatedAt: time.Now(),
+	}
+}
+
+// NormalizeTagName 规范化标签名：去除首尾空白，合并连续空白，超长部分截断
+func NormalizeTagName(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if utf8.RuneCountInString(name) > MaxTagNameLength {
+		name = strings.TrimSpace(string([]rune(name)[:MaxTagNameLength]))
+	}
+	return name
+}
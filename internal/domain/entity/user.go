@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+)
+
+// Role 用户角色
+type Role string
+
+const (
+	// RoleAdmin 管理员，拥有全部权限
+	RoleAdmin Role = "admin"
+	// RoleEditor 编辑，可以编辑任何文章并审核评论
+	RoleEditor Role = "editor"
+	// RoleAuthor 作者，只能管理自己的文章
+	RoleAuthor Role = "author"
+	// RoleReader 读者，只能发表评论
+	RoleReader Role = "reader"
+)
+
+// IsValid 判断角色是否合法
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleAdmin, RoleEditor, RoleAuthor, RoleReader:
+		return true
+	}
+	return false
+}
+
+// User 用户实体
+type User struct {
+	ID           uint      `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	Role         Role      `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewUser 创建新用户
+func NewUser(username, passwordHash string, role Role) *User {
+	return &User{
+		Username:     username,
+		PasswordHash: passwordHash,
+		Role:         role,
+		CreatedAt:    time.Now(),
+	}
+}
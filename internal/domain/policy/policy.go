@@ -0,0 +1,190 @@
+package policy
+
+import (
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+// ErrForbidden 没有权限，与utils.ErrForbidden为同一错误，由错误处理中间件映射为403
+var ErrForbidden = utils.ErrForbidden
+
+// Actor 当前操作者
+type Actor struct {
+	UserID   uint
+	Username string
+	Role     entity.Role
+}
+
+// IsModerator 是否为评论审核者（管理员或编辑）
+func (a *Actor) IsModerator() bool {
+	return a != nil && (a.Role == entity.RoleAdmin || a.Role == entity.RoleEditor)
+}
+
+// Policy 权限策略，由领域服务在执行写操作前调用
+type Policy struct{}
+
+// NewPolicy 创建权限策略
+func NewPolicy() *Policy {
+	return &Policy{}
+}
+
+// CanCreatePost 作者、编辑和管理员可以发表文章
+func (p *Policy) CanCreatePost(actor *Actor) error {
+	if actor == nil {
+		return ErrForbidden
+	}
+	switch actor.Role {
+	case entity.RoleAdmin, entity.RoleEditor, entity.RoleAuthor:
+		return nil
+	}
+	return ErrForbidden
+}
+
+// CanUpdatePost 编辑和管理员可以修改任何文章，作者只能修改自己的文章
+func (p *Policy) CanUpdatePost(actor *Actor, post *entity.Post) error {
+	if actor == nil {
+		return ErrForbidden
+	}
+	switch actor.Role {
+	case entity.RoleAdmin, entity.RoleEditor:
+		return nil
+	case entity.RoleAuthor:
+		if post.Author == actor.Username {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
+
+// CanDeletePost 删除文章与修改文章的规则相同
+func (p *Policy) CanDeletePost(actor *Actor, post *entity.Post) error {
+	return p.CanUpdatePost(actor, post)
+}
+
+// CanViewPost 已发布的文章所有人可见，其余状态仅作者本人、编辑和管理员可见
+func (p *Policy) CanViewPost(actor *Actor, post *entity.Post) error {
+	if post.IsPublished() {
+		return nil
+	}
+	if actor == nil {
+		return ErrForbidden
+	}
+	switch actor.Role {
+	case entity.RoleAdmin, entity.RoleEditor:
+		return nil
+	case entity.RoleAuthor:
+		if post.Author == actor.Username {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
+
+// ScopePostQuery 根据操作者限制文章列表的可见范围
+// 未指定状态时只返回已发布文章；查询其他状态时，作者只能看到自己的文章，读者和匿名用户无权查询
+func (p *Policy) ScopePostQuery(actor *Actor, query *repository.PostQuery) error {
+	if query.Status == "" {
+		query.Status = entity.PostStatusPublished
+	}
+	if query.Status == entity.PostStatusPublished {
+		return nil
+	}
+	if actor == nil {
+		return ErrForbidden
+	}
+	switch actor.Role {
+	case entity.RoleAdmin, entity.RoleEditor:
+		return nil
+	case entity.RoleAuthor:
+		query.Author = actor.Username
+		return nil
+	}
+	return ErrForbidden
+}
+
+// ScopeTrashQuery 根据操作者限制文章回收站的可见范围
+// 编辑和管理员可以查看所有文章，作者只能查看自己的文章，读者和匿名用户无权查看
+func (p *Policy) ScopeTrashQuery(actor *Actor, query *repository.TrashQuery) error {
+	if actor == nil {
+		return ErrForbidden
+	}
+	switch actor.Role {
+	case entity.RoleAdmin, entity.RoleEditor:
+		return nil
+	case entity.RoleAuthor:
+		query.Author = actor.Username
+		return nil
+	}
+	return ErrForbidden
+}
+
+// CanCreateComment 任何登录用户都可以发表评论
+func (p *Policy) CanCreateComment(actor *Actor) error {
+	if actor == nil {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// CanDeleteComment 审核者和文章作者可以删除评论
+func (p *Policy) CanDeleteComment(actor *Actor, post *entity.Post) error {
+	if actor == nil {
+		return ErrForbidden
+	}
+	if actor.IsModerator() || post.Author == actor.Username {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// CanViewComment 已通过审核的评论所有人可见，其余状态仅评论者本人和审核者可见
+func (p *Policy) CanViewComment(actor *Actor, comment *entity.Comment) error {
+	if comment.IsApproved() {
+		return nil
+	}
+	if actor != nil && (actor.IsModerator() || comment.Author == actor.Username) {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// CanModerateComments 编辑和管理员可以审核评论
+func (p *Policy) CanModerateComments(actor *Actor) error {
+	if actor == nil || !actor.IsModerator() {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// CanManageCategories 只有管理员可以管理分类
+func (p *Policy) CanManageCategories(actor *Actor) error {
+	if actor == nil || actor.Role != entity.RoleAdmin {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// CanManageSlugRedirects 编辑和管理员可以管理slug重定向
+func (p *Policy) CanManageSlugRedirects(actor *Actor) error {
+	if actor == nil || !actor.IsModerator() {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// CanRebuildSearchIndex 只有管理员可以重建搜索索引
+func (p *Policy) CanRebuildSearchIndex(actor *Actor) error {
+	if actor == nil || actor.Role != entity.RoleAdmin {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// CanManageUsers 只有管理员可以管理用户角色
+func (p *Policy) CanManageUsers(actor *Actor) error {
+	if actor == nil || actor.Role != entity.RoleAdmin {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"blog/internal/domain/entity"
 )
 
@@ -8,14 +10,22 @@ import (
 type CategoryRepository interface {
 	// Create 创建分类
 	Create(category *entity.Category) error
-	// GetByID 根据ID获取分类
+	// GetByID 根据ID获取分类，除回收站相关方法外，所有查询都不包含回收站中的分类
 	GetByID(id uint) (*entity.Category, error)
 	// GetAll 获取所有分类
 	GetAll() ([]*entity.Category, error)
-	// Update 更新分类
+	// Update 更新分类，分类的版本号与数据库中不一致时返回ErrVersionConflict，成功后版本号加1
 	Update(category *entity.Category) error
-	// Delete 删除分类
+	// Delete 将分类移入回收站，回收站中的分类仍占用分类名称
 	Delete(id uint) error
+	// GetTrashedByID 根据ID获取回收站中的分类
+	GetTrashedByID(id uint) (*entity.Category, error)
+	// ListTrashed 分页获取回收站中的分类，同时返回总数
+	ListTrashed(query *TrashQuery) ([]*entity.Category, int64, error)
+	// Restore 将分类移出回收站
+	Restore(id uint) error
+	// PurgeTrashed 永久删除在before之前移入回收站的分类，子分类成为顶级分类，返回删除的数量
+	PurgeTrashed(before time.Time) (int64, error)
 	// AddPostToCategory 将文章添加到分类
 	AddPostToCategory(postID, categoryID uint) error
 	// RemovePostFromCategory 从分类中移除文章
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"blog/internal/domain/entity"
+)
+
+// CommentQuery 评论审核列表查询条件
+type CommentQuery struct {
+	Page     int
+	PageSize int
+	// Status 评论状态，为空时不限状态
+	Status entity.CommentStatus
+	// PostID 文章ID，为0时不限文章
+	PostID uint
+}
+
+// Normalize 补全默认值并修正越界参数
+func (q *CommentQuery) Normalize() {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize < 1 {
+		q.PageSize = DefaultPageSize
+	}
+	if q.PageSize > MaxPageSize {
+		q.PageSize = MaxPageSize
+	}
+}
+
+// Offset 计算分页偏移量
+func (q *CommentQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"blog/internal/domain/entity"
 )
 
@@ -8,10 +10,28 @@ import (
 type CommentRepository interface {
 	// Create 创建评论
 	Create(comment *entity.Comment) error
-	// GetByID 根据ID获取评论
+	// GetByID 根据ID获取评论，除回收站相关方法外，所有查询都不包含回收站中的评论
 	GetByID(id uint) (*entity.Comment, error)
-	// GetByPostID 根据文章ID获取评论
-	GetByPostID(postID uint) ([]*entity.Comment, error)
-	// Delete 删除评论
+	// GetByIDs 根据ID批量获取评论，不存在的ID会被忽略
+	GetByIDs(ids []uint) ([]*entity.Comment, error)
+	// GetByPostID 根据文章ID按创建时间倒序获取已通过审核的顶级评论
+	// page为nil时返回全部顶级评论，否则按游标分页并返回翻页方向上是否还有更多数据
+	GetByPostID(postID uint, page *CursorQuery) ([]*entity.Comment, bool, error)
+	// GetReplies 获取指定评论下已通过审核的所有回复（含间接回复），按创建时间正序
+	// 未通过审核的回复及其下的回复均不返回
+	GetReplies(parentIDs []uint) ([]*entity.Comment, error)
+	// List 按条件分页获取评论，按创建时间正序，用于审核队列，不包含回收站中文章的评论
+	List(query *CommentQuery) ([]*entity.Comment, int64, error)
+	// UpdateStatus 批量修改评论状态，回收站中的评论不修改，返回实际修改的数量
+	UpdateStatus(ids []uint, status entity.CommentStatus) (int64, error)
+	// Delete 将评论移入回收站，评论下的回复随之隐藏
 	Delete(id uint) error
+	// GetTrashedByID 根据ID获取回收站中的评论
+	GetTrashedByID(id uint) (*entity.Comment, error)
+	// ListTrashed 分页获取回收站中的评论，同时返回总数
+	ListTrashed(query *TrashQuery) ([]*entity.Comment, int64, error)
+	// Restore 将评论移出回收站
+	Restore(id uint) error
+	// PurgeTrashed 永久删除在before之前移入回收站的评论及其回复，返回删除的数量
+	PurgeTrashed(before time.Time) (int64, error)
 }
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"blog/pkg/utils"
+)
+
+// ErrInvalidCursor 游标格式错误
+var ErrInvalidCursor = fmt.Errorf("无效的分页游标: %w", utils.ErrValidation)
+
+// Cursor 键集分页游标，按(created_at, id)定位一条记录
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uint      `json:"id"`
+	// Backward 为true时表示从该位置向前（较新的方向）翻页
+	Backward bool `json:"b,omitempty"`
+}
+
+// CursorQuery 游标分页参数，Cursor为空时表示第一页
+type CursorQuery struct {
+	Limit  int
+	Cursor *Cursor
+}
+
+// Normalize 补全默认值并修正越界参数
+func (q *CursorQuery) Normalize() {
+	if q.Limit < 1 {
+		q.Limit = DefaultPageSize
+	}
+	if q.Limit > MaxPageSize {
+		q.Limit = MaxPageSize
+	}
+}
+
+// EncodeCursor 将游标编码为不透明字符串
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor 解析游标字符串，空字符串返回nil
+func DecodeCursor(value string) (*Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	cursor := &Cursor{}
+	if err := json.Unmarshal(data, cursor); err != nil || cursor.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return cursor, nil
+}
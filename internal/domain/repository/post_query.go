@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"time"
+
+	"blog/internal/domain/entity"
+)
+
+const (
+	// DefaultPageSize 默认每页数量
+	DefaultPageSize = 10
+	// MaxPageSize 每页最大数量
+	MaxPageSize = 100
+)
+
+// PostSortField 文章排序字段
+type PostSortField string
+
+const (
+	// SortByCreatedAt 按创建时间排序
+	SortByCreatedAt PostSortField = "created_at"
+	// SortByUpdatedAt 按更新时间排序
+	SortByUpdatedAt PostSortField = "updated_at"
+	// SortByViewCount 按阅读量排序
+	SortByViewCount PostSortField = "view_count"
+)
+
+// PostQuery 文章列表查询条件
+type PostQuery struct {
+	Page     int
+	PageSize int
+	// Status 文章状态，为空时不限状态
+	Status     entity.PostStatus
+	Author     string
+	CategoryID uint
+	// IncludeDescendants 按分类筛选时是否包含子孙分类的文章
+	IncludeDescendants bool
+	// Tag 标签名，仅列表查询支持
+	Tag string
+	// CreatedFrom 创建时间下界（包含）
+	CreatedFrom time.Time
+	// CreatedTo 创建时间上界（不包含）
+	CreatedTo time.Time
+	SortBy    PostSortField
+	Ascending bool
+}
+
+// Normalize 补全默认值并修正越界参数
+func (q *PostQuery) Normalize() {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize < 1 {
+		q.PageSize = DefaultPageSize
+	}
+	if q.PageSize > MaxPageSize {
+		q.PageSize = MaxPageSize
+	}
+	switch q.SortBy {
+	case SortByCreatedAt, SortByUpdatedAt, SortByViewCount:
+	default:
+		q.SortBy = SortByCreatedAt
+	}
+}
+
+// Offset 计算分页偏移量
+func (q *PostQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
+}
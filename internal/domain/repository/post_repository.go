@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"blog/internal/domain/entity"
 )
 
@@ -8,14 +10,34 @@ import (
 type PostRepository interface {
 	// Create 创建文章
 	Create(post *entity.Post) error
-	// GetByID 根据ID获取文章
+	// GetByID 根据ID获取文章，除回收站相关方法外，所有查询都不包含回收站中的文章
 	GetByID(id uint) (*entity.Post, error)
+	// GetBySlug 根据slug获取文章
+	GetBySlug(slug string) (*entity.Post, error)
+	// SlugExists 判断slug是否已被其他文章使用，excludeID为需要排除的文章ID，回收站中的文章同样占用slug
+	SlugExists(slug string, excludeID uint) (bool, error)
 	// GetAll 获取所有文章
 	GetAll() ([]*entity.Post, error)
-	// Update 更新文章
+	// Update 更新文章，文章的版本号与数据库中不一致时返回ErrVersionConflict，成功后版本号加1
 	Update(post *entity.Post) error
-	// Delete 删除文章
+	// IncrementViewCount 增加文章阅读量，不修改版本号和更新时间
+	IncrementViewCount(id uint) error
+	// Delete 将文章移入回收站
 	Delete(id uint) error
-	// GetByCategory 根据分类获取文章
-	GetByCategory(categoryID uint) ([]*entity.Post, error)
+	// GetTrashedByID 根据ID获取回收站中的文章
+	GetTrashedByID(id uint) (*entity.Post, error)
+	// ListTrashed 分页获取回收站中的文章（不含正文），同时返回总数
+	ListTrashed(query *TrashQuery) ([]*entity.Post, int64, error)
+	// Restore 将文章移出回收站
+	Restore(id uint) error
+	// PurgeTrashed 永久删除在before之前移入回收站的文章及其评论等关联数据，返回删除的数量
+	PurgeTrashed(before time.Time) (int64, error)
+	// List 按条件分页获取文章列表（不含正文），同时返回总数
+	List(query *PostQuery) ([]*entity.Post, int64, error)
+	// ListByCursor 按创建时间倒序进行游标分页，返回当前页数据以及翻页方向上是否还有更多数据
+	ListByCursor(query *PostQuery, page *CursorQuery) ([]*entity.Post, bool, error)
+	// Search 全文搜索文章，按相关度排序并返回总数
+	Search(query *PostSearchQuery) ([]*PostSearchHit, int64, error)
+	// GetDueScheduled 获取发布时间已到的定时发布文章
+	GetDueScheduled(now time.Time) ([]*entity.Post, error)
 }
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"blog/internal/domain/entity"
+)
+
+// PostRevisionRepository 文章修订版本仓储接口
+type PostRevisionRepository interface {
+	// Create 保存修订版本，版本号为该文章当前最大版本号加1
+	Create(revision *entity.PostRevision) error
+	// GetByRevision 根据文章ID和版本号获取修订版本
+	GetByRevision(postID uint, revision int) (*entity.PostRevision, error)
+	// ListByPostID 获取文章的所有修订版本，按版本号倒序
+	ListByPostID(postID uint) ([]*entity.PostRevision, error)
+}
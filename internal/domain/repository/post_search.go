@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"blog/internal/domain/entity"
+)
+
+// PostSearchQuery 文章全文搜索条件，筛选和分页参数与列表查询相同，结果按相关度排序
+type PostSearchQuery struct {
+	PostQuery
+	Keyword string
+}
+
+// PostSearchHit 文章搜索命中结果
+type PostSearchHit struct {
+	Post *entity.Post
+	// Score 相关度得分，越大越相关
+	Score float64
+}
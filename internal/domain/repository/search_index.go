@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"blog/internal/domain/entity"
+)
+
+// SearchDocument 搜索索引中的文章文档
+type SearchDocument struct {
+	Post *entity.Post
+	// CategoryIDs 文章所属分类，用于按分类筛选
+	CategoryIDs []uint
+}
+
+// SearchIndex 文章搜索索引接口，与数据库存储相互独立
+type SearchIndex interface {
+	// Index 添加或更新文章
+	Index(doc *SearchDocument) error
+	// Remove 从索引中移除文章
+	Remove(postID uint) error
+	// Search 全文搜索文章，按相关度排序并返回总数
+	Search(query *PostSearchQuery) ([]*PostSearchHit, int64, error)
+	// Rebuild 清空索引并写入全部文章
+	Rebuild(docs []*SearchDocument) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"blog/internal/domain/entity"
+)
+
+// SlugRedirectRepository slug重定向仓储接口
+type SlugRedirectRepository interface {
+	// Save 保存重定向，旧slug已存在时改为指向新的文章
+	Save(redirect *entity.SlugRedirect) error
+	// GetByID 根据ID获取重定向
+	GetByID(id uint) (*entity.SlugRedirect, error)
+	// GetBySlug 根据旧slug获取重定向
+	GetBySlug(oldSlug string) (*entity.SlugRedirect, error)
+	// List 获取重定向列表，postID为0时返回全部
+	List(postID uint) ([]*entity.SlugRedirect, error)
+	// Delete 删除重定向
+	Delete(id uint) error
+	// DeleteBySlug 根据旧slug删除重定向
+	DeleteBySlug(oldSlug string) error
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"net/http"
+
+	"blog/internal/domain/entity"
+	"blog/pkg/utils"
+)
+
+// ErrCommentRateLimited 评论过于频繁，映射为429
+var ErrCommentRateLimited = utils.NewAppError(http.StatusTooManyRequests, "rate_limited", "评论过于频繁，请稍后再试", nil)
+
+// SpamCheckInput 垃圾评论检测的输入
+type SpamCheckInput struct {
+	Comment *entity.Comment
+	// Post 评论所属文章
+	Post *entity.Post
+	// Referrer 提交评论时的来源页面
+	Referrer string
+	// Honeypot 蜜罐字段的值，正常用户不会填写
+	Honeypot string
+}
+
+// SpamCheckResult 垃圾评论检测结果
+type SpamCheckResult struct {
+	Spam bool
+	// Reason 判定为垃圾评论的原因，便于审核者排查
+	Reason string
+}
+
+// SpamChecker 垃圾评论检测接口
+type SpamChecker interface {
+	// Check 检测评论是否为垃圾评论，评论过于频繁时返回ErrCommentRateLimited
+	Check(input *SpamCheckInput) (*SpamCheckResult, error)
+	// Learn 根据审核者的判定进行学习，spam为false表示正常评论
+	Learn(input *SpamCheckInput, spam bool) error
+}
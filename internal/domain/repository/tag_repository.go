@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"blog/internal/domain/entity"
+)
+
+// TagCount 标签及其使用次数
+type TagCount struct {
+	Tag *entity.Tag
+	// PostCount 使用该标签的已发布文章数量
+	PostCount int64
+}
+
+// TagRepository 标签仓储接口
+type TagRepository interface {
+	// GetOrCreate 根据名称获取标签，不存在的标签会自动创建
+	GetOrCreate(names []string) ([]*entity.Tag, error)
+	// GetByName 根据名称获取标签
+	GetByName(name string) (*entity.Tag, error)
+	// GetByPostID 获取文章的所有标签
+	GetByPostID(postID uint) ([]*entity.Tag, error)
+	// SetPostTags 替换文章的全部标签
+	SetPostTags(postID uint, tagIDs []uint) error
+	// GetCloud 获取标签云，按使用次数倒序，limit为0时不限数量
+	GetCloud(limit int) ([]*TagCount, error)
+}
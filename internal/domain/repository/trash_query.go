@@ -0,0 +1,27 @@
+package repository
+
+// TrashQuery 回收站列表查询条件，按移入回收站的时间倒序
+type TrashQuery struct {
+	Page     int
+	PageSize int
+	// Author 文章作者，为空时不限，仅文章回收站使用
+	Author string
+}
+
+// Normalize 补全默认值并修正越界参数
+func (q *TrashQuery) Normalize() {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize < 1 {
+		q.PageSize = DefaultPageSize
+	}
+	if q.PageSize > MaxPageSize {
+		q.PageSize = MaxPageSize
+	}
+}
+
+// Offset 计算分页偏移量
+func (q *TrashQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"blog/internal/domain/entity"
+)
+
+// UserRepository 用户仓储接口
+type UserRepository interface {
+	// Create 创建用户，用户名已存在时返回utils.ErrConflict
+	Create(user *entity.User) error
+	// CreateFirst 以固定的ID创建第一个用户，该ID已被占用时返回utils.ErrConflict
+	// 多个请求同时创建第一个用户时只有一个能成功
+	CreateFirst(user *entity.User) error
+	// GetByID 根据ID获取用户
+	GetByID(id uint) (*entity.User, error)
+	// GetByUsername 根据用户名获取用户
+	GetByUsername(username string) (*entity.User, error)
+	// Count 获取用户总数
+	Count() (int64, error)
+	// UpdateRole 更新用户角色
+	UpdateRole(id uint, role entity.Role) error
+}
@@ -0,0 +1,10 @@
+package repository
+
+import (
+	"net/http"
+
+	"blog/pkg/utils"
+)
+
+// ErrVersionConflict 保存时数据的版本号与读取时不一致，说明已被其他请求修改，映射为412
+var ErrVersionConflict = utils.NewAppError(http.StatusPreconditionFailed, "version_conflict", "数据已被其他用户修改，请刷新后重试", nil)
@@ -1,25 +1,46 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
 	"blog/internal/domain/repository"
+	"blog/pkg/utils"
 )
 
+// ErrCategoryCycle 父分类不能是分类自身或其子孙分类
+var ErrCategoryCycle = fmt.Errorf("不能将分类移动到自身或其子分类下: %w", utils.ErrValidation)
+
 // CategoryService 分类领域服务
 type CategoryService struct {
 	categoryRepo repository.CategoryRepository
+	policy       *policy.Policy
 }
 
 // NewCategoryService 创建分类服务
-func NewCategoryService(categoryRepo repository.CategoryRepository) *CategoryService {
+func NewCategoryService(categoryRepo repository.CategoryRepository, policy *policy.Policy) *CategoryService {
 	return &CategoryService{
 		categoryRepo: categoryRepo,
+		policy:       policy,
 	}
 }
 
-// CreateCategory 创建分类
-func (s *CategoryService) CreateCategory(name, description string) (*entity.Category, error) {
-	category := entity.NewCategory(name, description)
+// CreateCategory 创建分类，parentID为空时创建顶级分类
+func (s *CategoryService) CreateCategory(actor *policy.Actor, name, description string, parentID *uint) (*entity.Category, error) {
+	if err := s.policy.CanManageCategories(actor); err != nil {
+		return nil, err
+	}
+
+	if parentID != nil {
+		if _, err := s.categoryRepo.GetByID(*parentID); err != nil {
+			return nil, err
+		}
+	}
+
+	category := entity.NewCategory(name, description, parentID)
 	err := s.categoryRepo.Create(category)
 	if err != nil {
 		return nil, err
@@ -37,15 +58,39 @@ func (s *CategoryService) GetAllCategories() ([]*entity.Category, error) {
 	return s.categoryRepo.GetAll()
 }
 
-// UpdateCategory 更新分类
-func (s *CategoryService) UpdateCategory(id uint, name, description string) (*entity.Category, error) {
+// GetCategoryTree 获取分类树
+func (s *CategoryService) GetCategoryTree() ([]*entity.CategoryNode, error) {
+	categories, err := s.categoryRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return entity.BuildCategoryTree(categories), nil
+}
+
+// UpdateCategory 更新分类，parentID为空时移动为顶级分类
+// version为客户端读取分类时的版本号，与当前版本不一致时返回repository.ErrVersionConflict
+func (s *CategoryService) UpdateCategory(actor *policy.Actor, id, version uint, name, description string, parentID *uint) (*entity.Category, error) {
+	if err := s.policy.CanManageCategories(actor); err != nil {
+		return nil, err
+	}
+
 	category, err := s.categoryRepo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if category.Version != version {
+		return nil, fmt.Errorf("分类 %d 的当前版本为 %d: %w", id, category.Version, repository.ErrVersionConflict)
+	}
+
+	if parentID != nil {
+		if err := s.checkParent(id, *parentID); err != nil {
+			return nil, err
+		}
+	}
 
 	category.Name = name
 	category.Description = description
+	category.ParentID = parentID
 
 	err = s.categoryRepo.Update(category)
 	if err != nil {
@@ -55,11 +100,75 @@ func (s *CategoryService) UpdateCategory(id uint, name, description string) (*en
 	return category, nil
 }
 
-// DeleteCategory 删除分类
-func (s *CategoryService) DeleteCategory(id uint) error {
+// checkParent 沿父分类链向上检查，确保新的父分类不是分类自身或其子孙分类
+// 新的父分类不能在回收站中；更上层的祖先分类在回收站中时继续沿其父分类检查，避免恢复后形成环
+func (s *CategoryService) checkParent(id, parentID uint) error {
+	visited := make(map[uint]struct{})
+	for current := &parentID; current != nil; {
+		if *current == id {
+			return ErrCategoryCycle
+		}
+		// 已有数据中存在环时停止遍历，避免死循环
+		if _, ok := visited[*current]; ok {
+			return ErrCategoryCycle
+		}
+		visited[*current] = struct{}{}
+
+		parent, err := s.categoryRepo.GetByID(*current)
+		if errors.Is(err, utils.ErrNotFound) && *current != parentID {
+			parent, err = s.categoryRepo.GetTrashedByID(*current)
+		}
+		if err != nil {
+			return err
+		}
+		current = parent.ParentID
+	}
+	return nil
+}
+
+// DeleteCategory 将分类移入回收站，子分类会作为顶级分类显示
+func (s *CategoryService) DeleteCategory(actor *policy.Actor, id uint) error {
+	if err := s.policy.CanManageCategories(actor); err != nil {
+		return err
+	}
+
+	if _, err := s.categoryRepo.GetByID(id); err != nil {
+		return err
+	}
 	return s.categoryRepo.Delete(id)
 }
 
+// ListTrashedCategories 分页获取回收站中的分类，仅管理员可用
+func (s *CategoryService) ListTrashedCategories(actor *policy.Actor, query *repository.TrashQuery) ([]*entity.Category, int64, error) {
+	if err := s.policy.CanManageCategories(actor); err != nil {
+		return nil, 0, err
+	}
+	return s.categoryRepo.ListTrashed(query)
+}
+
+// RestoreCategory 将分类移出回收站
+func (s *CategoryService) RestoreCategory(actor *policy.Actor, id uint) (*entity.Category, error) {
+	if err := s.policy.CanManageCategories(actor); err != nil {
+		return nil, err
+	}
+
+	category, err := s.categoryRepo.GetTrashedByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryRepo.Restore(id); err != nil {
+		return nil, err
+	}
+	category.DeletedAt = nil
+	return category, nil
+}
+
+// PurgeTrash 永久删除在before之前移入回收站的分类，返回删除的数量
+func (s *CategoryService) PurgeTrash(before time.Time) (int64, error) {
+	return s.categoryRepo.PurgeTrashed(before)
+}
+
 // GetCategoriesByPostID 获取文章的所有分类
 func (s *CategoryService) GetCategoriesByPostID(postID uint) ([]*entity.Category, error) {
 	return s.categoryRepo.GetCategoriesByPostID(postID)
@@ -1,33 +1,128 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
 	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
 	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+var (
+	// ErrInvalidParentComment 回复的评论不属于同一篇文章
+	ErrInvalidParentComment = fmt.Errorf("回复的评论不属于该文章: %w", utils.ErrValidation)
+	// ErrCommentTooDeep 回复嵌套层级超过上限
+	ErrCommentTooDeep = fmt.Errorf("回复层级超过上限: %w", utils.ErrValidation)
+	// ErrInvalidCommentStatus 无效的评论状态
+	ErrInvalidCommentStatus = fmt.Errorf("无效的评论状态: %w", utils.ErrValidation)
 )
 
+// CommentSettings 评论设置
+type CommentSettings struct {
+	// MaxDepth 回复的最大嵌套层级，顶级评论为第0层
+	MaxDepth int
+	// AutoApprove 为true时新评论直接通过审核，否则进入审核队列
+	AutoApprove bool
+}
+
+// CommentClient 提交评论的客户端信息，用于垃圾评论检测
+type CommentClient struct {
+	IP        string
+	UserAgent string
+	Referrer  string
+	// Honeypot 表单中对用户隐藏的字段，正常用户提交时为空
+	Honeypot string
+}
+
 // CommentService 评论领域服务
 type CommentService struct {
 	commentRepo repository.CommentRepository
 	postRepo    repository.PostRepository
+	policy      *policy.Policy
+	settings    CommentSettings
+	spamChecker repository.SpamChecker
+	// learning 后台进行中的检测器学习任务
+	learning sync.WaitGroup
 }
 
 // NewCommentService 创建评论服务
-func NewCommentService(commentRepo repository.CommentRepository, postRepo repository.PostRepository) *CommentService {
+func NewCommentService(commentRepo repository.CommentRepository, postRepo repository.PostRepository, policy *policy.Policy, settings CommentSettings) *CommentService {
 	return &CommentService{
 		commentRepo: commentRepo,
 		postRepo:    postRepo,
+		policy:      policy,
+		settings:    settings,
 	}
 }
 
-// CreateComment 创建评论
-func (s *CommentService) CreateComment(postID uint, content, author string) (*entity.Comment, error) {
-	// 验证文章是否存在
-	_, err := s.postRepo.GetByID(postID)
+// SetSpamChecker 设置垃圾评论检测器，未设置时不做检测
+func (s *CommentService) SetSpamChecker(checker repository.SpamChecker) {
+	s.spamChecker = checker
+}
+
+// CreateComment 创建评论，作者为当前操作者，parentID不为空时作为对该评论的回复
+// 未开启自动审核时，除审核者外的评论需审核通过后才公开显示，被判定为垃圾的评论直接标记为spam
+func (s *CommentService) CreateComment(actor *policy.Actor, postID uint, parentID *uint, content string, client *CommentClient) (*entity.Comment, error) {
+	if err := s.policy.CanCreateComment(actor); err != nil {
+		return nil, err
+	}
+
+	// 验证文章是否存在，未发布的文章不能评论
+	post, err := s.postRepo.GetByID(postID)
 	if err != nil {
 		return nil, err
 	}
+	if !post.IsPublished() {
+		return nil, fmt.Errorf("文章不存在: %d: %w", postID, utils.ErrNotFound)
+	}
+
+	comment := entity.NewComment(postID, content, actor.Username)
+	if parentID != nil {
+		parent, err := s.commentRepo.GetByID(*parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.PostID != postID || !parent.IsApproved() {
+			return nil, ErrInvalidParentComment
+		}
+		if parent.Depth+1 > s.settings.MaxDepth {
+			return nil, ErrCommentTooDeep
+		}
+		comment = entity.NewReply(parent, content, actor.Username)
+	}
+	if client != nil {
+		comment.AuthorIP = client.IP
+		comment.UserAgent = client.UserAgent
+	}
+	if s.settings.AutoApprove || actor.IsModerator() {
+		comment.Status = entity.CommentStatusApproved
+	}
+
+	// 审核者的评论不做检测
+	if s.spamChecker != nil && !actor.IsModerator() {
+		input := &repository.SpamCheckInput{Comment: comment, Post: post}
+		if client != nil {
+			input.Referrer = client.Referrer
+			input.Honeypot = client.Honeypot
+		}
+		result, err := s.spamChecker.Check(input)
+		switch {
+		case errors.Is(err, repository.ErrCommentRateLimited):
+			return nil, err
+		case err != nil:
+			// 检测服务异常时不阻塞评论，按正常流程处理
+			log.Printf("垃圾评论检测失败: %v", err)
+		case result.Spam:
+			log.Printf("评论被判定为垃圾评论: 文章%d, 作者%s, 原因: %s", postID, actor.Username, result.Reason)
+			comment.Status = entity.CommentStatusSpam
+		}
+	}
 
-	comment := entity.NewComment(postID, content, author)
 	err = s.commentRepo.Create(comment)
 	if err != nil {
 		return nil, err
@@ -35,17 +130,208 @@ func (s *CommentService) CreateComment(postID uint, content, author string) (*en
 	return comment, nil
 }
 
-// GetCommentByID 根据ID获取评论
-func (s *CommentService) GetCommentByID(id uint) (*entity.Comment, error) {
-	return s.commentRepo.GetByID(id)
+// GetCommentByID 根据ID获取评论，未通过审核的评论或所属文章不可见的评论对无权查看的操作者视为不存在
+func (s *CommentService) GetCommentByID(actor *policy.Actor, id uint) (*entity.Comment, error) {
+	comment, err := s.commentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.policy.CanViewComment(actor, comment); err != nil {
+		return nil, fmt.Errorf("评论不存在: %d: %w", id, utils.ErrNotFound)
+	}
+
+	post, err := s.postRepo.GetByID(comment.PostID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.policy.CanViewPost(actor, post); err != nil {
+		return nil, fmt.Errorf("评论不存在: %d: %w", id, utils.ErrNotFound)
+	}
+	return comment, nil
+}
+
+// GetCommentsByPostID 根据文章ID按游标分页获取评论树
+// 分页以顶级评论为单位，每个顶级评论携带其下的全部回复
+// 文章对当前用户不可见时与文章不存在同样处理
+func (s *CommentService) GetCommentsByPostID(actor *policy.Actor, postID uint, page *repository.CursorQuery) ([]*entity.CommentNode, bool, error) {
+	post, err := s.postRepo.GetByID(postID)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.policy.CanViewPost(actor, post); err != nil {
+		return nil, false, fmt.Errorf("文章不存在: %d: %w", postID, utils.ErrNotFound)
+	}
+
+	roots, hasMore, err := s.commentRepo.GetByPostID(postID, page)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var replies []*entity.Comment
+	if len(roots) > 0 {
+		rootIDs := make([]uint, 0, len(roots))
+		for _, root := range roots {
+			rootIDs = append(rootIDs, root.ID)
+		}
+		replies, err = s.commentRepo.GetReplies(rootIDs)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return entity.BuildCommentTree(roots, replies), hasMore, nil
+}
+
+// ListComments 分页获取审核队列中的评论，仅审核者可用，未指定状态时返回待审核评论
+func (s *CommentService) ListComments(actor *policy.Actor, query *repository.CommentQuery) ([]*entity.Comment, int64, error) {
+	if err := s.policy.CanModerateComments(actor); err != nil {
+		return nil, 0, err
+	}
+
+	if query.Status == "" {
+		query.Status = entity.CommentStatusPending
+	}
+	if !query.Status.IsValid() {
+		return nil, 0, ErrInvalidCommentStatus
+	}
+	return s.commentRepo.List(query)
+}
+
+// ModerateComments 批量修改评论的审核状态，返回实际修改的数量
+func (s *CommentService) ModerateComments(actor *policy.Actor, ids []uint, status entity.CommentStatus) (int64, error) {
+	if err := s.policy.CanModerateComments(actor); err != nil {
+		return 0, err
+	}
+	if !status.IsValid() {
+		return 0, ErrInvalidCommentStatus
+	}
+
+	// 审核者将评论标记为垃圾或通过时，检测器从中学习
+	var learnFrom []*entity.Comment
+	if s.spamChecker != nil && (status == entity.CommentStatusSpam || status == entity.CommentStatusApproved) {
+		comments, err := s.commentRepo.GetByIDs(ids)
+		if err != nil {
+			return 0, err
+		}
+		for _, comment := range comments {
+			if comment.Status != status {
+				learnFrom = append(learnFrom, comment)
+			}
+		}
+	}
+
+	updated, err := s.commentRepo.UpdateStatus(ids, status)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(learnFrom) > 0 {
+		s.learnInBackground(s.spamChecker, learnFrom, status == entity.CommentStatusSpam)
+	}
+	return updated, nil
 }
 
-// GetCommentsByPostID 根据文章ID获取评论
-func (s *CommentService) GetCommentsByPostID(postID uint) ([]*entity.Comment, error) {
-	return s.commentRepo.GetByPostID(postID)
+// learnInBackground 在后台将审核结果逐条反馈给检测器，外部检测服务较慢时不阻塞审核请求，失败时只记录日志
+func (s *CommentService) learnInBackground(checker repository.SpamChecker, comments []*entity.Comment, spam bool) {
+	s.learning.Add(1)
+	go func() {
+		defer s.learning.Done()
+
+		for _, comment := range comments {
+			input := &repository.SpamCheckInput{Comment: comment}
+			if err := checker.Learn(input, spam); err != nil {
+				log.Printf("垃圾评论检测器学习失败: 评论%d: %v", comment.ID, err)
+			}
+		}
+	}()
 }
 
-// DeleteComment 删除评论
-func (s *CommentService) DeleteComment(id uint) error {
+// WaitLearning 等待后台的检测器学习任务全部完成，关闭服务前调用
+func (s *CommentService) WaitLearning() {
+	s.learning.Wait()
+}
+
+// TrainSpamChecker 用历史审核结果训练指定的垃圾评论检测器，垃圾评论和已通过的评论各取最早的limit条
+// 外部检测服务通常无需重复提交历史数据，因此只训练传入的检测器，返回用于训练的评论数量
+func (s *CommentService) TrainSpamChecker(checker repository.SpamChecker, limit int) (int, error) {
+	trained := 0
+	for _, status := range []entity.CommentStatus{entity.CommentStatusSpam, entity.CommentStatusApproved} {
+		learned := 0
+		for page := 1; learned < limit; page++ {
+			query := &repository.CommentQuery{Page: page, PageSize: repository.MaxPageSize, Status: status}
+			comments, _, err := s.commentRepo.List(query)
+			if err != nil {
+				return trained, err
+			}
+			for _, comment := range comments {
+				if learned == limit {
+					break
+				}
+				if err := checker.Learn(&repository.SpamCheckInput{Comment: comment}, status == entity.CommentStatusSpam); err != nil {
+					return trained, fmt.Errorf("训练垃圾评论检测器失败: %w", err)
+				}
+				learned++
+				trained++
+			}
+			if len(comments) < query.PageSize {
+				break
+			}
+		}
+	}
+	return trained, nil
+}
+
+// DeleteComment 将评论移入回收站，其下的回复随之隐藏，仅审核者和文章作者可以删除
+func (s *CommentService) DeleteComment(actor *policy.Actor, id uint) error {
+	comment, err := s.commentRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	post, err := s.postRepo.GetByID(comment.PostID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.policy.CanDeleteComment(actor, post); err != nil {
+		return err
+	}
+
 	return s.commentRepo.Delete(id)
 }
+
+// ListTrashedComments 分页获取回收站中的评论，仅审核者可用
+func (s *CommentService) ListTrashedComments(actor *policy.Actor, query *repository.TrashQuery) ([]*entity.Comment, int64, error) {
+	if err := s.policy.CanModerateComments(actor); err != nil {
+		return nil, 0, err
+	}
+	return s.commentRepo.ListTrashed(query)
+}
+
+// RestoreComment 将评论移出回收站，权限与删除评论相同
+func (s *CommentService) RestoreComment(actor *policy.Actor, id uint) (*entity.Comment, error) {
+	comment, err := s.commentRepo.GetTrashedByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := s.postRepo.GetByID(comment.PostID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanDeleteComment(actor, post); err != nil {
+		return nil, err
+	}
+
+	if err := s.commentRepo.Restore(id); err != nil {
+		return nil, err
+	}
+	comment.DeletedAt = nil
+	return comment, nil
+}
+
+// PurgeTrash 永久删除在before之前移入回收站的评论，返回删除的数量
+func (s *CommentService) PurgeTrash(before time.Time) (int64, error) {
+	return s.commentRepo.PurgeTrashed(before)
+}
@@ -1,87 +1,601 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
 	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
 	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+// maxSlugSuffix 自动生成slug时尝试的最大后缀序号
+const maxSlugSuffix = 1000
+
+var (
+	// ErrEmptyKeyword 搜索关键词为空
+	ErrEmptyKeyword = fmt.Errorf("搜索关键词不能为空: %w", utils.ErrValidation)
+	// ErrSearchIndexDisabled 未启用独立搜索索引
+	ErrSearchIndexDisabled = fmt.Errorf("未启用独立搜索索引: %w", utils.ErrValidation)
+	// ErrInvalidSlug slug不包含任何可用字符
+	ErrInvalidSlug = fmt.Errorf("无效的slug: %w", utils.ErrValidation)
+	// ErrSlugTaken slug已被其他文章使用
+	ErrSlugTaken = fmt.Errorf("slug已被使用: %w", utils.ErrConflict)
 )
 
+// SlugMovedError 访问的slug已停用，文章现在使用新的slug
+type SlugMovedError struct {
+	Slug string
+}
+
+// Error 实现error接口
+func (e *SlugMovedError) Error() string {
+	return fmt.Sprintf("文章已迁移至新的slug: %s", e.Slug)
+}
+
+// RevisionDiff 两个修订版本之间的差异
+type RevisionDiff struct {
+	From *entity.PostRevision
+	To   *entity.PostRevision
+	// Lines 正文的行级差异
+	Lines []utils.DiffLine
+}
+
 // PostService 文章领域服务
 type PostService struct {
 	postRepo     repository.PostRepository
 	categoryRepo repository.CategoryRepository
+	redirectRepo repository.SlugRedirectRepository
+	revisionRepo repository.PostRevisionRepository
+	policy       *policy.Policy
+	// searchIndex 独立搜索索引，为空时使用仓储自带的全文搜索
+	searchIndex repository.SearchIndex
 }
 
 // NewPostService 创建文章服务
-func NewPostService(postRepo repository.PostRepository, categoryRepo repository.CategoryRepository) *PostService {
+func NewPostService(postRepo repository.PostRepository, categoryRepo repository.CategoryRepository, redirectRepo repository.SlugRedirectRepository, revisionRepo repository.PostRevisionRepository, policy *policy.Policy) *PostService {
 	return &PostService{
 		postRepo:     postRepo,
 		categoryRepo: categoryRepo,
+		redirectRepo: redirectRepo,
+		revisionRepo: revisionRepo,
+		policy:       policy,
+	}
+}
+
+// SetSearchIndex 设置独立搜索索引，文章变更时会同步更新索引
+func (s *PostService) SetSearchIndex(index repository.SearchIndex) {
+	s.searchIndex = index
+}
+
+// CreatePost 创建文章，作者为当前操作者
+// slug为空时根据标题自动生成，status为空时创建草稿，为scheduled时publishAt必须晚于当前时间
+func (s *PostService) CreatePost(actor *policy.Actor, title, content, titleURL, slug string, status entity.PostStatus, publishAt *time.Time) (*entity.Post, error) {
+	if err := s.policy.CanCreatePost(actor); err != nil {
+		return nil, err
+	}
+
+	post := entity.NewPost(title, content, actor.Username, titleURL)
+	var err error
+	post.Slug, err = s.resolveSlug(slug, title, 0)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case entity.PostStatusPublished:
+		post.Publish(post.CreatedAt)
+	case entity.PostStatusScheduled:
+		if publishAt == nil {
+			return nil, entity.ErrInvalidSchedule
+		}
+		if err := post.Schedule(*publishAt, post.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.postRepo.Create(post)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.revisionRepo.Create(entity.NewPostRevision(post, actor.Username)); err != nil {
+		return nil, err
 	}
+	s.syncSearchIndex(post)
+	return post, nil
 }
 
-// CreatePost 创建文章
-func (s *PostService) CreatePost(title, content, author, titleURL string) (*entity.Post, error) {
-	post := entity.NewPost(title, content, author, titleURL)
-	err := s.postRepo.Create(post)
+// GetPostByID 根据ID获取文章，未发布的文章对无权查看的操作者视为不存在
+func (s *PostService) GetPostByID(actor *policy.Actor, id uint) (*entity.Post, error) {
+	post, err := s.postRepo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
+
+	if err := s.policy.CanViewPost(actor, post); err != nil {
+		return nil, fmt.Errorf("文章不存在: %d: %w", id, utils.ErrNotFound)
+	}
 	return post, nil
 }
 
-// GetPostByID 根据ID获取文章
-func (s *PostService) GetPostByID(id uint) (*entity.Post, error) {
-	return s.postRepo.GetByID(id)
+// GetPostBySlug 根据slug获取文章，可见性规则与GetPostByID相同
+// slug已停用时返回*SlugMovedError，其中包含文章当前的slug
+func (s *PostService) GetPostBySlug(actor *policy.Actor, slug string) (*entity.Post, error) {
+	post, err := s.postRepo.GetBySlug(slug)
+	if err != nil {
+		if !errors.Is(err, utils.ErrNotFound) {
+			return nil, err
+		}
+		redirect, redirectErr := s.redirectRepo.GetBySlug(slug)
+		if redirectErr != nil {
+			if errors.Is(redirectErr, utils.ErrNotFound) {
+				return nil, err
+			}
+			return nil, redirectErr
+		}
+		post, err = s.postRepo.GetByID(redirect.PostID)
+		if err != nil {
+			return nil, err
+		}
+		if s.policy.CanViewPost(actor, post) != nil {
+			return nil, fmt.Errorf("文章不存在: %s: %w", slug, utils.ErrNotFound)
+		}
+		return nil, &SlugMovedError{Slug: post.Slug}
+	}
+	if err := s.policy.CanViewPost(actor, post); err != nil {
+		return nil, fmt.Errorf("文章不存在: %s: %w", slug, utils.ErrNotFound)
+	}
+	return post, nil
 }
 
-// GetAllPosts 获取所有文章
-func (s *PostService) GetAllPosts() ([]*entity.Post, error) {
-	return s.postRepo.GetAll()
+// ListSlugRedirects 获取slug重定向列表，postID为0时返回全部
+func (s *PostService) ListSlugRedirects(actor *policy.Actor, postID uint) ([]*entity.SlugRedirect, error) {
+	if err := s.policy.CanManageSlugRedirects(actor); err != nil {
+		return nil, err
+	}
+	return s.redirectRepo.List(postID)
 }
 
-// UpdatePost 更新文章
-func (s *PostService) UpdatePost(id uint, title, content, titleURL string) (*entity.Post, error) {
-	post, err := s.postRepo.GetByID(id)
+// DeleteSlugRedirect 删除slug重定向，旧链接将不再跳转
+func (s *PostService) DeleteSlugRedirect(actor *policy.Actor, id uint) error {
+	if err := s.policy.CanManageSlugRedirects(actor); err != nil {
+		return err
+	}
+	if _, err := s.redirectRepo.GetByID(id); err != nil {
+		return err
+	}
+	return s.redirectRepo.Delete(id)
+}
+
+// BackfillSlugs 为没有slug的历史文章生成slug，返回补全的文章数量
+func (s *PostService) BackfillSlugs() (int, error) {
+	posts, err := s.postRepo.GetAll()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, post := range posts {
+		if post.Slug != "" {
+			continue
+		}
+		post.Slug, err = s.resolveSlug("", post.Title, post.ID)
+		if err != nil {
+			return count, err
+		}
+		if err := s.postRepo.Update(post); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// ListPosts 按条件分页获取文章列表
+func (s *PostService) ListPosts(actor *policy.Actor, query *repository.PostQuery) ([]*entity.Post, int64, error) {
+	if err := s.policy.ScopePostQuery(actor, query); err != nil {
+		return nil, 0, err
+	}
+	return s.postRepo.List(query)
+}
+
+// ListPostsByCursor 按游标分页获取文章列表
+func (s *PostService) ListPostsByCursor(actor *policy.Actor, query *repository.PostQuery, page *repository.CursorQuery) ([]*entity.Post, bool, error) {
+	if err := s.policy.ScopePostQuery(actor, query); err != nil {
+		return nil, false, err
+	}
+	return s.postRepo.ListByCursor(query, page)
+}
+
+// SearchPosts 全文搜索文章，可见范围与列表相同
+func (s *PostService) SearchPosts(actor *policy.Actor, query *repository.PostSearchQuery) ([]*repository.PostSearchHit, int64, error) {
+	query.Keyword = strings.TrimSpace(query.Keyword)
+	if query.Keyword == "" {
+		return nil, 0, ErrEmptyKeyword
+	}
+	if err := s.policy.ScopePostQuery(actor, &query.PostQuery); err != nil {
+		return nil, 0, err
+	}
+	if s.searchIndex != nil {
+		return s.searchIndex.Search(query)
+	}
+	return s.postRepo.Search(query)
+}
+
+// RebuildSearchIndex 重建独立搜索索引，返回写入的文章数量
+func (s *PostService) RebuildSearchIndex(actor *policy.Actor) (int, error) {
+	if err := s.policy.CanRebuildSearchIndex(actor); err != nil {
+		return 0, err
+	}
+	return s.BuildSearchIndex()
+}
+
+// BuildSearchIndex 从仓储读取全部文章写入独立搜索索引，用于启动时初始化
+func (s *PostService) BuildSearchIndex() (int, error) {
+	if s.searchIndex == nil {
+		return 0, ErrSearchIndexDisabled
+	}
+
+	posts, err := s.postRepo.GetAll()
+	if err != nil {
+		return 0, err
+	}
+
+	docs := make([]*repository.SearchDocument, 0, len(posts))
+	for _, post := range posts {
+		doc, err := s.searchDocument(post)
+		if err != nil {
+			return 0, err
+		}
+		docs = append(docs, doc)
+	}
+
+	if err := s.searchIndex.Rebuild(docs); err != nil {
+		return 0, fmt.Errorf("重建搜索索引失败: %w", err)
+	}
+	return len(docs), nil
+}
+
+// UpdatePost 更新文章，slug为空时保持原slug不变，标题或内容有变化时保存新的修订版本
+// version为客户端读取文章时的版本号，与当前版本不一致时返回repository.ErrVersionConflict
+func (s *PostService) UpdatePost(actor *policy.Actor, id, version uint, title, content, titleURL, slug string) (*entity.Post, error) {
+	post, err := s.editablePost(actor, id)
 	if err != nil {
 		return nil, err
 	}
+	if post.Version != version {
+		return nil, fmt.Errorf("文章 %d 的当前版本为 %d: %w", id, post.Version, repository.ErrVersionConflict)
+	}
 
+	// 历史文章没有slug时顺便补全
+	oldSlug := post.Slug
+	if slug != "" || post.Slug == "" {
+		post.Slug, err = s.resolveSlug(slug, title, post.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	changed := post.Title != title || post.Content != content || post.TitleURL != titleURL
 	post.Update(title, content, titleURL)
 	err = s.postRepo.Update(post)
 	if err != nil {
 		return nil, err
 	}
+	if changed {
+		if err := s.revisionRepo.Create(entity.NewPostRevision(post, actor.Username)); err != nil {
+			return nil, err
+		}
+	}
+	if post.Slug != oldSlug {
+		if err := s.recordSlugChange(post.ID, oldSlug, post.Slug); err != nil {
+			return nil, err
+		}
+	}
+	s.syncSearchIndex(post)
 
 	return post, nil
 }
 
-// IncrementViewCount 增加文章阅读量
-func (s *PostService) IncrementViewCount(id uint) error {
+// ListRevisions 获取文章的修订历史，按版本号倒序，仅可修改文章的操作者可以查看
+func (s *PostService) ListRevisions(actor *policy.Actor, postID uint) ([]*entity.PostRevision, error) {
+	if _, err := s.editablePost(actor, postID); err != nil {
+		return nil, err
+	}
+	return s.revisionRepo.ListByPostID(postID)
+}
+
+// DiffRevisions 比较文章的两个修订版本，from为旧版本，to为新版本
+func (s *PostService) DiffRevisions(actor *policy.Actor, postID uint, from, to int) (*RevisionDiff, error) {
+	if _, err := s.editablePost(actor, postID); err != nil {
+		return nil, err
+	}
+
+	fromRevision, err := s.revisionRepo.GetByRevision(postID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.revisionRepo.GetByRevision(postID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RevisionDiff{
+		From:  fromRevision,
+		To:    toRevision,
+		Lines: utils.DiffLines(fromRevision.Content, toRevision.Content),
+	}, nil
+}
+
+// RestoreRevision 将文章的标题和内容恢复为指定修订版本，恢复后保存为新的修订版本
+func (s *PostService) RestoreRevision(actor *policy.Actor, postID uint, revision int) (*entity.Post, error) {
+	post, err := s.editablePost(actor, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.revisionRepo.GetByRevision(postID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	post.Update(target.Title, target.Content, target.TitleURL)
+	err = s.postRepo.Update(post)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.revisionRepo.Create(entity.NewPostRevision(post, actor.Username)); err != nil {
+		return nil, err
+	}
+	s.syncSearchIndex(post)
+
+	return post, nil
+}
+
+// editablePost 获取文章并校验操作者是否可以修改
+func (s *PostService) editablePost(actor *policy.Actor, id uint) (*entity.Post, error) {
 	post, err := s.postRepo.GetByID(id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if err := s.policy.CanUpdatePost(actor, post); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// PublishPost 立即发布文章
+func (s *PostService) PublishPost(actor *policy.Actor, id uint) (*entity.Post, error) {
+	return s.changeStatus(actor, id, func(post *entity.Post, now time.Time) error {
+		post.Publish(now)
+		return nil
+	})
+}
+
+// UnpublishPost 将文章撤回为草稿
+func (s *PostService) UnpublishPost(actor *policy.Actor, id uint) (*entity.Post, error) {
+	return s.changeStatus(actor, id, func(post *entity.Post, now time.Time) error {
+		post.Unpublish(now)
+		return nil
+	})
+}
+
+// SchedulePost 设置文章定时发布
+func (s *PostService) SchedulePost(actor *policy.Actor, id uint, publishAt time.Time) (*entity.Post, error) {
+	return s.changeStatus(actor, id, func(post *entity.Post, now time.Time) error {
+		return post.Schedule(publishAt, now)
+	})
+}
+
+// ArchivePost 归档文章
+func (s *PostService) ArchivePost(actor *policy.Actor, id uint) (*entity.Post, error) {
+	return s.changeStatus(actor, id, func(post *entity.Post, now time.Time) error {
+		post.Archive(now)
+		return nil
+	})
+}
+
+// PublishDuePosts 发布所有到期的定时文章，返回本次发布的文章
+// 单篇文章保存失败（如正被编辑而版本冲突）时跳过该文章继续发布其余文章，失败的文章在下一轮重试，
+// 所有失败原因合并后返回
+func (s *PostService) PublishDuePosts(now time.Time) ([]*entity.Post, error) {
+	posts, err := s.postRepo.GetDueScheduled(now)
+	if err != nil {
+		return nil, err
+	}
+
+	published := make([]*entity.Post, 0, len(posts))
+	var errs []error
+	for _, post := range posts {
+		post.Publish(now)
+		if err := s.postRepo.Update(post); err != nil {
+			errs = append(errs, fmt.Errorf("发布文章 %d 失败: %w", post.ID, err))
+			continue
+		}
+		s.syncSearchIndex(post)
+		published = append(published, post)
+	}
+	return published, errors.Join(errs...)
+}
+
+// changeStatus 校验权限后修改文章状态并保存
+func (s *PostService) changeStatus(actor *policy.Actor, id uint, change func(post *entity.Post, now time.Time) error) (*entity.Post, error) {
+	post, err := s.postRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanUpdatePost(actor, post); err != nil {
+		return nil, err
+	}
+
+	if err := change(post, time.Now()); err != nil {
+		return nil, err
 	}
 
-	post.ViewCount++
 	err = s.postRepo.Update(post)
+	if err != nil {
+		return nil, err
+	}
+	s.syncSearchIndex(post)
+
+	return post, nil
+}
+
+// IncrementViewCount 增加文章阅读量，不影响文章的版本号
+func (s *PostService) IncrementViewCount(id uint) error {
+	return s.postRepo.IncrementViewCount(id)
+}
+
+// DeletePost 将文章移入回收站，超过保留期后由清理任务永久删除
+func (s *PostService) DeletePost(actor *policy.Actor, id uint) error {
+	post, err := s.postRepo.GetByID(id)
 	if err != nil {
 		return err
 	}
 
+	if err := s.policy.CanDeletePost(actor, post); err != nil {
+		return err
+	}
+
+	if err := s.postRepo.Delete(id); err != nil {
+		return err
+	}
+	if s.searchIndex != nil {
+		if err := s.searchIndex.Remove(id); err != nil {
+			log.Printf("从搜索索引移除文章 %d 失败: %v", id, err)
+		}
+	}
 	return nil
 }
 
-// DeletePost 删除文章
-func (s *PostService) DeletePost(id uint) error {
-	return s.postRepo.Delete(id)
+// ListTrashedPosts 分页获取回收站中的文章，作者只能看到自己的文章
+func (s *PostService) ListTrashedPosts(actor *policy.Actor, query *repository.TrashQuery) ([]*entity.Post, int64, error) {
+	if err := s.policy.ScopeTrashQuery(actor, query); err != nil {
+		return nil, 0, err
+	}
+	return s.postRepo.ListTrashed(query)
+}
+
+// RestorePost 将文章移出回收站，权限与删除文章相同
+func (s *PostService) RestorePost(actor *policy.Actor, id uint) (*entity.Post, error) {
+	post, err := s.postRepo.GetTrashedByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.CanDeletePost(actor, post); err != nil {
+		return nil, err
+	}
+
+	if err := s.postRepo.Restore(id); err != nil {
+		return nil, err
+	}
+	post.DeletedAt = nil
+	s.syncSearchIndex(post)
+	return post, nil
+}
+
+// PurgeTrash 永久删除在before之前移入回收站的文章，返回删除的数量
+func (s *PostService) PurgeTrash(before time.Time) (int64, error) {
+	return s.postRepo.PurgeTrashed(before)
 }
 
 // AddPostToCategory 将文章添加到分类
 func (s *PostService) AddPostToCategory(postID, categoryID uint) error {
-	return s.categoryRepo.AddPostToCategory(postID, categoryID)
+	if err := s.categoryRepo.AddPostToCategory(postID, categoryID); err != nil {
+		return err
+	}
+	if s.searchIndex != nil {
+		post, err := s.postRepo.GetByID(postID)
+		if err != nil {
+			return err
+		}
+		s.syncSearchIndex(post)
+	}
+	return nil
 }
 
-// GetPostsByCategory 根据分类获取文章
-func (s *PostService) GetPostsByCategory(categoryID uint) ([]*entity.Post, error) {
-	return s.postRepo.GetByCategory(categoryID)
+// syncSearchIndex 将文章同步到独立搜索索引
+// 索引可随时重建，同步失败只记录日志，不影响文章本身的保存
+func (s *PostService) syncSearchIndex(post *entity.Post) {
+	if s.searchIndex == nil {
+		return
+	}
+
+	doc, err := s.searchDocument(post)
+	if err == nil {
+		err = s.searchIndex.Index(doc)
+	}
+	if err != nil {
+		log.Printf("同步文章 %d 到搜索索引失败: %v", post.ID, err)
+	}
+}
+
+// searchDocument 构造文章的搜索文档
+func (s *PostService) searchDocument(post *entity.Post) (*repository.SearchDocument, error) {
+	categories, err := s.categoryRepo.GetCategoriesByPostID(post.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &repository.SearchDocument{Post: post}
+	for _, category := range categories {
+		doc.CategoryIDs = append(doc.CategoryIDs, category.ID)
+	}
+	return doc, nil
+}
+
+// recordSlugChange 记录旧slug到文章的重定向
+// 新slug如果曾是某篇文章的旧slug，对应的重定向随之失效
+func (s *PostService) recordSlugChange(postID uint, oldSlug, newSlug string) error {
+	if err := s.redirectRepo.DeleteBySlug(newSlug); err != nil {
+		return err
+	}
+	if oldSlug == "" {
+		return nil
+	}
+	return s.redirectRepo.Save(entity.NewSlugRedirect(oldSlug, postID))
+}
+
+// resolveSlug 确定文章的slug
+// 指定slug时规范化后直接使用，被占用则报错；未指定时根据标题生成，重复时追加数字后缀
+func (s *PostService) resolveSlug(requested, title string, excludeID uint) (string, error) {
+	if requested != "" {
+		slug := utils.Slugify(requested)
+		if slug == "" {
+			return "", ErrInvalidSlug
+		}
+		exists, err := s.postRepo.SlugExists(slug, excludeID)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return "", ErrSlugTaken
+		}
+		return slug, nil
+	}
+
+	base := utils.Slugify(title)
+	if base == "" {
+		base = "post"
+	}
+	for i := 1; i <= maxSlugSuffix; i++ {
+		slug := base
+		if i > 1 {
+			suffix := fmt.Sprintf("-%d", i)
+			slug = strings.TrimRight(base[:min(len(base), utils.MaxSlugLength-len(suffix))], "-") + suffix
+		}
+		exists, err := s.postRepo.SlugExists(slug, excludeID)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
+		}
+	}
+	return "", ErrSlugTaken
 }
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"strings"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+)
+
+// TagService 标签领域服务
+type TagService struct {
+	tagRepo repository.TagRepository
+}
+
+// NewTagService 创建标签服务
+func NewTagService(tagRepo repository.TagRepository) *TagService {
+	return &TagService{
+		tagRepo: tagRepo,
+	}
+}
+
+// SetPostTags 设置文章标签，不存在的标签自动创建
+// 调用方需先完成文章的权限校验
+func (s *TagService) SetPostTags(postID uint, names []string) ([]*entity.Tag, error) {
+	names = uniqueTagNames(names)
+
+	var tags []*entity.Tag
+	if len(names) > 0 {
+		var err error
+		tags, err = s.tagRepo.GetOrCreate(names)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tagIDs := make([]uint, 0, len(tags))
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	if err := s.tagRepo.SetPostTags(postID, tagIDs); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetTagsByPostID 获取文章的所有标签
+func (s *TagService) GetTagsByPostID(postID uint) ([]*entity.Tag, error) {
+	return s.tagRepo.GetByPostID(postID)
+}
+
+// GetTagByName 根据名称获取标签
+func (s *TagService) GetTagByName(name string) (*entity.Tag, error) {
+	return s.tagRepo.GetByName(entity.NormalizeTagName(name))
+}
+
+// GetTagCloud 获取标签云
+func (s *TagService) GetTagCloud(limit int) ([]*repository.TagCount, error) {
+	return s.tagRepo.GetCloud(limit)
+}
+
+// uniqueTagNames 规范化标签名并去除空值和重复项，重复判断不区分大小写
+func uniqueTagNames(names []string) []string {
+	seen := make(map[string]struct{}, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		name = entity.NormalizeTagName(name)
+		if name == "" {
+			continue
+		}
+		key := strings.ToLower(name)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, name)
+	}
+	return result
+}
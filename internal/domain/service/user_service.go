@@ -0,0 +1,126 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrUserExists 用户名已被注册
+	ErrUserExists = fmt.Errorf("用户名已存在: %w", utils.ErrConflict)
+	// ErrInvalidCredentials 用户名或密码错误
+	ErrInvalidCredentials = fmt.Errorf("用户名或密码错误: %w", utils.ErrUnauthorized)
+	// ErrInvalidRole 角色不合法
+	ErrInvalidRole = fmt.Errorf("无效的角色: %w", utils.ErrValidation)
+)
+
+// UserService 用户领域服务
+type UserService struct {
+	userRepo repository.UserRepository
+	policy   *policy.Policy
+}
+
+// NewUserService 创建用户服务
+func NewUserService(userRepo repository.UserRepository, policy *policy.Policy) *UserService {
+	return &UserService{
+		userRepo: userRepo,
+		policy:   policy,
+	}
+}
+
+// Register 注册用户，第一个注册的用户成为管理员，其余用户默认为读者
+// 用户名是否已存在以及谁是第一个用户都由存储库的唯一约束判定，并发注册时结果仍然正确
+func (s *UserService) Register(username, password string) (*entity.User, error) {
+	_, err := s.userRepo.GetByUsername(username)
+	if err == nil {
+		return nil, ErrUserExists
+	}
+	if !errors.Is(err, utils.ErrNotFound) {
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.userRepo.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	user := entity.NewUser(username, string(hash), entity.RoleReader)
+	if count == 0 {
+		// 同时注册的多个用户都可能看到没有用户，只有成功创建第一个用户的请求成为管理员，其余按读者注册
+		user.Role = entity.RoleAdmin
+		err = s.userRepo.CreateFirst(user)
+		if errors.Is(err, utils.ErrConflict) {
+			user.Role = entity.RoleReader
+			err = s.userRepo.Create(user)
+		}
+	} else {
+		err = s.userRepo.Create(user)
+	}
+	if errors.Is(err, utils.ErrConflict) {
+		return nil, ErrUserExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Authenticate 校验用户名和密码
+func (s *UserService) Authenticate(username, password string) (*entity.User, error) {
+	user, err := s.userRepo.GetByUsername(username)
+	if errors.Is(err, utils.ErrNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// GetUserByID 根据ID获取用户
+func (s *UserService) GetUserByID(id uint) (*entity.User, error) {
+	return s.userRepo.GetByID(id)
+}
+
+// UpdateUserRole 修改用户角色
+func (s *UserService) UpdateUserRole(actor *policy.Actor, id uint, role entity.Role) (*entity.User, error) {
+	if err := s.policy.CanManageUsers(actor); err != nil {
+		return nil, err
+	}
+	if !role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	err = s.userRepo.UpdateRole(id, role)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role = role
+	return user, nil
+}
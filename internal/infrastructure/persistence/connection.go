@@ -0,0 +1,68 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"blog/internal/config"
+)
+
+// Connection 数据库连接，Dialect为所连接数据库的SQL方言
+type Connection struct {
+	DB      *sql.DB
+	Dialect Dialect
+}
+
+// NewConnection 使用已打开的数据库创建连接
+func NewConnection(db *sql.DB, dialect Dialect) *Connection {
+	return &Connection{
+		DB:      db,
+		Dialect: dialect,
+	}
+}
+
+// Open 按配置项database.driver选择方言并打开数据库连接
+func Open(cfg *config.DatabaseConfig) (*Connection, error) {
+	dialect, err := DialectFor(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := dialect.open(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// 测试连接
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	log.Printf("数据库连接成功: %s", dialect.Name())
+	return NewConnection(db, dialect), nil
+}
+
+// Close 关闭数据库连接
+func (conn *Connection) Close() error {
+	if conn.DB != nil {
+		return conn.DB.Close()
+	}
+	return nil
+}
+
+// DialectFor 根据驱动名称返回对应的方言
+func DialectFor(driver string) (Dialect, error) {
+	switch driver {
+	case MySQL.Name():
+		return MySQL, nil
+	case SQLite.Name():
+		return SQLite, nil
+	case Postgres.Name():
+		return Postgres, nil
+	default:
+		return nil, fmt.Errorf("不支持的数据库驱动: %s", driver)
+	}
+}
@@ -0,0 +1,104 @@
+package persistence
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// database 存储库使用的数据库句柄，SQL统一使用?占位符，执行前按方言改写
+type database struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// newDatabase 创建存储库使用的数据库句柄
+func newDatabase(conn *Connection) *database {
+	return &database{
+		db:      conn.DB,
+		dialect: conn.Dialect,
+	}
+}
+
+// Exec 执行不返回行的语句
+func (d *database) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return d.db.Exec(d.dialect.rebind(query), args...)
+}
+
+// Query 执行查询
+func (d *database) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return d.db.Query(d.dialect.rebind(query), args...)
+}
+
+// QueryRow 执行只返回一行的查询
+func (d *database) QueryRow(query string, args ...interface{}) *sql.Row {
+	return d.db.QueryRow(d.dialect.rebind(query), args...)
+}
+
+// Begin 开启事务
+func (d *database) Begin() (*transaction, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &transaction{tx: tx, dialect: d.dialect}, nil
+}
+
+// insert 执行INSERT语句并返回新行的ID
+func (d *database) insert(query string, args ...interface{}) (int64, error) {
+	return d.dialect.insert(d, query, args...)
+}
+
+// upsert 插入一行，conflict列冲突时用新值更新update列，返回该行的ID
+func (d *database) upsert(table string, columns []string, conflict string, update []string, args ...interface{}) (int64, error) {
+	return d.dialect.upsert(d, table, columns, conflict, update, args...)
+}
+
+// transaction 事务，与database一样在执行前改写占位符
+type transaction struct {
+	tx      *sql.Tx
+	dialect Dialect
+}
+
+// Exec 在事务中执行不返回行的语句
+func (t *transaction) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.tx.Exec(t.dialect.rebind(query), args...)
+}
+
+// Commit 提交事务
+func (t *transaction) Commit() error {
+	return t.tx.Commit()
+}
+
+// Rollback 回滚事务
+func (t *transaction) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// rebindNumbered 将?占位符改写为$1、$2…的编号形式，跳过字符串字面量和带引号的标识符中的问号
+func rebindNumbered(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	var quote rune
+	for _, c := range query {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,248 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"blog/internal/config"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// Dialect SQL方言，封装各数据库在连接方式和非标准语法上的差异，存储库中其余的SQL各数据库通用
+type Dialect interface {
+	// Name 方言名称，与配置项database.driver一致
+	Name() string
+	// open 按配置打开数据库
+	open(cfg *config.DatabaseConfig) (*sql.DB, error)
+	// rebind 将SQL中的?占位符改写为该数据库的占位符
+	rebind(query string) string
+	// insert 执行INSERT语句并返回新行的ID
+	insert(db *database, query string, args ...interface{}) (int64, error)
+	// matchPosts 返回全文搜索文章的匹配条件和相关度得分表达式
+	matchPosts(keyword string) textMatch
+	// upsert 插入一行，conflict列冲突时用新值更新update列，返回该行的ID
+	upsert(db *database, table string, columns []string, conflict string, update []string, args ...interface{}) (int64, error)
+	// syncIdentity 显式写入自增ID后同步表的自增计数，使之后自动分配的ID不与已有的行冲突
+	syncIdentity(tx *transaction, table string) error
+}
+
+// textMatch 全文搜索的匹配条件和得分表达式，args依次对应表达式中的占位符
+type textMatch struct {
+	condition     string
+	conditionArgs []interface{}
+	score         string
+	scoreArgs     []interface{}
+}
+
+var (
+	// MySQL 使用FULLTEXT索引（ngram分词）搜索，多副本部署时通过GET_LOCK互斥
+	MySQL Dialect = mysqlDialect{}
+	// SQLite 单文件数据库，搜索使用子串匹配，只支持单实例部署
+	SQLite Dialect = sqliteDialect{}
+	// Postgres 使用tsvector列搜索，多副本部署时通过咨询锁互斥
+	Postgres Dialect = postgresDialect{}
+)
+
+// mysqlDialect MySQL方言
+type mysqlDialect struct{}
+
+// Name 方言名称
+func (mysqlDialect) Name() string {
+	return "mysql"
+}
+
+// open 打开MySQL数据库
+func (mysqlDialect) open(cfg *config.DatabaseConfig) (*sql.DB, error) {
+	return sql.Open("mysql", cfg.GetDSN())
+}
+
+// rebind MySQL使用?占位符，不需要改写
+func (mysqlDialect) rebind(query string) string {
+	return query
+}
+
+// insert 通过LastInsertId取回新行的ID
+func (mysqlDialect) insert(db *database, query string, args ...interface{}) (int64, error) {
+	return lastInsertID(db, query, args...)
+}
+
+// matchPosts 使用ft_posts_title_content索引的自然语言模式匹配
+func (mysqlDialect) matchPosts(keyword string) textMatch {
+	return textMatch{
+		condition:     postMatchClause,
+		conditionArgs: []interface{}{keyword},
+		score:         postMatchClause,
+		scoreArgs:     []interface{}{keyword},
+	}
+}
+
+// upsert 冲突时通过LAST_INSERT_ID取回已有行的ID
+func (mysqlDialect) upsert(db *database, table string, columns []string, conflict string, update []string, args ...interface{}) (int64, error) {
+	assignments := []string{"id = LAST_INSERT_ID(id)"}
+	for _, column := range update {
+		assignments = append(assignments, column+" = VALUES("+column+")")
+	}
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s`,
+		table, strings.Join(columns, ", "), placeholders(len(columns)), strings.Join(assignments, ", "))
+
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// syncIdentity MySQL的AUTO_INCREMENT会随显式写入的ID自动前进，不需要同步
+func (mysqlDialect) syncIdentity(tx *transaction, table string) error {
+	return nil
+}
+
+// sqliteDialect SQLite方言，使用纯Go实现的modernc.org/sqlite驱动
+type sqliteDialect struct{}
+
+// Name 方言名称
+func (sqliteDialect) Name() string {
+	return "sqlite"
+}
+
+// open 打开SQLite数据库文件
+// 每个连接都开启外键约束以支持级联删除；时间统一按带时区的文本格式写入，保证按字符串比较的结果与时间先后一致；
+// 事务以IMMEDIATE模式开始，避免并发写入时因锁升级失败而报错
+func (sqliteDialect) open(cfg *config.DatabaseConfig) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_time_format", "sqlite")
+	params.Set("_txlock", "immediate")
+	return sql.Open("sqlite", "file:"+cfg.Path+"?"+params.Encode())
+}
+
+// rebind SQLite使用?占位符，不需要改写
+func (sqliteDialect) rebind(query string) string {
+	return query
+}
+
+// insert 通过LastInsertId取回新行的ID
+func (sqliteDialect) insert(db *database, query string, args ...interface{}) (int64, error) {
+	return lastInsertID(db, query, args...)
+}
+
+// matchPosts 要求标题或正文包含关键词中的每个词，标题命中得2分，正文命中得1分
+func (sqliteDialect) matchPosts(keyword string) textMatch {
+	var match textMatch
+	var conditions, scores []string
+	for _, term := range strings.Fields(keyword) {
+		pattern := "%" + escapeLike(term) + "%"
+		conditions = append(conditions, `(p.title LIKE ? ESCAPE '\' OR p.content LIKE ? ESCAPE '\')`)
+		match.conditionArgs = append(match.conditionArgs, pattern, pattern)
+		scores = append(scores, `CASE WHEN p.title LIKE ? ESCAPE '\' THEN 2 ELSE 0 END + CASE WHEN p.content LIKE ? ESCAPE '\' THEN 1 ELSE 0 END`)
+		match.scoreArgs = append(match.scoreArgs, pattern, pattern)
+	}
+	// 与MySQL一致，没有可匹配的词时不返回任何文章
+	if len(conditions) == 0 {
+		return textMatch{condition: "1 = 0", score: "0"}
+	}
+	match.condition = "(" + strings.Join(conditions, " AND ") + ")"
+	match.score = "(" + strings.Join(scores, " + ") + ")"
+	return match
+}
+
+// upsert 冲突时更新指定列并通过RETURNING取回ID
+func (sqliteDialect) upsert(db *database, table string, columns []string, conflict string, update []string, args ...interface{}) (int64, error) {
+	return upsertReturning(db, table, columns, conflict, update, args...)
+}
+
+// syncIdentity SQLite的AUTOINCREMENT取已有最大ID加1，不需要同步
+func (sqliteDialect) syncIdentity(tx *transaction, table string) error {
+	return nil
+}
+
+// postgresDialect PostgreSQL方言，使用pgx驱动
+type postgresDialect struct{}
+
+// Name 方言名称
+func (postgresDialect) Name() string {
+	return "postgres"
+}
+
+// open 打开PostgreSQL数据库
+func (postgresDialect) open(cfg *config.DatabaseConfig) (*sql.DB, error) {
+	return sql.Open("pgx", cfg.GetPostgresDSN())
+}
+
+// rebind PostgreSQL使用$1、$2…编号占位符
+func (postgresDialect) rebind(query string) string {
+	return rebindNumbered(query)
+}
+
+// insert PostgreSQL驱动不支持LastInsertId，在语句末尾追加RETURNING id取回新行的ID
+func (postgresDialect) insert(db *database, query string, args ...interface{}) (int64, error) {
+	var id int64
+	if err := db.QueryRow(strings.TrimSpace(query)+" RETURNING id", args...).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// matchPosts 使用posts.search_vector列（标题权重A、正文权重B）匹配，按ts_rank计算得分
+// 使用simple分词配置，中文按连续的汉字整体切分，需要中文分词时可改用内置搜索引擎
+func (postgresDialect) matchPosts(keyword string) textMatch {
+	const query = "plainto_tsquery('simple', ?)"
+	return textMatch{
+		condition:     "p.search_vector @@ " + query,
+		conditionArgs: []interface{}{keyword},
+		score:         "ts_rank(p.search_vector, " + query + ")",
+		scoreArgs:     []interface{}{keyword},
+	}
+}
+
+// upsert 冲突时更新指定列并通过RETURNING取回ID
+func (postgresDialect) upsert(db *database, table string, columns []string, conflict string, update []string, args ...interface{}) (int64, error) {
+	return upsertReturning(db, table, columns, conflict, update, args...)
+}
+
+// syncIdentity PostgreSQL的标识列序列不随显式写入的ID前进，将序列设置为表中的最大ID
+func (postgresDialect) syncIdentity(tx *transaction, table string) error {
+	_, err := tx.Exec(fmt.Sprintf(`SELECT setval(pg_get_serial_sequence('%s', 'id'), (SELECT MAX(id) FROM %s))`, table, table))
+	return err
+}
+
+// lastInsertID 执行INSERT语句并通过LastInsertId取回新行的ID
+func lastInsertID(db *database, query string, args ...interface{}) (int64, error) {
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// upsertReturning 使用ON CONFLICT ... DO UPDATE ... RETURNING id实现upsert，SQLite和PostgreSQL通用
+// 没有需要更新的列时原样写回冲突列，使冲突时也能返回已有行
+func upsertReturning(db *database, table string, columns []string, conflict string, update []string, args ...interface{}) (int64, error) {
+	if len(update) == 0 {
+		update = []string{conflict}
+	}
+	assignments := make([]string, 0, len(update))
+	for _, column := range update {
+		assignments = append(assignments, column+" = excluded."+column)
+	}
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO UPDATE SET %s RETURNING id`,
+		table, strings.Join(columns, ", "), placeholders(len(columns)), conflict, strings.Join(assignments, ", "))
+
+	var id int64
+	if err := db.QueryRow(query, args...).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// escapeLike 转义LIKE模式中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
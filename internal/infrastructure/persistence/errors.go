@@ -0,0 +1,45 @@
+package persistence
+
+import (
+	"errors"
+	"fmt"
+
+	"blog/pkg/utils"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	// mysqlErrDuplicateEntry MySQL唯一索引冲突的错误号
+	mysqlErrDuplicateEntry = 1062
+	// postgresUniqueViolation PostgreSQL唯一约束冲突的SQLSTATE
+	postgresUniqueViolation = "23505"
+)
+
+// conflictError 唯一索引冲突时返回归类为utils.ErrConflict的错误，否则按message包装原始错误
+func conflictError(err error, message, conflictMessage string) error {
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%s: %w", conflictMessage, utils.ErrConflict)
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// isUniqueViolation 判断错误是否为各数据库驱动的唯一约束冲突
+func isUniqueViolation(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrDuplicateEntry
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == postgresUniqueViolation
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package persistence
+
+import (
+	"fmt"
+
+	"blog/internal/domain/repository"
+)
+
+// keysetClause 根据游标生成键集分页条件和排序方向
+// 结果集整体按(created_at, id)倒序排列；向前翻页时先按正序查询，调用方需将结果反转
+func keysetClause(alias string, cursor *repository.Cursor) (string, []interface{}, string) {
+	if cursor == nil {
+		return "", nil, "DESC"
+	}
+
+	op, order := "<", "DESC"
+	if cursor.Backward {
+		op, order = ">", "ASC"
+	}
+	condition := fmt.Sprintf("(%[1]s.created_at %[2]s ? OR (%[1]s.created_at = ? AND %[1]s.id %[2]s ?))", alias, op)
+	return condition, []interface{}{cursor.CreatedAt, cursor.CreatedAt, cursor.ID}, order
+}
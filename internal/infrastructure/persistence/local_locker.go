@@ -0,0 +1,37 @@
+package persistence
+
+import (
+	"context"
+	"sync"
+)
+
+// LocalLocker 进程内的互斥锁，用于只支持单实例部署的SQLite和内存存储
+type LocalLocker struct {
+	mu     sync.Mutex
+	locked map[string]bool
+}
+
+// NewLocalLocker 创建进程内的互斥锁
+func NewLocalLocker() *LocalLocker {
+	return &LocalLocker{
+		locked: make(map[string]bool),
+	}
+}
+
+// TryLock 尝试获取锁，不等待；获取成功时返回释放函数
+func (l *LocalLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.locked[name] {
+		return nil, false, nil
+	}
+	l.locked[name] = true
+
+	unlock := func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.locked, name)
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,241 @@
+package persistence
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+// MemoryCategoryRepository 基于内存的分类存储库实现
+type MemoryCategoryRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryCategoryRepository 创建内存分类存储库
+func NewMemoryCategoryRepository(store *MemoryStore) repository.CategoryRepository {
+	return &MemoryCategoryRepository{
+		store: store,
+	}
+}
+
+// Create 创建分类，分类名称不区分大小写唯一
+func (r *MemoryCategoryRepository) Create(category *entity.Category) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.nameTaken(category.Name, 0) {
+		return fmt.Errorf("分类名称已存在: %w", utils.ErrConflict)
+	}
+
+	category.ID = r.store.nextID("categories")
+	r.store.categories[category.ID] = cloneCategory(category)
+	return nil
+}
+
+// GetByID 根据ID获取分类
+func (r *MemoryCategoryRepository) GetByID(id uint) (*entity.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	category, ok := r.store.categories[id]
+	if !ok || category.DeletedAt != nil {
+		return nil, fmt.Errorf("分类不存在: %d: %w", id, utils.ErrNotFound)
+	}
+	return cloneCategory(category), nil
+}
+
+// GetAll 获取所有分类，按名称排序
+func (r *MemoryCategoryRepository) GetAll() ([]*entity.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var categories []*entity.Category
+	for _, category := range r.store.categories {
+		if category.DeletedAt == nil {
+			categories = append(categories, category)
+		}
+	}
+	return sortCategoriesByName(categories), nil
+}
+
+// Update 更新分类，仅当版本号未变化时保存
+func (r *MemoryCategoryRepository) Update(category *entity.Category) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.categories[category.ID]
+	if !ok || stored.DeletedAt != nil || stored.Version != category.Version {
+		return fmt.Errorf("更新分类 %d 失败: %w", category.ID, repository.ErrVersionConflict)
+	}
+	if r.nameTaken(category.Name, category.ID) {
+		return fmt.Errorf("分类名称已存在: %w", utils.ErrConflict)
+	}
+
+	stored.Name = category.Name
+	stored.Description = category.Description
+	stored.ParentID = cloneID(category.ParentID)
+	stored.Version++
+
+	category.Version++
+	return nil
+}
+
+// Delete 将分类移入回收站，回收站中的分类仍占用分类名称
+func (r *MemoryCategoryRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if category, ok := r.store.categories[id]; ok && category.DeletedAt == nil {
+		now := time.Now()
+		category.DeletedAt = &now
+	}
+	return nil
+}
+
+// GetTrashedByID 根据ID获取回收站中的分类
+func (r *MemoryCategoryRepository) GetTrashedByID(id uint) (*entity.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	category, ok := r.store.categories[id]
+	if !ok || category.DeletedAt == nil {
+		return nil, fmt.Errorf("回收站中不存在分类: %d: %w", id, utils.ErrNotFound)
+	}
+	return cloneCategory(category), nil
+}
+
+// ListTrashed 分页获取回收站中的分类，按移入回收站的时间倒序
+func (r *MemoryCategoryRepository) ListTrashed(query *repository.TrashQuery) ([]*entity.Category, int64, error) {
+	query.Normalize()
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var categories []*entity.Category
+	for _, category := range r.store.categories {
+		if category.DeletedAt != nil {
+			categories = append(categories, category)
+		}
+	}
+	slices.SortFunc(categories, func(a, b *entity.Category) int {
+		return compareTrashed(a.DeletedAt, a.ID, b.DeletedAt, b.ID)
+	})
+	return cloneCategories(pageOf(categories, query.Offset(), query.PageSize)), int64(len(categories)), nil
+}
+
+// Restore 将分类移出回收站，父分类仍在回收站中时作为顶级分类显示
+func (r *MemoryCategoryRepository) Restore(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	category, ok := r.store.categories[id]
+	if !ok || category.DeletedAt == nil {
+		return fmt.Errorf("回收站中不存在分类: %d: %w", id, utils.ErrNotFound)
+	}
+	category.DeletedAt = nil
+	return nil
+}
+
+// PurgeTrashed 永久删除在before之前移入回收站的分类，子分类成为顶级分类
+func (r *MemoryCategoryRepository) PurgeTrashed(before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for id, category := range r.store.categories {
+		if trashedBefore(category.DeletedAt, before) {
+			r.store.purgeCategory(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// AddPostToCategory 将文章添加到分类，文章已在该分类中时返回冲突错误
+func (r *MemoryCategoryRepository) AddPostToCategory(postID, categoryID uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.posts[postID]; !ok {
+		return fmt.Errorf("添加文章到分类失败: 文章不存在: %d", postID)
+	}
+	if _, ok := r.store.categories[categoryID]; !ok {
+		return fmt.Errorf("添加文章到分类失败: 分类不存在: %d", categoryID)
+	}
+	if slices.Contains(r.store.postCategories[postID], categoryID) {
+		return fmt.Errorf("添加文章到分类失败: %w", utils.ErrConflict)
+	}
+	r.store.postCategories[postID] = append(r.store.postCategories[postID], categoryID)
+	return nil
+}
+
+// RemovePostFromCategory 从分类中移除文章
+func (r *MemoryCategoryRepository) RemovePostFromCategory(postID, categoryID uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.postCategories[postID] = slices.DeleteFunc(r.store.postCategories[postID], func(id uint) bool {
+		return id == categoryID
+	})
+	return nil
+}
+
+// GetCategoriesByPostID 获取文章的所有分类，按名称排序
+func (r *MemoryCategoryRepository) GetCategoriesByPostID(postID uint) ([]*entity.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var categories []*entity.Category
+	for _, id := range r.store.postCategories[postID] {
+		if category, ok := r.store.categories[id]; ok && category.DeletedAt == nil {
+			categories = append(categories, category)
+		}
+	}
+	return sortCategoriesByName(categories), nil
+}
+
+// nameTaken 判断分类名称是否已被excludeID以外的分类使用（包括回收站中的分类），调用方需持有锁
+func (r *MemoryCategoryRepository) nameTaken(name string, excludeID uint) bool {
+	for _, category := range r.store.categories {
+		if category.ID != excludeID && strings.EqualFold(category.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortCategoriesByName 按名称（不区分大小写）排序并复制分类
+func sortCategoriesByName(categories []*entity.Category) []*entity.Category {
+	slices.SortFunc(categories, func(a, b *entity.Category) int {
+		if c := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return cloneCategories(categories)
+}
+
+// cloneCategory 复制分类
+func cloneCategory(category *entity.Category) *entity.Category {
+	clone := *category
+	clone.ParentID = cloneID(category.ParentID)
+	clone.DeletedAt = cloneTime(category.DeletedAt)
+	return &clone
+}
+
+// cloneCategories 逐个复制分类
+func cloneCategories(categories []*entity.Category) []*entity.Category {
+	if len(categories) == 0 {
+		return nil
+	}
+	result := make([]*entity.Category, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, cloneCategory(category))
+	}
+	return result
+}
@@ -0,0 +1,249 @@
+package persistence
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+// MemoryCommentRepository 基于内存的评论存储库实现
+type MemoryCommentRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryCommentRepository 创建内存评论存储库
+func NewMemoryCommentRepository(store *MemoryStore) repository.CommentRepository {
+	return &MemoryCommentRepository{
+		store: store,
+	}
+}
+
+// Create 创建评论
+func (r *MemoryCommentRepository) Create(comment *entity.Comment) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.posts[comment.PostID]; !ok {
+		return fmt.Errorf("创建评论失败: 文章不存在: %d", comment.PostID)
+	}
+
+	comment.ID = r.store.nextID("comments")
+	r.store.comments[comment.ID] = cloneComment(comment)
+	return nil
+}
+
+// GetByID 根据ID获取评论
+func (r *MemoryCommentRepository) GetByID(id uint) (*entity.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comment, ok := r.store.comments[id]
+	if !ok || !r.visible(comment) {
+		return nil, fmt.Errorf("评论不存在: %d: %w", id, utils.ErrNotFound)
+	}
+	return cloneComment(comment), nil
+}
+
+// GetByIDs 根据ID批量获取评论，按ID排序
+func (r *MemoryCommentRepository) GetByIDs(ids []uint) ([]*entity.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comments := r.filter(func(comment *entity.Comment) bool {
+		return slices.Contains(ids, comment.ID) && r.visible(comment)
+	})
+	slices.SortFunc(comments, func(a, b *entity.Comment) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return cloneComments(comments), nil
+}
+
+// GetByPostID 根据文章ID获取已通过审核的顶级评论，page不为nil时按游标分页
+func (r *MemoryCommentRepository) GetByPostID(postID uint, page *repository.CursorQuery) ([]*entity.Comment, bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comments := r.filter(func(comment *entity.Comment) bool {
+		return comment.PostID == postID && comment.ParentID == nil && comment.IsApproved() && r.visible(comment)
+	})
+	if page == nil {
+		slices.SortFunc(comments, func(a, b *entity.Comment) int {
+			return compareCreated(b.CreatedAt, b.ID, a.CreatedAt, a.ID)
+		})
+		return cloneComments(comments), false, nil
+	}
+
+	page.Normalize()
+	comments, hasMore := cursorPage(comments, page, func(comment *entity.Comment) (time.Time, uint) {
+		return comment.CreatedAt, comment.ID
+	})
+	return cloneComments(comments), hasMore, nil
+}
+
+// GetReplies 逐层获取指定评论下已通过审核的所有回复，按创建时间正序
+func (r *MemoryCommentRepository) GetReplies(parentIDs []uint) ([]*entity.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var replies []*entity.Comment
+	parents := make(map[uint]bool, len(parentIDs))
+	for _, id := range parentIDs {
+		parents[id] = true
+	}
+	for len(parents) > 0 {
+		children := make(map[uint]bool)
+		for _, comment := range r.store.comments {
+			if comment.ParentID != nil && parents[*comment.ParentID] && comment.IsApproved() && comment.DeletedAt == nil {
+				replies = append(replies, comment)
+				children[comment.ID] = true
+			}
+		}
+		parents = children
+	}
+
+	slices.SortFunc(replies, func(a, b *entity.Comment) int {
+		return compareCreated(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+	return cloneComments(replies), nil
+}
+
+// List 按条件分页获取评论，按创建时间正序
+func (r *MemoryCommentRepository) List(query *repository.CommentQuery) ([]*entity.Comment, int64, error) {
+	query.Normalize()
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comments := r.filter(func(comment *entity.Comment) bool {
+		return r.visible(comment) &&
+			(query.Status == "" || comment.Status == query.Status) &&
+			(query.PostID == 0 || comment.PostID == query.PostID)
+	})
+	slices.SortFunc(comments, func(a, b *entity.Comment) int {
+		return compareCreated(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+	return cloneComments(pageOf(comments, query.Offset(), query.PageSize)), int64(len(comments)), nil
+}
+
+// UpdateStatus 批量修改评论状态，返回匹配的评论数量
+func (r *MemoryCommentRepository) UpdateStatus(ids []uint, status entity.CommentStatus) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var affected int64
+	for _, id := range ids {
+		if comment, ok := r.store.comments[id]; ok && comment.DeletedAt == nil {
+			comment.Status = status
+			affected++
+		}
+	}
+	return affected, nil
+}
+
+// Delete 将评论移入回收站，回复的查询从已通过审核的父评论逐层进行，因此其下的回复随之隐藏
+func (r *MemoryCommentRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if comment, ok := r.store.comments[id]; ok && comment.DeletedAt == nil {
+		now := time.Now()
+		comment.DeletedAt = &now
+	}
+	return nil
+}
+
+// GetTrashedByID 根据ID获取回收站中的评论
+func (r *MemoryCommentRepository) GetTrashedByID(id uint) (*entity.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comment, ok := r.store.comments[id]
+	if !ok || comment.DeletedAt == nil {
+		return nil, fmt.Errorf("回收站中不存在评论: %d: %w", id, utils.ErrNotFound)
+	}
+	return cloneComment(comment), nil
+}
+
+// ListTrashed 分页获取回收站中的评论，按移入回收站的时间倒序
+func (r *MemoryCommentRepository) ListTrashed(query *repository.TrashQuery) ([]*entity.Comment, int64, error) {
+	query.Normalize()
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comments := r.filter(func(comment *entity.Comment) bool { return comment.DeletedAt != nil })
+	slices.SortFunc(comments, func(a, b *entity.Comment) int {
+		return compareTrashed(a.DeletedAt, a.ID, b.DeletedAt, b.ID)
+	})
+	return cloneComments(pageOf(comments, query.Offset(), query.PageSize)), int64(len(comments)), nil
+}
+
+// Restore 将评论移出回收站
+func (r *MemoryCommentRepository) Restore(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	comment, ok := r.store.comments[id]
+	if !ok || comment.DeletedAt == nil {
+		return fmt.Errorf("回收站中不存在评论: %d: %w", id, utils.ErrNotFound)
+	}
+	comment.DeletedAt = nil
+	return nil
+}
+
+// PurgeTrashed 永久删除在before之前移入回收站的评论，回复一并删除，返回的数量不含回复
+func (r *MemoryCommentRepository) PurgeTrashed(before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var ids []uint
+	for id, comment := range r.store.comments {
+		if trashedBefore(comment.DeletedAt, before) {
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range ids {
+		r.store.purgeComment(id)
+	}
+	return int64(len(ids)), nil
+}
+
+// visible 判断评论及其所属文章都不在回收站中，调用方需持有锁
+func (r *MemoryCommentRepository) visible(comment *entity.Comment) bool {
+	post, ok := r.store.posts[comment.PostID]
+	return comment.DeletedAt == nil && ok && post.DeletedAt == nil
+}
+
+// filter 返回满足条件的评论，调用方需持有锁，返回的评论不能修改
+func (r *MemoryCommentRepository) filter(match func(comment *entity.Comment) bool) []*entity.Comment {
+	var comments []*entity.Comment
+	for _, comment := range r.store.comments {
+		if match(comment) {
+			comments = append(comments, comment)
+		}
+	}
+	return comments
+}
+
+// cloneComment 复制评论
+func cloneComment(comment *entity.Comment) *entity.Comment {
+	clone := *comment
+	clone.ParentID = cloneID(comment.ParentID)
+	clone.DeletedAt = cloneTime(comment.DeletedAt)
+	return &clone
+}
+
+// cloneComments 逐个复制评论
+func cloneComments(comments []*entity.Comment) []*entity.Comment {
+	if len(comments) == 0 {
+		return nil
+	}
+	result := make([]*entity.Comment, 0, len(comments))
+	for _, comment := range comments {
+		result = append(result, cloneComment(comment))
+	}
+	return result
+}
@@ -0,0 +1,395 @@
+package persistence
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+// MemoryPostRepository 基于内存的文章存储库实现
+type MemoryPostRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryPostRepository 创建内存文章存储库
+func NewMemoryPostRepository(store *MemoryStore) repository.PostRepository {
+	return &MemoryPostRepository{
+		store: store,
+	}
+}
+
+// Create 创建文章
+func (r *MemoryPostRepository) Create(post *entity.Post) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.slugTaken(post.Slug, 0) {
+		return fmt.Errorf("slug已被使用: %w", utils.ErrConflict)
+	}
+
+	post.ID = r.store.nextID("posts")
+	r.store.posts[post.ID] = clonePost(post)
+	return nil
+}
+
+// GetByID 根据ID获取文章
+func (r *MemoryPostRepository) GetByID(id uint) (*entity.Post, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	post, ok := r.store.posts[id]
+	if !ok || post.DeletedAt != nil {
+		return nil, fmt.Errorf("文章不存在: %d: %w", id, utils.ErrNotFound)
+	}
+	return clonePost(post), nil
+}
+
+// GetBySlug 根据slug获取文章
+func (r *MemoryPostRepository) GetBySlug(slug string) (*entity.Post, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, post := range r.store.posts {
+		if post.Slug != "" && post.Slug == slug && post.DeletedAt == nil {
+			return clonePost(post), nil
+		}
+	}
+	return nil, fmt.Errorf("文章不存在: %s: %w", slug, utils.ErrNotFound)
+}
+
+// SlugExists 判断slug是否已被其他文章使用，回收站中的文章同样计入
+func (r *MemoryPostRepository) SlugExists(slug string, excludeID uint) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.slugTaken(slug, excludeID), nil
+}
+
+// GetAll 获取所有文章，按创建时间倒序
+func (r *MemoryPostRepository) GetAll() ([]*entity.Post, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	posts := r.filter(func(post *entity.Post) bool { return post.DeletedAt == nil })
+	sortPosts(posts, repository.SortByCreatedAt, false)
+	return clonePosts(posts, clonePost), nil
+}
+
+// Update 更新文章，仅当版本号未变化时保存，阅读量由IncrementViewCount单独维护
+func (r *MemoryPostRepository) Update(post *entity.Post) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.posts[post.ID]
+	if !ok || stored.DeletedAt != nil || stored.Version != post.Version {
+		return fmt.Errorf("更新文章 %d 失败: %w", post.ID, repository.ErrVersionConflict)
+	}
+	if r.slugTaken(post.Slug, post.ID) {
+		return fmt.Errorf("slug已被使用: %w", utils.ErrConflict)
+	}
+
+	now := time.Now()
+	stored.Title = post.Title
+	stored.Content = post.Content
+	stored.TitleURL = post.TitleURL
+	stored.Slug = post.Slug
+	stored.Status = post.Status
+	stored.PublishedAt = cloneTime(post.PublishedAt)
+	stored.UpdatedAt = now
+	stored.Version++
+
+	post.UpdatedAt = now
+	post.Version++
+	return nil
+}
+
+// IncrementViewCount 增加文章阅读量，不修改版本号和更新时间
+func (r *MemoryPostRepository) IncrementViewCount(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if post, ok := r.store.posts[id]; ok {
+		post.ViewCount++
+	}
+	return nil
+}
+
+// Delete 将文章移入回收站
+func (r *MemoryPostRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if post, ok := r.store.posts[id]; ok && post.DeletedAt == nil {
+		now := time.Now()
+		post.DeletedAt = &now
+	}
+	return nil
+}
+
+// GetTrashedByID 根据ID获取回收站中的文章
+func (r *MemoryPostRepository) GetTrashedByID(id uint) (*entity.Post, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	post, ok := r.store.posts[id]
+	if !ok || post.DeletedAt == nil {
+		return nil, fmt.Errorf("回收站中不存在文章: %d: %w", id, utils.ErrNotFound)
+	}
+	return clonePost(post), nil
+}
+
+// ListTrashed 分页获取回收站中的文章，按移入回收站的时间倒序
+func (r *MemoryPostRepository) ListTrashed(query *repository.TrashQuery) ([]*entity.Post, int64, error) {
+	query.Normalize()
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	posts := r.filter(func(post *entity.Post) bool {
+		return post.DeletedAt != nil && (query.Author == "" || post.Author == query.Author)
+	})
+	slices.SortFunc(posts, func(a, b *entity.Post) int {
+		return compareTrashed(a.DeletedAt, a.ID, b.DeletedAt, b.ID)
+	})
+	return clonePosts(pageOf(posts, query.Offset(), query.PageSize), postSummary), int64(len(posts)), nil
+}
+
+// Restore 将文章移出回收站
+func (r *MemoryPostRepository) Restore(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	post, ok := r.store.posts[id]
+	if !ok || post.DeletedAt == nil {
+		return fmt.Errorf("回收站中不存在文章: %d: %w", id, utils.ErrNotFound)
+	}
+	post.DeletedAt = nil
+	return nil
+}
+
+// PurgeTrashed 永久删除在before之前移入回收站的文章，评论、修订版本等关联数据一并删除
+func (r *MemoryPostRepository) PurgeTrashed(before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for id, post := range r.store.posts {
+		if trashedBefore(post.DeletedAt, before) {
+			r.store.purgePost(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// List 按条件分页获取文章列表
+func (r *MemoryPostRepository) List(query *repository.PostQuery) ([]*entity.Post, int64, error) {
+	query.Normalize()
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	posts := r.filter(r.matcher(query))
+	sortPosts(posts, query.SortBy, query.Ascending)
+	return clonePosts(pageOf(posts, query.Offset(), query.PageSize), postSummary), int64(len(posts)), nil
+}
+
+// ListByCursor 按创建时间倒序进行游标分页
+func (r *MemoryPostRepository) ListByCursor(query *repository.PostQuery, page *repository.CursorQuery) ([]*entity.Post, bool, error) {
+	query.Normalize()
+	page.Normalize()
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	posts, hasMore := cursorPage(r.filter(r.matcher(query)), page, func(post *entity.Post) (time.Time, uint) {
+		return post.CreatedAt, post.ID
+	})
+	return clonePosts(posts, postSummary), hasMore, nil
+}
+
+// Search 与SQLite方言一致，要求标题或正文包含关键词中的每个词（不区分大小写），标题命中得2分，正文命中得1分
+func (r *MemoryPostRepository) Search(query *repository.PostSearchQuery) ([]*repository.PostSearchHit, int64, error) {
+	query.Normalize()
+	terms := strings.Fields(strings.ToLower(query.Keyword))
+	if len(terms) == 0 {
+		return nil, 0, nil
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	match := r.matcher(&query.PostQuery)
+	var hits []*repository.PostSearchHit
+	for _, post := range r.store.posts {
+		if !match(post) {
+			continue
+		}
+		if score, ok := scorePost(post, terms); ok {
+			hits = append(hits, &repository.PostSearchHit{Post: post, Score: score})
+		}
+	}
+	slices.SortFunc(hits, func(a, b *repository.PostSearchHit) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Post.ID, a.Post.ID)
+	})
+
+	page := pageOf(hits, query.Offset(), query.PageSize)
+	for i, hit := range page {
+		page[i] = &repository.PostSearchHit{Post: clonePost(hit.Post), Score: hit.Score}
+	}
+	return page, int64(len(hits)), nil
+}
+
+// GetDueScheduled 获取发布时间已到的定时发布文章，按发布时间排序
+func (r *MemoryPostRepository) GetDueScheduled(now time.Time) ([]*entity.Post, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	posts := r.filter(func(post *entity.Post) bool {
+		return post.DeletedAt == nil && post.Status == entity.PostStatusScheduled && post.PublishedAt != nil && !post.PublishedAt.After(now)
+	})
+	slices.SortFunc(posts, func(a, b *entity.Post) int {
+		if c := a.PublishedAt.Compare(*b.PublishedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return clonePosts(posts, clonePost), nil
+}
+
+// slugTaken 判断slug是否已被excludeID以外的文章使用，空slug不占用，调用方需持有锁
+func (r *MemoryPostRepository) slugTaken(slug string, excludeID uint) bool {
+	if slug == "" {
+		return false
+	}
+	for _, post := range r.store.posts {
+		if post.Slug == slug && post.ID != excludeID {
+			return true
+		}
+	}
+	return false
+}
+
+// filter 返回满足条件的文章，调用方需持有锁，返回的文章不能修改
+func (r *MemoryPostRepository) filter(match func(post *entity.Post) bool) []*entity.Post {
+	var posts []*entity.Post
+	for _, post := range r.store.posts {
+		if match(post) {
+			posts = append(posts, post)
+		}
+	}
+	return posts
+}
+
+// matcher 返回与buildPostFilter等价的筛选函数，始终排除回收站中的文章，调用方需持有锁
+func (r *MemoryPostRepository) matcher(query *repository.PostQuery) func(post *entity.Post) bool {
+	var categories map[uint]bool
+	if query.CategoryID != 0 {
+		categories = map[uint]bool{query.CategoryID: true}
+		if query.IncludeDescendants {
+			categories = r.store.categoryTree(query.CategoryID)
+		}
+	}
+	var tagID uint
+	if query.Tag != "" {
+		for _, tag := range r.store.tags {
+			if strings.EqualFold(tag.Name, query.Tag) {
+				tagID = tag.ID
+			}
+		}
+	}
+
+	return func(post *entity.Post) bool {
+		switch {
+		case post.DeletedAt != nil:
+			return false
+		case query.Status != "" && post.Status != query.Status:
+			return false
+		case query.Author != "" && post.Author != query.Author:
+			return false
+		case categories != nil && !slices.ContainsFunc(r.store.postCategories[post.ID], func(id uint) bool { return categories[id] }):
+			return false
+		case query.Tag != "" && (tagID == 0 || !slices.Contains(r.store.postTags[post.ID], tagID)):
+			return false
+		case !query.CreatedFrom.IsZero() && post.CreatedAt.Before(query.CreatedFrom):
+			return false
+		case !query.CreatedTo.IsZero() && !post.CreatedAt.Before(query.CreatedTo):
+			return false
+		}
+		return true
+	}
+}
+
+// sortPosts 按排序字段和ID排序文章
+func sortPosts(posts []*entity.Post, field repository.PostSortField, ascending bool) {
+	slices.SortFunc(posts, func(a, b *entity.Post) int {
+		var c int
+		switch field {
+		case repository.SortByUpdatedAt:
+			c = a.UpdatedAt.Compare(b.UpdatedAt)
+		case repository.SortByViewCount:
+			c = cmp.Compare(a.ViewCount, b.ViewCount)
+		default:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		if c == 0 {
+			c = cmp.Compare(a.ID, b.ID)
+		}
+		if !ascending {
+			c = -c
+		}
+		return c
+	})
+}
+
+// scorePost 计算文章对所有词的相关度得分，有词未命中时返回false
+func scorePost(post *entity.Post, terms []string) (float64, bool) {
+	title, content := strings.ToLower(post.Title), strings.ToLower(post.Content)
+	var score float64
+	for _, term := range terms {
+		inTitle, inContent := strings.Contains(title, term), strings.Contains(content, term)
+		if !inTitle && !inContent {
+			return 0, false
+		}
+		if inTitle {
+			score += 2
+		}
+		if inContent {
+			score++
+		}
+	}
+	return score, true
+}
+
+// clonePost 复制文章
+func clonePost(post *entity.Post) *entity.Post {
+	clone := *post
+	clone.PublishedAt = cloneTime(post.PublishedAt)
+	clone.DeletedAt = cloneTime(post.DeletedAt)
+	return &clone
+}
+
+// postSummary 复制不含正文的文章，与列表查询的postSummaryColumns一致
+func postSummary(post *entity.Post) *entity.Post {
+	summary := clonePost(post)
+	summary.Content = ""
+	return summary
+}
+
+// clonePosts 逐个复制文章
+func clonePosts(posts []*entity.Post, clone func(*entity.Post) *entity.Post) []*entity.Post {
+	if len(posts) == 0 {
+		return nil
+	}
+	result := make([]*entity.Post, 0, len(posts))
+	for _, post := range posts {
+		result = append(result, clone(post))
+	}
+	return result
+}
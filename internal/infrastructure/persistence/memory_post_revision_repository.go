@@ -0,0 +1,78 @@
+package persistence
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+// MemoryPostRevisionRepository 基于内存的文章修订版本存储库实现
+type MemoryPostRevisionRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryPostRevisionRepository 创建内存文章修订版本存储库
+func NewMemoryPostRevisionRepository(store *MemoryStore) repository.PostRevisionRepository {
+	return &MemoryPostRevisionRepository{
+		store: store,
+	}
+}
+
+// Create 保存修订版本，版本号为该文章当前最大版本号加1
+func (r *MemoryPostRevisionRepository) Create(revision *entity.PostRevision) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.posts[revision.PostID]; !ok {
+		return fmt.Errorf("保存文章修订版本失败: 文章不存在: %d", revision.PostID)
+	}
+
+	latest := 0
+	for _, existing := range r.store.revisions {
+		if existing.PostID == revision.PostID {
+			latest = max(latest, existing.Revision)
+		}
+	}
+
+	revision.ID = r.store.nextID("post_revisions")
+	revision.Revision = latest + 1
+	clone := *revision
+	r.store.revisions[revision.ID] = &clone
+	return nil
+}
+
+// GetByRevision 根据文章ID和版本号获取修订版本
+func (r *MemoryPostRevisionRepository) GetByRevision(postID uint, revision int) (*entity.PostRevision, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, postRevision := range r.store.revisions {
+		if postRevision.PostID == postID && postRevision.Revision == revision {
+			clone := *postRevision
+			return &clone, nil
+		}
+	}
+	return nil, fmt.Errorf("文章修订版本不存在: %d: %w", revision, utils.ErrNotFound)
+}
+
+// ListByPostID 获取文章的所有修订版本，按版本号倒序
+func (r *MemoryPostRevisionRepository) ListByPostID(postID uint) ([]*entity.PostRevision, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var revisions []*entity.PostRevision
+	for _, revision := range r.store.revisions {
+		if revision.PostID == postID {
+			clone := *revision
+			revisions = append(revisions, &clone)
+		}
+	}
+	slices.SortFunc(revisions, func(a, b *entity.PostRevision) int {
+		return cmp.Compare(b.Revision, a.Revision)
+	})
+	return revisions, nil
+}
@@ -0,0 +1,122 @@
+package persistence
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+// MemorySlugRedirectRepository 基于内存的slug重定向存储库实现
+type MemorySlugRedirectRepository struct {
+	store *MemoryStore
+}
+
+// NewMemorySlugRedirectRepository 创建内存slug重定向存储库
+func NewMemorySlugRedirectRepository(store *MemoryStore) repository.SlugRedirectRepository {
+	return &MemorySlugRedirectRepository{
+		store: store,
+	}
+}
+
+// Save 保存重定向，旧slug已存在时改为指向新的文章，ID不变
+func (r *MemorySlugRedirectRepository) Save(redirect *entity.SlugRedirect) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.posts[redirect.PostID]; !ok {
+		return fmt.Errorf("保存slug重定向失败: 文章不存在: %d", redirect.PostID)
+	}
+
+	if existing := r.findBySlug(redirect.OldSlug); existing != nil {
+		existing.PostID = redirect.PostID
+		existing.CreatedAt = redirect.CreatedAt
+		redirect.ID = existing.ID
+		return nil
+	}
+
+	redirect.ID = r.store.nextID("post_slug_redirects")
+	clone := *redirect
+	r.store.redirects[redirect.ID] = &clone
+	return nil
+}
+
+// GetByID 根据ID获取重定向
+func (r *MemorySlugRedirectRepository) GetByID(id uint) (*entity.SlugRedirect, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	redirect, ok := r.store.redirects[id]
+	if !ok {
+		return nil, fmt.Errorf("slug重定向不存在: %d: %w", id, utils.ErrNotFound)
+	}
+	clone := *redirect
+	return &clone, nil
+}
+
+// GetBySlug 根据旧slug获取重定向
+func (r *MemorySlugRedirectRepository) GetBySlug(oldSlug string) (*entity.SlugRedirect, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	redirect := r.findBySlug(oldSlug)
+	if redirect == nil {
+		return nil, fmt.Errorf("slug重定向不存在: %s: %w", oldSlug, utils.ErrNotFound)
+	}
+	clone := *redirect
+	return &clone, nil
+}
+
+// List 获取重定向列表，postID为0时返回全部，按创建时间倒序
+func (r *MemorySlugRedirectRepository) List(postID uint) ([]*entity.SlugRedirect, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var redirects []*entity.SlugRedirect
+	for _, redirect := range r.store.redirects {
+		if postID == 0 || redirect.PostID == postID {
+			clone := *redirect
+			redirects = append(redirects, &clone)
+		}
+	}
+	slices.SortFunc(redirects, func(a, b *entity.SlugRedirect) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	return redirects, nil
+}
+
+// Delete 删除重定向
+func (r *MemorySlugRedirectRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.redirects, id)
+	return nil
+}
+
+// DeleteBySlug 根据旧slug删除重定向
+func (r *MemorySlugRedirectRepository) DeleteBySlug(oldSlug string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if redirect := r.findBySlug(oldSlug); redirect != nil {
+		delete(r.store.redirects, redirect.ID)
+	}
+	return nil
+}
+
+// findBySlug 根据旧slug查找重定向，调用方需持有锁
+func (r *MemorySlugRedirectRepository) findBySlug(oldSlug string) *entity.SlugRedirect {
+	for _, redirect := range r.store.redirects {
+		if redirect.OldSlug == oldSlug {
+			return redirect
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package persistence
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+)
+
+// MemoryStore 内存存储，所有内存存储库共享同一份数据，关联查询和级联删除的行为与数据库一致
+// 数据只保存在进程内，重启后丢失，用于测试和演示；保存和读取时都会复制实体，修改返回值不影响已保存的数据
+type MemoryStore struct {
+	mu sync.RWMutex
+	// lastID 各表最后分配的ID
+	lastID     map[string]uint
+	posts      map[uint]*entity.Post
+	comments   map[uint]*entity.Comment
+	categories map[uint]*entity.Category
+	tags       map[uint]*entity.Tag
+	redirects  map[uint]*entity.SlugRedirect
+	revisions  map[uint]*entity.PostRevision
+	users      map[uint]*entity.User
+	// postCategories 文章ID到所属分类ID的映射
+	postCategories map[uint][]uint
+	// postTags 文章ID到标签ID的映射
+	postTags map[uint][]uint
+}
+
+// NewMemoryStore 创建空的内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		lastID:         make(map[string]uint),
+		posts:          make(map[uint]*entity.Post),
+		comments:       make(map[uint]*entity.Comment),
+		categories:     make(map[uint]*entity.Category),
+		tags:           make(map[uint]*entity.Tag),
+		redirects:      make(map[uint]*entity.SlugRedirect),
+		revisions:      make(map[uint]*entity.PostRevision),
+		users:          make(map[uint]*entity.User),
+		postCategories: make(map[uint][]uint),
+		postTags:       make(map[uint][]uint),
+	}
+}
+
+// nextID 分配table的下一个自增ID，调用方需持有写锁
+func (s *MemoryStore) nextID(table string) uint {
+	s.lastID[table]++
+	return s.lastID[table]
+}
+
+// categoryTree 返回分类及其所有子孙分类的ID，与categoryDescendantsCTE一样包含回收站中的分类，调用方需持有锁
+func (s *MemoryStore) categoryTree(rootID uint) map[uint]bool {
+	tree := map[uint]bool{rootID: true}
+	for changed := true; changed; {
+		changed = false
+		for _, category := range s.categories {
+			if category.ParentID != nil && tree[*category.ParentID] && !tree[category.ID] {
+				tree[category.ID] = true
+				changed = true
+			}
+		}
+	}
+	return tree
+}
+
+// purgePost 永久删除文章及其评论、分类和标签关联、修订版本和slug重定向，调用方需持有写锁
+func (s *MemoryStore) purgePost(id uint) {
+	delete(s.posts, id)
+	delete(s.postCategories, id)
+	delete(s.postTags, id)
+	for commentID, comment := range s.comments {
+		if comment.PostID == id {
+			delete(s.comments, commentID)
+		}
+	}
+	for revisionID, revision := range s.revisions {
+		if revision.PostID == id {
+			delete(s.revisions, revisionID)
+		}
+	}
+	for redirectID, redirect := range s.redirects {
+		if redirect.PostID == id {
+			delete(s.redirects, redirectID)
+		}
+	}
+}
+
+// purgeComment 永久删除评论及其所有回复，调用方需持有写锁
+func (s *MemoryStore) purgeComment(id uint) {
+	delete(s.comments, id)
+	for replyID, reply := range s.comments {
+		if reply.ParentID != nil && *reply.ParentID == id {
+			s.purgeComment(replyID)
+		}
+	}
+}
+
+// purgeCategory 永久删除分类，子分类成为顶级分类，文章与该分类的关联一并删除，调用方需持有写锁
+func (s *MemoryStore) purgeCategory(id uint) {
+	delete(s.categories, id)
+	for _, category := range s.categories {
+		if category.ParentID != nil && *category.ParentID == id {
+			category.ParentID = nil
+		}
+	}
+	for postID, categoryIDs := range s.postCategories {
+		s.postCategories[postID] = slices.DeleteFunc(categoryIDs, func(categoryID uint) bool {
+			return categoryID == id
+		})
+	}
+}
+
+// trashedBefore 判断记录是否在before之前移入回收站
+func trashedBefore(deletedAt *time.Time, before time.Time) bool {
+	return deletedAt != nil && deletedAt.Before(before)
+}
+
+// compareTrashed 按移入回收站的时间和ID倒序比较，与SQL存储库的ORDER BY deleted_at DESC, id DESC一致
+func compareTrashed(aDeletedAt *time.Time, aID uint, bDeletedAt *time.Time, bID uint) int {
+	if c := bDeletedAt.Compare(*aDeletedAt); c != 0 {
+		return c
+	}
+	return cmp.Compare(bID, aID)
+}
+
+// compareCreated 按(created_at, id)正序比较
+func compareCreated(aCreatedAt time.Time, aID uint, bCreatedAt time.Time, bID uint) int {
+	if c := aCreatedAt.Compare(bCreatedAt); c != 0 {
+		return c
+	}
+	return cmp.Compare(aID, bID)
+}
+
+// cursorPage 与keysetClause对应的内存实现：按(created_at, id)倒序对items进行游标分页，返回当前页以及翻页方向上是否还有更多数据
+func cursorPage[T any](items []T, page *repository.CursorQuery, key func(T) (time.Time, uint)) ([]T, bool) {
+	cursor := page.Cursor
+	var result []T
+	for _, item := range items {
+		createdAt, id := key(item)
+		if cursor != nil {
+			c := compareCreated(createdAt, id, cursor.CreatedAt, cursor.ID)
+			if (!cursor.Backward && c >= 0) || (cursor.Backward && c <= 0) {
+				continue
+			}
+		}
+		result = append(result, item)
+	}
+
+	backward := cursor != nil && cursor.Backward
+	slices.SortFunc(result, func(a, b T) int {
+		aCreatedAt, aID := key(a)
+		bCreatedAt, bID := key(b)
+		if backward {
+			return compareCreated(aCreatedAt, aID, bCreatedAt, bID)
+		}
+		return compareCreated(bCreatedAt, bID, aCreatedAt, aID)
+	})
+
+	hasMore := len(result) > page.Limit
+	if hasMore {
+		result = result[:page.Limit]
+	}
+	if backward {
+		slices.Reverse(result)
+	}
+	return result, hasMore
+}
+
+// pageOf 返回items中从offset开始的至多limit个元素
+func pageOf[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	return items[offset:min(offset+limit, len(items))]
+}
+
+// cloneTime 复制时间指针
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	clone := *t
+	return &clone
+}
+
+// cloneID 复制ID指针
+func cloneID(id *uint) *uint {
+	if id == nil {
+		return nil
+	}
+	clone := *id
+	return &clone
+}
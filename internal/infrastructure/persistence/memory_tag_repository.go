@@ -0,0 +1,155 @@
+package persistence
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+// MemoryTagRepository 基于内存的标签存储库实现
+type MemoryTagRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryTagRepository 创建内存标签存储库
+func NewMemoryTagRepository(store *MemoryStore) repository.TagRepository {
+	return &MemoryTagRepository{
+		store: store,
+	}
+}
+
+// GetOrCreate 根据名称获取标签，不存在的标签会自动创建，标签名不区分大小写
+func (r *MemoryTagRepository) GetOrCreate(names []string) ([]*entity.Tag, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	var tags []*entity.Tag
+	for _, name := range names {
+		tag := r.findByName(name)
+		if tag == nil {
+			tag = &entity.Tag{ID: r.store.nextID("tags"), Name: name, CreatedAt: now}
+			r.store.tags[tag.ID] = tag
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return sortTagsByName(tags), nil
+}
+
+// GetByName 根据名称获取标签
+func (r *MemoryTagRepository) GetByName(name string) (*entity.Tag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tag := r.findByName(name)
+	if tag == nil {
+		return nil, fmt.Errorf("标签不存在: %s: %w", name, utils.ErrNotFound)
+	}
+	clone := *tag
+	return &clone, nil
+}
+
+// GetByPostID 获取文章的所有标签，按名称排序
+func (r *MemoryTagRepository) GetByPostID(postID uint) ([]*entity.Tag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tags []*entity.Tag
+	for _, id := range r.store.postTags[postID] {
+		if tag, ok := r.store.tags[id]; ok {
+			tags = append(tags, tag)
+		}
+	}
+	return sortTagsByName(tags), nil
+}
+
+// SetPostTags 替换文章的全部标签
+func (r *MemoryTagRepository) SetPostTags(postID uint, tagIDs []uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.posts[postID]; !ok && len(tagIDs) > 0 {
+		return fmt.Errorf("保存文章标签失败: 文章不存在: %d", postID)
+	}
+	if len(tagIDs) == 0 {
+		delete(r.store.postTags, postID)
+		return nil
+	}
+	r.store.postTags[postID] = slices.Clone(tagIDs)
+	return nil
+}
+
+// GetCloud 获取标签云，只统计已发布的文章，按使用次数倒序
+func (r *MemoryTagRepository) GetCloud(limit int) ([]*repository.TagCount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[uint]int64)
+	for postID, tagIDs := range r.store.postTags {
+		post, ok := r.store.posts[postID]
+		if !ok || post.DeletedAt != nil || post.Status != entity.PostStatusPublished {
+			continue
+		}
+		for _, tagID := range tagIDs {
+			counts[tagID]++
+		}
+	}
+
+	var cloud []*repository.TagCount
+	for tagID, count := range counts {
+		if tag, ok := r.store.tags[tagID]; ok {
+			clone := *tag
+			cloud = append(cloud, &repository.TagCount{Tag: &clone, PostCount: count})
+		}
+	}
+	slices.SortFunc(cloud, func(a, b *repository.TagCount) int {
+		if c := cmp.Compare(b.PostCount, a.PostCount); c != 0 {
+			return c
+		}
+		return compareTags(a.Tag, b.Tag)
+	})
+	if limit > 0 && len(cloud) > limit {
+		cloud = cloud[:limit]
+	}
+	return cloud, nil
+}
+
+// findByName 根据名称查找标签，调用方需持有锁
+func (r *MemoryTagRepository) findByName(name string) *entity.Tag {
+	for _, tag := range r.store.tags {
+		if strings.EqualFold(tag.Name, name) {
+			return tag
+		}
+	}
+	return nil
+}
+
+// compareTags 按名称（不区分大小写）和ID比较标签
+func compareTags(a, b *entity.Tag) int {
+	if c := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// sortTagsByName 按名称排序并复制标签
+func sortTagsByName(tags []*entity.Tag) []*entity.Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+	slices.SortFunc(tags, compareTags)
+	result := make([]*entity.Tag, 0, len(tags))
+	for _, tag := range tags {
+		clone := *tag
+		result = append(result, &clone)
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package persistence
+
+import (
+	"fmt"
+	"strings"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+// MemoryUserRepository 基于内存的用户存储库实现
+type MemoryUserRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryUserRepository 创建内存用户存储库
+func NewMemoryUserRepository(store *MemoryStore) repository.UserRepository {
+	return &MemoryUserRepository{
+		store: store,
+	}
+}
+
+// Create 创建用户，用户名不区分大小写唯一
+func (r *MemoryUserRepository) Create(user *entity.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.findByUsername(user.Username) != nil {
+		return fmt.Errorf("用户名已存在: %w", utils.ErrConflict)
+	}
+
+	user.ID = r.store.nextID("users")
+	clone := *user
+	r.store.users[user.ID] = &clone
+	return nil
+}
+
+// CreateFirst 以firstUserID创建第一个用户
+func (r *MemoryUserRepository) CreateFirst(user *entity.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[firstUserID]; ok {
+		return fmt.Errorf("第一个用户已存在: %w", utils.ErrConflict)
+	}
+	if r.findByUsername(user.Username) != nil {
+		return fmt.Errorf("用户名已存在: %w", utils.ErrConflict)
+	}
+
+	user.ID = firstUserID
+	if r.store.lastID["users"] < firstUserID {
+		r.store.lastID["users"] = firstUserID
+	}
+	clone := *user
+	r.store.users[user.ID] = &clone
+	return nil
+}
+
+// GetByID 根据ID获取用户
+func (r *MemoryUserRepository) GetByID(id uint) (*entity.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return nil, fmt.Errorf("用户不存在: %d: %w", id, utils.ErrNotFound)
+	}
+	clone := *user
+	return &clone, nil
+}
+
+// GetByUsername 根据用户名获取用户
+func (r *MemoryUserRepository) GetByUsername(username string) (*entity.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user := r.findByUsername(username)
+	if user == nil {
+		return nil, fmt.Errorf("用户不存在: %s: %w", username, utils.ErrNotFound)
+	}
+	clone := *user
+	return &clone, nil
+}
+
+// Count 获取用户总数
+func (r *MemoryUserRepository) Count() (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return int64(len(r.store.users)), nil
+}
+
+// UpdateRole 更新用户角色
+func (r *MemoryUserRepository) UpdateRole(id uint, role entity.Role) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return fmt.Errorf("用户不存在: %d: %w", id, utils.ErrNotFound)
+	}
+	user.Role = role
+	return nil
+}
+
+// findByUsername 根据用户名查找用户，调用方需持有锁
+func (r *MemoryUserRepository) findByUsername(username string) *entity.User {
+	for _, user := range r.store.users {
+		if strings.EqualFold(user.Username, username) {
+			return user
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package migrations
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SourceDir 迁移文件在源码中的目录，create命令在该目录下为每种数据库驱动生成新文件
+const SourceDir = "internal/infrastructure/persistence/migrations"
+
+// Drivers 有迁移文件的数据库驱动，每种驱动的迁移放在同名目录下，版本号一一对应
+var Drivers = []string{"mysql", "sqlite", "postgres"}
+
+//go:embed mysql/*.sql sqlite/*.sql postgres/*.sql
+var files embed.FS
+
+// fileName 迁移文件名格式：<版本号>_<名称>.up.sql 或 <版本号>_<名称>.down.sql
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// migrationName 新建迁移时允许的名称
+var migrationName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Migration 一个版本的迁移，Up升级到该版本，Down回滚该版本
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Embedded 返回编译进程序的指定数据库驱动的迁移，按版本号升序
+func Embedded(driver string) ([]Migration, error) {
+	sub, err := fs.Sub(files, driver)
+	if err != nil {
+		return nil, fmt.Errorf("读取迁移文件失败: %w", err)
+	}
+	migrations, err := Load(sub)
+	if err != nil {
+		return nil, err
+	}
+	if len(migrations) == 0 {
+		return nil, fmt.Errorf("不支持的数据库驱动: %s", driver)
+	}
+	return migrations, nil
+}
+
+// Load 读取目录下的迁移文件，每个版本必须同时有up和down文件，版本号不能重复
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("读取迁移文件失败: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("迁移文件名不合法: %s", entry.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		if version < 1 {
+			return nil, fmt.Errorf("迁移版本号必须大于0: %s", entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("读取迁移文件 %s 失败: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("迁移版本号 %d 重复: %s 与 %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" || strings.TrimSpace(m.Down) == "" {
+			return nil, fmt.Errorf("迁移 %d_%s 缺少up或down文件", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Create 在root下每种数据库驱动的目录中生成下一个版本的空白迁移文件，返回生成的文件路径
+// 各驱动目录中已有迁移的版本号和名称必须一致，否则拒绝生成，避免各数据库的迁移互相错位
+func Create(root, name string) ([]string, error) {
+	if !migrationName.MatchString(name) {
+		return nil, fmt.Errorf("迁移名称只能包含小写字母、数字和下划线: %q", name)
+	}
+
+	var latest []Migration
+	for i, driver := range Drivers {
+		existing, err := Load(os.DirFS(filepath.Join(root, driver)))
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			if err := sameVersions(Drivers[0], latest, driver, existing); err != nil {
+				return nil, err
+			}
+		}
+		latest = existing
+	}
+	var version int64 = 1
+	if len(latest) > 0 {
+		version = latest[len(latest)-1].Version + 1
+	}
+
+	base := fmt.Sprintf("%04d_%s", version, name)
+	var paths []string
+	for _, driver := range Drivers {
+		dir := filepath.Join(root, driver)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("创建迁移目录失败: %w", err)
+		}
+		up := filepath.Join(dir, base+".up.sql")
+		down := filepath.Join(dir, base+".down.sql")
+		if err := os.WriteFile(up, []byte("-- "+name+"\n"), 0o644); err != nil {
+			return nil, fmt.Errorf("创建迁移文件失败: %w", err)
+		}
+		if err := os.WriteFile(down, []byte("-- 回滚 "+name+"\n"), 0o644); err != nil {
+			return nil, fmt.Errorf("创建迁移文件失败: %w", err)
+		}
+		paths = append(paths, up, down)
+	}
+	return paths, nil
+}
+
+// sameVersions 检查两种数据库驱动的迁移版本号和名称是否一一对应
+func sameVersions(driverA string, a []Migration, driverB string, b []Migration) error {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		switch {
+		case i >= len(a):
+			return fmt.Errorf("各数据库的迁移版本不一致: %s缺少迁移 %d_%s", driverA, b[i].Version, b[i].Name)
+		case i >= len(b):
+			return fmt.Errorf("各数据库的迁移版本不一致: %s缺少迁移 %d_%s", driverB, a[i].Version, a[i].Name)
+		case a[i].Version != b[i].Version || a[i].Name != b[i].Name:
+			return fmt.Errorf("各数据库的迁移版本不一致: %s为 %d_%s，%s为 %d_%s", driverA, a[i].Version, a[i].Name, driverB, b[i].Version, b[i].Name)
+		}
+	}
+	return nil
+}
+
+// splitStatements 将迁移脚本拆分为单条语句，语句以行尾的分号结束，忽略空行和--开头的注释行
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
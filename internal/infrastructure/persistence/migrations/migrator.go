@@ -0,0 +1,334 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// lockName 迁移使用的咨询锁名称，多个副本同时启动时只有一个执行迁移
+	lockName = "blog:schema_migrations"
+	// lockTimeout 等待其他副本完成迁移的最长时间，单位秒
+	lockTimeout = 60
+	// lockRetryInterval PostgreSQL等待迁移锁时的重试间隔
+	lockRetryInterval = time.Second
+)
+
+// createTableQueries 各数据库创建迁移记录表的语句
+var createTableQueries = map[string]string{
+	"mysql": `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT UNSIGNED NOT NULL PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	`,
+	"sqlite": `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER NOT NULL PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`,
+	"postgres": `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)
+	`,
+}
+
+// Status 迁移的执行状态，AppliedAt为空表示未执行
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+	// Missing 为true时表示数据库中已记录该版本，但程序中没有对应的迁移文件
+	Missing bool
+}
+
+// Migrator 数据库迁移执行器，已执行的版本记录在schema_migrations表中
+type Migrator struct {
+	db         *sql.DB
+	driver     string
+	migrations []Migration
+}
+
+// NewMigrator 创建迁移执行器，driver为数据库驱动名称，migrations需按版本号升序
+func NewMigrator(db *sql.DB, driver string, migrations []Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		driver:     driver,
+		migrations: migrations,
+	}
+}
+
+// Up 按版本号升序执行所有未执行的迁移，返回本次执行的迁移
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := versions[migration.Version]; ok {
+				continue
+			}
+			if err := m.run(ctx, conn, migration, migration.Up, true); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down 按版本号降序回滚最近执行的steps个迁移，返回本次回滚的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := versions[migration.Version]; !ok {
+				continue
+			}
+			if err := m.run(ctx, conn, migration, migration.Down, false); err != nil {
+				return err
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status 返回所有迁移的执行状态，按版本号升序
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withConn(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		known := make(map[int64]bool, len(m.migrations))
+		for _, migration := range m.migrations {
+			known[migration.Version] = true
+			status := Status{Migration: migration}
+			if appliedAt, ok := versions[migration.Version]; ok {
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		for version, appliedAt := range versions {
+			if !known[version] {
+				appliedAt := appliedAt
+				statuses = append(statuses, Status{Migration: Migration{Version: version}, AppliedAt: &appliedAt, Missing: true})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// Pending 返回未执行的迁移
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending = append(pending, status.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// run 在事务中执行迁移脚本并更新schema_migrations
+// MySQL的DDL语句会隐式提交事务，脚本中途失败时已执行的DDL不会回滚，需要人工处理后重试；
+// SQLite和PostgreSQL的DDL支持事务，失败时整个迁移回滚
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, migration Migration, script string, up bool) error {
+	action := "执行"
+	if !up {
+		action = "回滚"
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, statement := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("%s迁移 %d_%s 失败: %w", action, migration.Version, migration.Name, err)
+		}
+	}
+
+	if up {
+		_, err = tx.ExecContext(ctx, m.bind(`INSERT INTO schema_migrations (version, name) VALUES (?, ?)`), migration.Version, migration.Name)
+	} else {
+		_, err = tx.ExecContext(ctx, m.bind(`DELETE FROM schema_migrations WHERE version = ?`), migration.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("记录迁移 %d_%s 失败: %w", migration.Version, migration.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交迁移 %d_%s 失败: %w", migration.Version, migration.Name, err)
+	}
+	log.Printf("已%s迁移 %d_%s", action, migration.Version, migration.Name)
+	return nil
+}
+
+// withLock 持有迁移锁执行fn，其他副本正在迁移时等待其完成
+// 咨询锁与会话绑定，因此加锁、迁移和解锁使用同一个数据库连接；
+// SQLite只支持单实例部署，且写事务本身互斥，不需要额外加锁
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	return m.withConn(ctx, func(conn *sql.Conn) error {
+		var unlock func()
+		var err error
+		switch m.driver {
+		case "sqlite":
+			return fn(conn)
+		case "postgres":
+			unlock, err = lockPostgres(ctx, conn)
+		default:
+			unlock, err = lockMySQL(ctx, conn)
+		}
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		return fn(conn)
+	})
+}
+
+// lockMySQL 通过GET_LOCK获取迁移锁，最多等待lockTimeout秒
+func lockMySQL(ctx context.Context, conn *sql.Conn) (func(), error) {
+	var acquired sql.NullInt64
+	err := conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, ?)`, lockName, lockTimeout).Scan(&acquired)
+	if err != nil {
+		return nil, fmt.Errorf("获取迁移锁失败: %w", err)
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		return nil, errors.New("等待迁移锁超时，其他实例可能正在执行迁移")
+	}
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT RELEASE_LOCK(?)`, lockName); err != nil {
+			log.Printf("释放迁移锁失败: %v", err)
+		}
+	}, nil
+}
+
+// lockPostgres 通过pg_try_advisory_lock获取迁移锁，获取失败时每隔lockRetryInterval重试，最多等待lockTimeout秒
+// 不使用会阻塞的pg_advisory_lock，以免其他实例迁移卡住时无限等待
+func lockPostgres(ctx context.Context, conn *sql.Conn) (func(), error) {
+	deadline := time.Now().Add(lockTimeout * time.Second)
+	for {
+		var acquired bool
+		err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, lockName).Scan(&acquired)
+		if err != nil {
+			return nil, fmt.Errorf("获取迁移锁失败: %w", err)
+		}
+		if acquired {
+			break
+		}
+		if time.Now().Add(lockRetryInterval).After(deadline) {
+			return nil, errors.New("等待迁移锁超时，其他实例可能正在执行迁移")
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, lockName); err != nil {
+			log.Printf("释放迁移锁失败: %v", err)
+		}
+	}, nil
+}
+
+// withConn 获取独占的数据库连接并确保schema_migrations表存在
+func (m *Migrator) withConn(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("获取数据库连接失败: %w", err)
+	}
+	defer conn.Close()
+
+	query, ok := createTableQueries[m.driver]
+	if !ok {
+		return fmt.Errorf("不支持的数据库驱动: %s", m.driver)
+	}
+	if _, err = conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("创建迁移记录表失败: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// bind PostgreSQL使用$1、$2…编号占位符，将语句中的?依次改写
+func (m *Migrator) bind(query string) string {
+	if m.driver != "postgres" {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// appliedVersions 查询已执行的迁移版本及执行时间
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("查询已执行的迁移失败: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("读取已执行的迁移失败: %w", err)
+		}
+		versions[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取已执行的迁移失败: %w", err)
+	}
+	return versions, nil
+}
@@ -0,0 +1,5 @@
+-- comments.post_id的外键需要以post_id开头的索引，先补建外键索引再删除游标索引
+
+ALTER TABLE comments ADD INDEX idx_comments_post_id (post_id);
+ALTER TABLE comments DROP INDEX idx_comments_post_created_at_id;
+ALTER TABLE posts DROP INDEX idx_posts_created_at_id;
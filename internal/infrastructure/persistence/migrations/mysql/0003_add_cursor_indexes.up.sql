@@ -0,0 +1,4 @@
+-- 文章和评论游标分页使用的索引
+
+ALTER TABLE posts ADD INDEX idx_posts_created_at_id (created_at, id);
+ALTER TABLE comments ADD INDEX idx_comments_post_created_at_id (post_id, created_at, id);
@@ -0,0 +1,8 @@
+-- 文章状态和发布时间，已有文章视为已发布，发布时间取创建时间
+-- 显式设置updated_at，避免ON UPDATE CURRENT_TIMESTAMP改写已有文章的更新时间
+
+ALTER TABLE posts
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'draft' AFTER view_count,
+    ADD COLUMN published_at TIMESTAMP NULL DEFAULT NULL AFTER status;
+UPDATE posts SET status = 'published', published_at = created_at, updated_at = updated_at;
+ALTER TABLE posts ADD INDEX idx_posts_status_created_at (status, created_at, id);
@@ -0,0 +1,5 @@
+-- 文章slug，已有文章的slug由服务启动时补全
+
+ALTER TABLE posts
+    ADD COLUMN slug VARCHAR(100) NULL AFTER title_url,
+    ADD UNIQUE INDEX uk_posts_slug (slug);
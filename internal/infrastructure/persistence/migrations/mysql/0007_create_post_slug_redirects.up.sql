@@ -0,0 +1,9 @@
+-- 文章修改slug后，旧slug重定向到文章
+
+CREATE TABLE post_slug_redirects (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    old_slug VARCHAR(100) NOT NULL UNIQUE,
+    post_id INT UNSIGNED NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
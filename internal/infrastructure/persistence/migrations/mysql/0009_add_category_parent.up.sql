@@ -0,0 +1,5 @@
+-- 分类层级，删除父分类时子分类成为顶级分类
+
+ALTER TABLE categories
+    ADD COLUMN parent_id INT UNSIGNED NULL AFTER description,
+    ADD CONSTRAINT fk_categories_parent FOREIGN KEY (parent_id) REFERENCES categories(id) ON DELETE SET NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE comments DROP FOREIGN KEY fk_comments_parent;
+ALTER TABLE comments
+    DROP COLUMN depth,
+    DROP COLUMN parent_id;
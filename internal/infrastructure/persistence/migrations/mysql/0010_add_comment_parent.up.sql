@@ -0,0 +1,6 @@
+-- 评论回复，删除评论时其回复一并删除
+
+ALTER TABLE comments
+    ADD COLUMN parent_id INT UNSIGNED NULL AFTER post_id,
+    ADD COLUMN depth INT UNSIGNED NOT NULL DEFAULT 0 AFTER parent_id,
+    ADD CONSTRAINT fk_comments_parent FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+-- 评论审核状态，已有评论视为已通过审核
+
+ALTER TABLE comments ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'pending' AFTER author;
+UPDATE comments SET status = 'approved';
+ALTER TABLE comments ADD INDEX idx_comments_status_created_at (status, created_at, id);
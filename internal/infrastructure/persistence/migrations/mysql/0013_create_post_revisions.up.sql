@@ -0,0 +1,17 @@
+-- 文章修订版本，已有文章以当前内容作为第1版
+
+CREATE TABLE post_revisions (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    post_id INT UNSIGNED NOT NULL,
+    revision INT UNSIGNED NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    title_url VARCHAR(500) NOT NULL DEFAULT '',
+    author VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX uk_post_revisions_post_revision (post_id, revision),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO post_revisions (post_id, revision, title, content, title_url, author, created_at)
+SELECT id, 1, title, content, COALESCE(title_url, ''), author, updated_at FROM posts;
@@ -0,0 +1,2 @@
+ALTER TABLE categories DROP COLUMN version;
+ALTER TABLE posts DROP COLUMN version;
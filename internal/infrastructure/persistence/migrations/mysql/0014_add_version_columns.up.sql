@@ -0,0 +1,4 @@
+-- 文章和分类的乐观锁版本号
+
+ALTER TABLE posts ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER updated_at;
+ALTER TABLE categories ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER parent_id;
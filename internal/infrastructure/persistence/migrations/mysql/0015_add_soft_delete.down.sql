@@ -0,0 +1,9 @@
+ALTER TABLE categories
+    DROP INDEX idx_categories_deleted_at,
+    DROP COLUMN deleted_at;
+ALTER TABLE comments
+    DROP INDEX idx_comments_deleted_at,
+    DROP COLUMN deleted_at;
+ALTER TABLE posts
+    DROP INDEX idx_posts_deleted_at,
+    DROP COLUMN deleted_at;
@@ -0,0 +1,11 @@
+-- 软删除，deleted_at不为空的记录位于回收站
+
+ALTER TABLE posts
+    ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL AFTER version,
+    ADD INDEX idx_posts_deleted_at (deleted_at);
+ALTER TABLE comments
+    ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL AFTER created_at,
+    ADD INDEX idx_comments_deleted_at (deleted_at);
+ALTER TABLE categories
+    ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL AFTER version,
+    ADD INDEX idx_categories_deleted_at (deleted_at);
@@ -0,0 +1,6 @@
+-- 按外键依赖的逆序删除初始表，数据将全部丢失；citext扩展可能被其他数据库对象使用，不删除
+
+DROP TABLE IF EXISTS post_categories;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS posts;
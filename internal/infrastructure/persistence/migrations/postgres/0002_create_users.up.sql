@@ -0,0 +1,9 @@
+-- 用户表，新注册的用户默认为读者
+
+CREATE TABLE users (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    username CITEXT NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'reader',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
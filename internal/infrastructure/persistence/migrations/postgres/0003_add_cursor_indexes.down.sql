@@ -0,0 +1,2 @@
+DROP INDEX idx_comments_post_created_at_id;
+DROP INDEX idx_posts_created_at_id;
@@ -0,0 +1,4 @@
+DROP INDEX idx_posts_status_created_at;
+ALTER TABLE posts
+    DROP COLUMN published_at,
+    DROP COLUMN status;
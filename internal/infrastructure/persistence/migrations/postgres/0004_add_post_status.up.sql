@@ -0,0 +1,7 @@
+-- 文章状态和发布时间，已有文章视为已发布，发布时间取创建时间
+
+ALTER TABLE posts
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    ADD COLUMN published_at TIMESTAMPTZ NULL DEFAULT NULL;
+UPDATE posts SET status = 'published', published_at = created_at;
+CREATE INDEX idx_posts_status_created_at ON posts (status, created_at, id);
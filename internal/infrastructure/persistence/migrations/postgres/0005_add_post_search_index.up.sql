@@ -0,0 +1,6 @@
+-- posts.search_vector由标题（权重A）和正文（权重B）生成，用于全文搜索
+
+ALTER TABLE posts ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', content), 'B')
+) STORED;
+CREATE INDEX idx_posts_search_vector ON posts USING GIN (search_vector);
@@ -0,0 +1,3 @@
+ALTER TABLE posts
+    DROP CONSTRAINT uk_posts_slug,
+    DROP COLUMN slug;
@@ -0,0 +1,9 @@
+-- 文章修改slug后，旧slug重定向到文章
+
+CREATE TABLE post_slug_redirects (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    old_slug VARCHAR(100) NOT NULL UNIQUE,
+    post_id BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_post_slug_redirects_post_id ON post_slug_redirects (post_id);
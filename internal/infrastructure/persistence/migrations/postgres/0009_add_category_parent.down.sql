@@ -0,0 +1,3 @@
+-- 删除字段时其索引和外键约束一并删除
+
+ALTER TABLE categories DROP COLUMN parent_id;
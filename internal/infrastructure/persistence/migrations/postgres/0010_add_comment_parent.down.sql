@@ -0,0 +1,5 @@
+-- 删除字段时其索引和外键约束一并删除
+
+ALTER TABLE comments
+    DROP COLUMN depth,
+    DROP COLUMN parent_id;
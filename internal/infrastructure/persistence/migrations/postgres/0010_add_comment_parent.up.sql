@@ -0,0 +1,7 @@
+-- 评论回复，删除评论时其回复一并删除
+
+ALTER TABLE comments
+    ADD COLUMN parent_id BIGINT NULL,
+    ADD COLUMN depth INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT fk_comments_parent FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE;
+CREATE INDEX idx_comments_parent_id ON comments (parent_id);
@@ -0,0 +1,3 @@
+ALTER TABLE comments
+    DROP COLUMN user_agent,
+    DROP COLUMN author_ip;
@@ -0,0 +1,5 @@
+-- 评论者信息，用于垃圾评论检测
+
+ALTER TABLE comments
+    ADD COLUMN author_ip VARCHAR(45) NOT NULL DEFAULT '',
+    ADD COLUMN user_agent VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,16 @@
+-- 文章修订版本，已有文章以当前内容作为第1版
+
+CREATE TABLE post_revisions (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    post_id BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    title_url VARCHAR(500) NOT NULL DEFAULT '',
+    author VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uk_post_revisions_post_revision UNIQUE (post_id, revision)
+);
+
+INSERT INTO post_revisions (post_id, revision, title, content, title_url, author, created_at)
+SELECT id, 1, title, content, COALESCE(title_url, ''), author, updated_at FROM posts;
@@ -0,0 +1,4 @@
+-- 文章和分类的乐观锁版本号
+
+ALTER TABLE posts ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
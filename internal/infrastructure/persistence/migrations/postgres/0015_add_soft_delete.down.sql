@@ -0,0 +1,5 @@
+-- 删除字段时其索引一并删除
+
+ALTER TABLE categories DROP COLUMN deleted_at;
+ALTER TABLE comments DROP COLUMN deleted_at;
+ALTER TABLE posts DROP COLUMN deleted_at;
@@ -0,0 +1,4 @@
+-- 文章和评论游标分页使用的索引
+
+CREATE INDEX idx_posts_created_at_id ON posts (created_at, id);
+CREATE INDEX idx_comments_post_created_at_id ON comments (post_id, created_at, id);
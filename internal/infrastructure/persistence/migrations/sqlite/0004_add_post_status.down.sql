@@ -0,0 +1,3 @@
+DROP INDEX idx_posts_status_created_at;
+ALTER TABLE posts DROP COLUMN published_at;
+ALTER TABLE posts DROP COLUMN status;
@@ -0,0 +1 @@
+-- SQLite没有需要回滚的全文索引
@@ -0,0 +1 @@
+-- SQLite使用子串匹配搜索文章，不需要全文索引；保留该版本使各数据库的迁移版本一致
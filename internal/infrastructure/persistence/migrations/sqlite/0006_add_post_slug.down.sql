@@ -0,0 +1,2 @@
+DROP INDEX uk_posts_slug;
+ALTER TABLE posts DROP COLUMN slug;
@@ -0,0 +1,5 @@
+-- 文章slug，已有文章的slug由服务启动时补全
+-- SQLite不能添加带UNIQUE约束的字段，唯一约束通过唯一索引实现
+
+ALTER TABLE posts ADD COLUMN slug TEXT NULL;
+CREATE UNIQUE INDEX uk_posts_slug ON posts (slug);
@@ -0,0 +1,14 @@
+-- SQLite不能删除带外键的字段，需要重建分类表
+-- 删除旧表时会级联删除文章分类关联，先备份关联，重建后恢复
+
+CREATE TABLE categories_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    description TEXT
+);
+INSERT INTO categories_new (id, name, description) SELECT id, name, description FROM categories;
+CREATE TABLE post_categories_backup AS SELECT id, post_id, category_id FROM post_categories;
+DROP TABLE categories;
+ALTER TABLE categories_new RENAME TO categories;
+INSERT INTO post_categories (id, post_id, category_id) SELECT id, post_id, category_id FROM post_categories_backup;
+DROP TABLE post_categories_backup;
@@ -0,0 +1,4 @@
+-- 分类层级，删除父分类时子分类成为顶级分类
+
+ALTER TABLE categories ADD COLUMN parent_id INTEGER NULL REFERENCES categories(id) ON DELETE SET NULL;
+CREATE INDEX idx_categories_parent_id ON categories (parent_id);
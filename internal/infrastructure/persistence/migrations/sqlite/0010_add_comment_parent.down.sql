@@ -0,0 +1,13 @@
+-- SQLite不能删除带外键的字段，需要重建评论表；没有其他表引用评论表，可以直接替换
+
+CREATE TABLE comments_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    author TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO comments_new (id, post_id, content, author, created_at) SELECT id, post_id, content, author, created_at FROM comments;
+DROP TABLE comments;
+ALTER TABLE comments_new RENAME TO comments;
+CREATE INDEX idx_comments_post_created_at_id ON comments (post_id, created_at, id);
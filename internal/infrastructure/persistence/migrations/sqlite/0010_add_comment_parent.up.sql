@@ -0,0 +1,5 @@
+-- 评论回复，删除评论时其回复一并删除
+
+ALTER TABLE comments ADD COLUMN parent_id INTEGER NULL REFERENCES comments(id) ON DELETE CASCADE;
+ALTER TABLE comments ADD COLUMN depth INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX idx_comments_parent_id ON comments (parent_id);
@@ -0,0 +1,2 @@
+DROP INDEX idx_comments_status_created_at;
+ALTER TABLE comments DROP COLUMN status;
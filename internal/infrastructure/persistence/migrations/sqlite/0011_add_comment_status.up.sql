@@ -0,0 +1,5 @@
+-- 评论审核状态，已有评论视为已通过审核
+
+ALTER TABLE comments ADD COLUMN status TEXT NOT NULL DEFAULT 'pending';
+UPDATE comments SET status = 'approved';
+CREATE INDEX idx_comments_status_created_at ON comments (status, created_at, id);
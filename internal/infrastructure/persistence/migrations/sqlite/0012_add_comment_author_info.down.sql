@@ -0,0 +1,2 @@
+ALTER TABLE comments DROP COLUMN user_agent;
+ALTER TABLE comments DROP COLUMN author_ip;
@@ -0,0 +1,4 @@
+-- 评论者信息，用于垃圾评论检测
+
+ALTER TABLE comments ADD COLUMN author_ip TEXT NOT NULL DEFAULT '';
+ALTER TABLE comments ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,16 @@
+-- 文章修订版本，已有文章以当前内容作为第1版
+
+CREATE TABLE post_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    title_url TEXT NOT NULL DEFAULT '',
+    author TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (post_id, revision)
+);
+
+INSERT INTO post_revisions (post_id, revision, title, content, title_url, author, created_at)
+SELECT id, 1, title, content, COALESCE(title_url, ''), author, updated_at FROM posts;
@@ -0,0 +1,6 @@
+DROP INDEX idx_categories_deleted_at;
+ALTER TABLE categories DROP COLUMN deleted_at;
+DROP INDEX idx_comments_deleted_at;
+ALTER TABLE comments DROP COLUMN deleted_at;
+DROP INDEX idx_posts_deleted_at;
+ALTER TABLE posts DROP COLUMN deleted_at;
@@ -0,0 +1,8 @@
+-- 软删除，deleted_at不为空的记录位于回收站
+
+ALTER TABLE posts ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL;
+CREATE INDEX idx_posts_deleted_at ON posts (deleted_at);
+ALTER TABLE comments ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL;
+CREATE INDEX idx_comments_deleted_at ON comments (deleted_at);
+ALTER TABLE categories ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL;
+CREATE INDEX idx_categories_deleted_at ON categories (deleted_at);
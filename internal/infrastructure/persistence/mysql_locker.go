@@ -0,0 +1,48 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// MySQLLocker 基于GET_LOCK的MySQL咨询锁，用于多副本部署时的任务互斥
+type MySQLLocker struct {
+	db *sql.DB
+}
+
+// NewMySQLLocker 创建MySQL咨询锁
+func NewMySQLLocker(conn *Connection) *MySQLLocker {
+	return &MySQLLocker{
+		db: conn.DB,
+	}
+}
+
+// TryLock 尝试获取锁，不等待；获取成功时返回释放函数
+// GET_LOCK与会话绑定，因此持锁期间独占一个数据库连接
+func (l *MySQLLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("获取数据库连接失败: %w", err)
+	}
+
+	var acquired sql.NullInt64
+	err = conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, 0)`, name).Scan(&acquired)
+	if err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("获取锁 %s 失败: %w", name, err)
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT RELEASE_LOCK(?)`, name); err != nil {
+			log.Printf("释放锁 %s 失败: %v", name, err)
+		}
+		conn.Close()
+	}
+	return unlock, true, nil
+}
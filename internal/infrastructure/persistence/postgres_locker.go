@@ -0,0 +1,48 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// PostgresLocker 基于pg_try_advisory_lock的PostgreSQL咨询锁，用于多副本部署时的任务互斥
+type PostgresLocker struct {
+	db *sql.DB
+}
+
+// NewPostgresLocker 创建PostgreSQL咨询锁
+func NewPostgresLocker(conn *Connection) *PostgresLocker {
+	return &PostgresLocker{
+		db: conn.DB,
+	}
+}
+
+// TryLock 尝试获取锁，不等待；获取成功时返回释放函数
+// 咨询锁与会话绑定，因此持锁期间独占一个数据库连接；锁名通过hashtext转换为锁的键
+func (l *PostgresLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("获取数据库连接失败: %w", err)
+	}
+
+	var acquired bool
+	err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, name).Scan(&acquired)
+	if err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("获取锁 %s 失败: %w", name, err)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, name); err != nil {
+			log.Printf("释放锁 %s 失败: %v", name, err)
+		}
+		conn.Close()
+	}
+	return unlock, true, nil
+}
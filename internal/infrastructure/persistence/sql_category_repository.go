@@ -0,0 +1,243 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+// SQLCategoryRepository 基于database/sql的分类存储库实现
+type SQLCategoryRepository struct {
+	db *database
+}
+
+// categoryColumns 分类查询字段，表别名为c
+const categoryColumns = `c.id, c.name, c.description, c.parent_id, c.version`
+
+// categoryDescendantsCTE 递归查询分类及其所有子孙分类的ID，参数为根分类ID
+const categoryDescendantsCTE = `
+	WITH RECURSIVE category_tree (id) AS (
+		SELECT id FROM categories WHERE id = ?
+		UNION ALL
+		SELECT c.id FROM categories c JOIN category_tree ct ON c.parent_id = ct.id
+	)
+	SELECT id FROM category_tree`
+
+// NewSQLCategoryRepository 创建分类存储库
+func NewSQLCategoryRepository(conn *Connection) repository.CategoryRepository {
+	return &SQLCategoryRepository{
+		db: newDatabase(conn),
+	}
+}
+
+// Create 创建分类
+func (r *SQLCategoryRepository) Create(category *entity.Category) error {
+	query := `INSERT INTO categories (name, description, parent_id, version) VALUES (?, ?, ?, ?)`
+	id, err := r.db.insert(query, category.Name, category.Description, category.ParentID, category.Version)
+	if err != nil {
+		return conflictError(err, "创建分类失败", "分类名称已存在")
+	}
+
+	category.ID = uint(id)
+	return nil
+}
+
+// GetByID 根据ID获取分类
+func (r *SQLCategoryRepository) GetByID(id uint) (*entity.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories c WHERE c.id = ? AND c.deleted_at IS NULL`
+	category, err := scanCategory(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("分类不存在: %d: %w", id, utils.ErrNotFound)
+		}
+		return nil, fmt.Errorf("获取分类失败: %w", err)
+	}
+
+	return category, nil
+}
+
+// GetAll 获取所有分类
+func (r *SQLCategoryRepository) GetAll() ([]*entity.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories c WHERE c.deleted_at IS NULL ORDER BY c.name`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("获取所有分类失败: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*entity.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("读取分类数据失败: %w", err)
+		}
+		categories = append(categories, category)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历分类数据失败: %w", err)
+	}
+
+	return categories, nil
+}
+
+// Update 更新分类，仅当版本号未变化时保存
+func (r *SQLCategoryRepository) Update(category *entity.Category) error {
+	query := `UPDATE categories SET name = ?, description = ?, parent_id = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL`
+	result, err := r.db.Exec(query, category.Name, category.Description, category.ParentID, category.ID, category.Version)
+	if err != nil {
+		return conflictError(err, "更新分类失败", "分类名称已存在")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新数量失败: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("更新分类 %d 失败: %w", category.ID, repository.ErrVersionConflict)
+	}
+
+	category.Version++
+	return nil
+}
+
+// Delete 将分类移入回收站
+func (r *SQLCategoryRepository) Delete(id uint) error {
+	query := `UPDATE categories SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+	_, err := r.db.Exec(query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("删除分类失败: %w", err)
+	}
+	return nil
+}
+
+// GetTrashedByID 根据ID获取回收站中的分类
+func (r *SQLCategoryRepository) GetTrashedByID(id uint) (*entity.Category, error) {
+	query := `SELECT ` + categoryColumns + `, c.deleted_at FROM categories c WHERE c.id = ? AND c.deleted_at IS NOT NULL`
+	var deletedAt time.Time
+	category, err := scanCategory(trashedScanner{r.db.QueryRow(query, id), &deletedAt})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("回收站中不存在分类: %d: %w", id, utils.ErrNotFound)
+		}
+		return nil, fmt.Errorf("获取分类失败: %w", err)
+	}
+
+	category.DeletedAt = &deletedAt
+	return category, nil
+}
+
+// ListTrashed 分页获取回收站中的分类，按移入回收站的时间倒序
+func (r *SQLCategoryRepository) ListTrashed(query *repository.TrashQuery) ([]*entity.Category, int64, error) {
+	query.Normalize()
+
+	var total int64
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM categories c WHERE c.deleted_at IS NOT NULL`).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("统计回收站分类数量失败: %w", err)
+	}
+
+	listQuery := `SELECT ` + categoryColumns + `, c.deleted_at FROM categories c WHERE c.deleted_at IS NOT NULL ORDER BY c.deleted_at DESC, c.id DESC LIMIT ? OFFSET ?`
+	rows, err := r.db.Query(listQuery, query.PageSize, query.Offset())
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取回收站分类失败: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*entity.Category
+	for rows.Next() {
+		var deletedAt time.Time
+		category, err := scanCategory(trashedScanner{rows, &deletedAt})
+		if err != nil {
+			return nil, 0, fmt.Errorf("读取分类数据失败: %w", err)
+		}
+		category.DeletedAt = &deletedAt
+		categories = append(categories, category)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("遍历分类数据失败: %w", err)
+	}
+
+	return categories, total, nil
+}
+
+// Restore 将分类移出回收站，父分类仍在回收站中时作为顶级分类显示
+func (r *SQLCategoryRepository) Restore(id uint) error {
+	return restoreRow(r.db, `UPDATE categories SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, id, "分类")
+}
+
+// PurgeTrashed 永久删除在before之前移入回收站的分类，子分类的parent_id通过外键置空
+func (r *SQLCategoryRepository) PurgeTrashed(before time.Time) (int64, error) {
+	return purgeRows(r.db, `DELETE FROM categories WHERE deleted_at IS NOT NULL AND deleted_at < ?`, before, "分类")
+}
+
+// AddPostToCategory 将文章添加到分类
+func (r *SQLCategoryRepository) AddPostToCategory(postID, categoryID uint) error {
+	query := `INSERT INTO post_categories (post_id, category_id) VALUES (?, ?)`
+	_, err := r.db.Exec(query, postID, categoryID)
+	if err != nil {
+		return fmt.Errorf("添加文章到分类失败: %w", err)
+	}
+	return nil
+}
+
+// RemovePostFromCategory 从分类中移除文章
+func (r *SQLCategoryRepository) RemovePostFromCategory(postID, categoryID uint) error {
+	query := `DELETE FROM post_categories WHERE post_id = ? AND category_id = ?`
+	_, err := r.db.Exec(query, postID, categoryID)
+	if err != nil {
+		return fmt.Errorf("从分类中移除文章失败: %w", err)
+	}
+	return nil
+}
+
+// GetCategoriesByPostID 获取文章的所有分类
+func (r *SQLCategoryRepository) GetCategoriesByPostID(postID uint) ([]*entity.Category, error) {
+	query := `
+		SELECT ` + categoryColumns + `
+		FROM categories c
+		JOIN post_categories pc ON c.id = pc.category_id
+		WHERE pc.post_id = ? AND c.deleted_at IS NULL
+		ORDER BY c.name
+	`
+	rows, err := r.db.Query(query, postID)
+	if err != nil {
+		return nil, fmt.Errorf("获取文章分类失败: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*entity.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("读取文章分类数据失败: %w", err)
+		}
+		categories = append(categories, category)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历文章分类数据失败: %w", err)
+	}
+
+	return categories, nil
+}
+
+// scanCategory 读取一条分类记录
+func scanCategory(scanner rowScanner) (*entity.Category, error) {
+	category := &entity.Category{}
+	var parentID sql.NullInt64
+	err := scanner.Scan(&category.ID, &category.Name, &category.Description, &parentID, &category.Version)
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		id := uint(parentID.Int64)
+		category.ParentID = &id
+	}
+	return category, nil
+}
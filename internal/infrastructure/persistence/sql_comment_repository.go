@@ -0,0 +1,344 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+// SQLCommentRepository 基于database/sql的评论存储库实现
+type SQLCommentRepository struct {
+	db *database
+}
+
+const (
+	// commentColumns 评论查询字段，表别名为c
+	commentColumns = `c.id, c.post_id, c.parent_id, c.depth, c.content, c.author, c.status, c.author_ip, c.user_agent, c.created_at`
+	// commentNotTrashed 排除回收站中的评论以及回收站中文章的评论
+	commentNotTrashed = `c.deleted_at IS NULL AND EXISTS (SELECT 1 FROM posts p WHERE p.id = c.post_id AND p.deleted_at IS NULL)`
+)
+
+// NewSQLCommentRepository 创建评论存储库
+func NewSQLCommentRepository(conn *Connection) repository.CommentRepository {
+	return &SQLCommentRepository{
+		db: newDatabase(conn),
+	}
+}
+
+// Create 创建评论
+func (r *SQLCommentRepository) Create(comment *entity.Comment) error {
+	query := `INSERT INTO comments (post_id, parent_id, depth, content, author, status, author_ip, user_agent, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	id, err := r.db.insert(query, comment.PostID, comment.ParentID, comment.Depth, comment.Content, comment.Author, comment.Status, comment.AuthorIP, comment.UserAgent, comment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("创建评论失败: %w", err)
+	}
+
+	comment.ID = uint(id)
+	return nil
+}
+
+// GetByID 根据ID获取评论
+func (r *SQLCommentRepository) GetByID(id uint) (*entity.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments c WHERE c.id = ? AND ` + commentNotTrashed
+	comment, err := scanComment(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("评论不存在: %d: %w", id, utils.ErrNotFound)
+		}
+		return nil, fmt.Errorf("获取评论失败: %w", err)
+	}
+
+	return comment, nil
+}
+
+// GetByIDs 根据ID批量获取评论
+func (r *SQLCommentRepository) GetByIDs(ids []uint) ([]*entity.Comment, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	rows, err := r.db.Query(`SELECT `+commentColumns+` FROM comments c WHERE c.id IN (`+placeholders(len(ids))+`) AND `+commentNotTrashed+` ORDER BY c.id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("批量获取评论失败: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []*entity.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("读取评论数据失败: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历评论数据失败: %w", err)
+	}
+
+	return comments, nil
+}
+
+// GetByPostID 根据文章ID获取已通过审核的顶级评论，page不为nil时按游标分页
+func (r *SQLCommentRepository) GetByPostID(postID uint, page *repository.CursorQuery) ([]*entity.Comment, bool, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments c WHERE c.post_id = ? AND c.parent_id IS NULL AND c.status = ? AND ` + commentNotTrashed
+	args := []interface{}{postID, entity.CommentStatusApproved}
+	order := "DESC"
+	if page != nil {
+		page.Normalize()
+		var condition string
+		var cursorArgs []interface{}
+		condition, cursorArgs, order = keysetClause("c", page.Cursor)
+		if condition != "" {
+			query += " AND " + condition
+			args = append(args, cursorArgs...)
+		}
+	}
+	query += fmt.Sprintf(" ORDER BY c.created_at %s, c.id %s", order, order)
+	if page != nil {
+		// 多取一条用于判断是否还有更多数据
+		query += " LIMIT ?"
+		args = append(args, page.Limit+1)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("获取文章评论失败: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []*entity.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, false, fmt.Errorf("读取评论数据失败: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("遍历评论数据失败: %w", err)
+	}
+
+	if page == nil {
+		return comments, false, nil
+	}
+
+	hasMore := len(comments) > page.Limit
+	if hasMore {
+		comments = comments[:page.Limit]
+	}
+	if page.Cursor != nil && page.Cursor.Backward {
+		slices.Reverse(comments)
+	}
+
+	return comments, hasMore, nil
+}
+
+// GetReplies 递归获取指定评论下已通过审核的所有回复，按创建时间正序
+func (r *SQLCommentRepository) GetReplies(parentIDs []uint) ([]*entity.Comment, error) {
+	if len(parentIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, 0, len(parentIDs)+2)
+	for _, id := range parentIDs {
+		args = append(args, id)
+	}
+	args = append(args, entity.CommentStatusApproved, entity.CommentStatusApproved)
+	query := `
+		WITH RECURSIVE comment_thread AS (
+			SELECT ` + commentColumns + ` FROM comments c WHERE c.parent_id IN (` + placeholders(len(parentIDs)) + `) AND c.status = ? AND c.deleted_at IS NULL
+			UNION ALL
+			SELECT ` + commentColumns + ` FROM comments c JOIN comment_thread ct ON c.parent_id = ct.id WHERE c.status = ? AND c.deleted_at IS NULL
+		)
+		SELECT ` + commentColumns + `
+		FROM comment_thread c
+		ORDER BY c.created_at, c.id
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("获取评论回复失败: %w", err)
+	}
+	defer rows.Close()
+
+	var replies []*entity.Comment
+	for rows.Next() {
+		reply, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("读取评论数据失败: %w", err)
+		}
+		replies = append(replies, reply)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历评论数据失败: %w", err)
+	}
+
+	return replies, nil
+}
+
+// List 按条件分页获取评论
+func (r *SQLCommentRepository) List(query *repository.CommentQuery) ([]*entity.Comment, int64, error) {
+	query.Normalize()
+
+	conditions := []string{commentNotTrashed}
+	var args []interface{}
+	if query.Status != "" {
+		conditions = append(conditions, "c.status = ?")
+		args = append(args, query.Status)
+	}
+	if query.PostID != 0 {
+		conditions = append(conditions, "c.post_id = ?")
+		args = append(args, query.PostID)
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int64
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM comments c`+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("统计评论数量失败: %w", err)
+	}
+
+	listQuery := `SELECT ` + commentColumns + ` FROM comments c` + where + ` ORDER BY c.created_at, c.id LIMIT ? OFFSET ?`
+	rows, err := r.db.Query(listQuery, append(args, query.PageSize, query.Offset())...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取评论列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []*entity.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("读取评论数据失败: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("遍历评论数据失败: %w", err)
+	}
+
+	return comments, total, nil
+}
+
+// UpdateStatus 批量修改评论状态
+func (r *SQLCommentRepository) UpdateStatus(ids []uint, status entity.CommentStatus) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	args := make([]interface{}, 0, len(ids)+1)
+	args = append(args, status)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	result, err := r.db.Exec(`UPDATE comments SET status = ? WHERE id IN (`+placeholders(len(ids))+`) AND deleted_at IS NULL`, args...)
+	if err != nil {
+		return 0, fmt.Errorf("修改评论状态失败: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("获取修改数量失败: %w", err)
+	}
+	return affected, nil
+}
+
+// Delete 将评论移入回收站，回复的查询从已通过审核的父评论递归，因此其下的回复随之隐藏
+func (r *SQLCommentRepository) Delete(id uint) error {
+	query := `UPDATE comments SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+	_, err := r.db.Exec(query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("删除评论失败: %w", err)
+	}
+	return nil
+}
+
+// GetTrashedByID 根据ID获取回收站中的评论
+func (r *SQLCommentRepository) GetTrashedByID(id uint) (*entity.Comment, error) {
+	query := `SELECT ` + commentColumns + `, c.deleted_at FROM comments c WHERE c.id = ? AND c.deleted_at IS NOT NULL`
+	var deletedAt time.Time
+	comment, err := scanComment(trashedScanner{r.db.QueryRow(query, id), &deletedAt})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("回收站中不存在评论: %d: %w", id, utils.ErrNotFound)
+		}
+		return nil, fmt.Errorf("获取评论失败: %w", err)
+	}
+
+	comment.DeletedAt = &deletedAt
+	return comment, nil
+}
+
+// ListTrashed 分页获取回收站中的评论，按移入回收站的时间倒序
+func (r *SQLCommentRepository) ListTrashed(query *repository.TrashQuery) ([]*entity.Comment, int64, error) {
+	query.Normalize()
+
+	var total int64
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM comments c WHERE c.deleted_at IS NOT NULL`).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("统计回收站评论数量失败: %w", err)
+	}
+
+	listQuery := `SELECT ` + commentColumns + `, c.deleted_at FROM comments c WHERE c.deleted_at IS NOT NULL ORDER BY c.deleted_at DESC, c.id DESC LIMIT ? OFFSET ?`
+	rows, err := r.db.Query(listQuery, query.PageSize, query.Offset())
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取回收站评论失败: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []*entity.Comment
+	for rows.Next() {
+		var deletedAt time.Time
+		comment, err := scanComment(trashedScanner{rows, &deletedAt})
+		if err != nil {
+			return nil, 0, fmt.Errorf("读取评论数据失败: %w", err)
+		}
+		comment.DeletedAt = &deletedAt
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("遍历评论数据失败: %w", err)
+	}
+
+	return comments, total, nil
+}
+
+// Restore 将评论移出回收站
+func (r *SQLCommentRepository) Restore(id uint) error {
+	return restoreRow(r.db, `UPDATE comments SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, id, "评论")
+}
+
+// PurgeTrashed 永久删除在before之前移入回收站的评论，回复通过外键级联删除
+func (r *SQLCommentRepository) PurgeTrashed(before time.Time) (int64, error) {
+	return purgeRows(r.db, `DELETE FROM comments WHERE deleted_at IS NOT NULL AND deleted_at < ?`, before, "评论")
+}
+
+// scanComment 读取一条评论记录
+func scanComment(scanner rowScanner) (*entity.Comment, error) {
+	comment := &entity.Comment{}
+	var parentID sql.NullInt64
+	err := scanner.Scan(&comment.ID, &comment.PostID, &parentID, &comment.Depth, &comment.Content, &comment.Author, &comment.Status, &comment.AuthorIP, &comment.UserAgent, &comment.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		id := uint(parentID.Int64)
+		comment.ParentID = &id
+	}
+	return comment, nil
+}
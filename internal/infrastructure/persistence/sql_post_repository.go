@@ -0,0 +1,526 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+const (
+	// 文章详情查询字段
+	postColumns = "p.id, p.title, p.content, p.author, p.title_url, COALESCE(p.slug, ''), p.view_count, p.status, p.published_at, p.created_at, p.updated_at, p.version"
+	// 文章列表查询字段（不含正文）
+	postSummaryColumns = "p.id, p.title, p.author, p.title_url, COALESCE(p.slug, ''), p.view_count, p.status, p.published_at, p.created_at, p.updated_at, p.version"
+	// MySQL全文搜索匹配条件，需与ft_posts_title_content索引的字段一致
+	postMatchClause = "MATCH(p.title, p.content) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	// 排除回收站中文章的条件
+	postNotTrashed = "p.deleted_at IS NULL"
+)
+
+// rowScanner 兼容sql.Row和sql.Rows的扫描接口
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// SQLPostRepository 基于database/sql的文章存储库实现
+type SQLPostRepository struct {
+	db *database
+}
+
+// NewSQLPostRepository 创建文章存储库
+func NewSQLPostRepository(conn *Connection) repository.PostRepository {
+	return &SQLPostRepository{
+		db: newDatabase(conn),
+	}
+}
+
+// Create 创建文章
+func (r *SQLPostRepository) Create(post *entity.Post) error {
+	query := `INSERT INTO posts (title, content, author, title_url, slug, view_count, status, published_at, created_at, updated_at, version) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	id, err := r.db.insert(query, post.Title, post.Content, post.Author, post.TitleURL, nullableSlug(post.Slug), post.ViewCount, post.Status, post.PublishedAt, post.CreatedAt, post.UpdatedAt, post.Version)
+	if err != nil {
+		return conflictError(err, "创建文章失败", "slug已被使用")
+	}
+
+	post.ID = uint(id)
+	return nil
+}
+
+// GetByID 根据ID获取文章
+func (r *SQLPostRepository) GetByID(id uint) (*entity.Post, error) {
+	query := `SELECT ` + postColumns + ` FROM posts p WHERE p.id = ? AND ` + postNotTrashed
+	post, err := scanPost(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("文章不存在: %d: %w", id, utils.ErrNotFound)
+		}
+		return nil, fmt.Errorf("获取文章失败: %w", err)
+	}
+
+	return post, nil
+}
+
+// GetBySlug 根据slug获取文章
+func (r *SQLPostRepository) GetBySlug(slug string) (*entity.Post, error) {
+	query := `SELECT ` + postColumns + ` FROM posts p WHERE p.slug = ? AND ` + postNotTrashed
+	post, err := scanPost(r.db.QueryRow(query, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("文章不存在: %s: %w", slug, utils.ErrNotFound)
+		}
+		return nil, fmt.Errorf("获取文章失败: %w", err)
+	}
+
+	return post, nil
+}
+
+// SlugExists 判断slug是否已被其他文章使用，回收站中的文章受唯一索引约束同样计入
+func (r *SQLPostRepository) SlugExists(slug string, excludeID uint) (bool, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM posts WHERE slug = ? AND id <> ?`, slug, excludeID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("检查slug失败: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetAll 获取所有文章
+func (r *SQLPostRepository) GetAll() ([]*entity.Post, error) {
+	query := `SELECT ` + postColumns + ` FROM posts p WHERE ` + postNotTrashed + ` ORDER BY p.created_at DESC`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("获取所有文章失败: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("读取文章数据失败: %w", err)
+		}
+		posts = append(posts, post)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历文章数据失败: %w", err)
+	}
+
+	return posts, nil
+}
+
+// Update 更新文章，仅当版本号未变化时保存，阅读量由IncrementViewCount单独维护
+func (r *SQLPostRepository) Update(post *entity.Post) error {
+	query := `UPDATE posts SET title = ?, content = ?, title_url = ?, slug = ?, status = ?, published_at = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL`
+	now := time.Now()
+	result, err := r.db.Exec(query, post.Title, post.Content, post.TitleURL, nullableSlug(post.Slug), post.Status, post.PublishedAt, now, post.ID, post.Version)
+	if err != nil {
+		return conflictError(err, "更新文章失败", "slug已被使用")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取更新数量失败: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("更新文章 %d 失败: %w", post.ID, repository.ErrVersionConflict)
+	}
+
+	post.UpdatedAt = now
+	post.Version++
+	return nil
+}
+
+// IncrementViewCount 增加文章阅读量，显式保留updated_at以免被ON UPDATE自动刷新
+func (r *SQLPostRepository) IncrementViewCount(id uint) error {
+	_, err := r.db.Exec(`UPDATE posts SET view_count = view_count + 1, updated_at = updated_at WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("增加文章阅读量失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 将文章移入回收站，保留updated_at不变
+func (r *SQLPostRepository) Delete(id uint) error {
+	query := `UPDATE posts SET deleted_at = ?, updated_at = updated_at WHERE id = ? AND deleted_at IS NULL`
+	_, err := r.db.Exec(query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("删除文章失败: %w", err)
+	}
+	return nil
+}
+
+// GetTrashedByID 根据ID获取回收站中的文章
+func (r *SQLPostRepository) GetTrashedByID(id uint) (*entity.Post, error) {
+	query := `SELECT ` + postColumns + `, p.deleted_at FROM posts p WHERE p.id = ? AND p.deleted_at IS NOT NULL`
+	var deletedAt time.Time
+	post, err := scanPost(trashedScanner{r.db.QueryRow(query, id), &deletedAt})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("回收站中不存在文章: %d: %w", id, utils.ErrNotFound)
+		}
+		return nil, fmt.Errorf("获取文章失败: %w", err)
+	}
+
+	post.DeletedAt = &deletedAt
+	return post, nil
+}
+
+// ListTrashed 分页获取回收站中的文章，按移入回收站的时间倒序
+func (r *SQLPostRepository) ListTrashed(query *repository.TrashQuery) ([]*entity.Post, int64, error) {
+	query.Normalize()
+	where := " WHERE p.deleted_at IS NOT NULL"
+	var args []interface{}
+	if query.Author != "" {
+		where += " AND p.author = ?"
+		args = append(args, query.Author)
+	}
+
+	var total int64
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM posts p`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("统计回收站文章数量失败: %w", err)
+	}
+
+	listQuery := `SELECT ` + postSummaryColumns + `, p.deleted_at FROM posts p` + where + ` ORDER BY p.deleted_at DESC, p.id DESC LIMIT ? OFFSET ?`
+	rows, err := r.db.Query(listQuery, append(args, query.PageSize, query.Offset())...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取回收站文章失败: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		var deletedAt time.Time
+		post, err := scanPostSummary(trashedScanner{rows, &deletedAt})
+		if err != nil {
+			return nil, 0, fmt.Errorf("读取文章数据失败: %w", err)
+		}
+		post.DeletedAt = &deletedAt
+		posts = append(posts, post)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("遍历文章数据失败: %w", err)
+	}
+
+	return posts, total, nil
+}
+
+// Restore 将文章移出回收站
+func (r *SQLPostRepository) Restore(id uint) error {
+	query := `UPDATE posts SET deleted_at = NULL, updated_at = updated_at WHERE id = ? AND deleted_at IS NOT NULL`
+	return restoreRow(r.db, query, id, "文章")
+}
+
+// PurgeTrashed 永久删除在before之前移入回收站的文章，评论、修订版本等关联数据通过外键级联删除
+func (r *SQLPostRepository) PurgeTrashed(before time.Time) (int64, error) {
+	return purgeRows(r.db, `DELETE FROM posts WHERE deleted_at IS NOT NULL AND deleted_at < ?`, before, "文章")
+}
+
+// List 按条件分页获取文章列表
+func (r *SQLPostRepository) List(query *repository.PostQuery) ([]*entity.Post, int64, error) {
+	query.Normalize()
+	where, args := buildPostFilter(query)
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM posts p` + where
+	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("统计文章数量失败: %w", err)
+	}
+
+	order := "DESC"
+	if query.Ascending {
+		order = "ASC"
+	}
+	listQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM posts p%s
+		ORDER BY p.%s %s, p.id %s
+		LIMIT ? OFFSET ?
+	`, postSummaryColumns, where, query.SortBy, order, order)
+
+	rows, err := r.db.Query(listQuery, append(args, query.PageSize, query.Offset())...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取文章列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		post, err := scanPostSummary(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("读取文章数据失败: %w", err)
+		}
+		posts = append(posts, post)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("遍历文章数据失败: %w", err)
+	}
+
+	return posts, total, nil
+}
+
+// ListByCursor 按创建时间倒序进行游标分页
+func (r *SQLPostRepository) ListByCursor(query *repository.PostQuery, page *repository.CursorQuery) ([]*entity.Post, bool, error) {
+	query.Normalize()
+	page.Normalize()
+	where, args := buildPostFilter(query)
+
+	condition, cursorArgs, order := keysetClause("p", page.Cursor)
+	if condition != "" {
+		where = andWhere(where, condition)
+		args = append(args, cursorArgs...)
+	}
+
+	// 多取一条用于判断是否还有更多数据
+	listQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM posts p%s
+		ORDER BY p.created_at %s, p.id %s
+		LIMIT ?
+	`, postSummaryColumns, where, order, order)
+
+	rows, err := r.db.Query(listQuery, append(args, page.Limit+1)...)
+	if err != nil {
+		return nil, false, fmt.Errorf("获取文章列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		post, err := scanPostSummary(rows)
+		if err != nil {
+			return nil, false, fmt.Errorf("读取文章数据失败: %w", err)
+		}
+		posts = append(posts, post)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("遍历文章数据失败: %w", err)
+	}
+
+	hasMore := len(posts) > page.Limit
+	if hasMore {
+		posts = posts[:page.Limit]
+	}
+	if page.Cursor != nil && page.Cursor.Backward {
+		slices.Reverse(posts)
+	}
+
+	return posts, hasMore, nil
+}
+
+// Search 全文搜索文章标题和正文，匹配方式由方言决定：MySQL使用FULLTEXT索引（ngram分词），SQLite使用子串匹配，PostgreSQL使用tsvector
+func (r *SQLPostRepository) Search(query *repository.PostSearchQuery) ([]*repository.PostSearchHit, int64, error) {
+	query.Normalize()
+	match := r.db.dialect.matchPosts(query.Keyword)
+	where, args := buildPostFilter(&query.PostQuery)
+	where = andWhere(where, match.condition)
+	args = append(args, match.conditionArgs...)
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM posts p` + where
+	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("统计搜索结果失败: %w", err)
+	}
+
+	searchQuery := fmt.Sprintf(`
+		SELECT %s, %s AS score
+		FROM posts p%s
+		ORDER BY score DESC, p.id DESC
+		LIMIT ? OFFSET ?
+	`, postColumns, match.score, where)
+
+	searchArgs := append(append([]interface{}{}, match.scoreArgs...), args...)
+	rows, err := r.db.Query(searchQuery, append(searchArgs, query.PageSize, query.Offset())...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("搜索文章失败: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []*repository.PostSearchHit
+	for rows.Next() {
+		hit := &repository.PostSearchHit{}
+		hit.Post, err = scanPost(scoreScanner{rows, &hit.Score})
+		if err != nil {
+			return nil, 0, fmt.Errorf("读取搜索结果失败: %w", err)
+		}
+		hits = append(hits, hit)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("遍历搜索结果失败: %w", err)
+	}
+
+	return hits, total, nil
+}
+
+// GetDueScheduled 获取发布时间已到的定时发布文章
+func (r *SQLPostRepository) GetDueScheduled(now time.Time) ([]*entity.Post, error) {
+	query := `SELECT ` + postColumns + ` FROM posts p WHERE p.status = ? AND p.published_at <= ? AND ` + postNotTrashed + ` ORDER BY p.published_at`
+	rows, err := r.db.Query(query, entity.PostStatusScheduled, now)
+	if err != nil {
+		return nil, fmt.Errorf("获取待发布文章失败: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("读取待发布文章数据失败: %w", err)
+		}
+		posts = append(posts, post)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历待发布文章数据失败: %w", err)
+	}
+
+	return posts, nil
+}
+
+// buildPostFilter 根据查询条件构造WHERE子句，始终排除回收站中的文章
+func buildPostFilter(query *repository.PostQuery) (string, []interface{}) {
+	conditions := []string{postNotTrashed}
+	var args []interface{}
+
+	if query.Status != "" {
+		conditions = append(conditions, "p.status = ?")
+		args = append(args, query.Status)
+	}
+	if query.Author != "" {
+		conditions = append(conditions, "p.author = ?")
+		args = append(args, query.Author)
+	}
+	if query.CategoryID != 0 {
+		conditions = append(conditions, categoryCondition(query.IncludeDescendants))
+		args = append(args, query.CategoryID)
+	}
+	if query.Tag != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE pt.post_id = p.id AND t.name = ?)")
+		args = append(args, query.Tag)
+	}
+	if !query.CreatedFrom.IsZero() {
+		conditions = append(conditions, "p.created_at >= ?")
+		args = append(args, query.CreatedFrom)
+	}
+	if !query.CreatedTo.IsZero() {
+		conditions = append(conditions, "p.created_at < ?")
+		args = append(args, query.CreatedTo)
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// categoryCondition 生成文章所属分类的筛选条件，参数为分类ID
+func categoryCondition(includeDescendants bool) string {
+	if includeDescendants {
+		return "EXISTS (SELECT 1 FROM post_categories pc WHERE pc.post_id = p.id AND pc.category_id IN (" + categoryDescendantsCTE + "))"
+	}
+	return "EXISTS (SELECT 1 FROM post_categories pc WHERE pc.post_id = p.id AND pc.category_id = ?)"
+}
+
+// andWhere 向WHERE子句追加条件
+func andWhere(where, condition string) string {
+	if where == "" {
+		return " WHERE " + condition
+	}
+	return where + " AND " + condition
+}
+
+// nullableSlug 空slug存为NULL，避免与唯一索引冲突
+func nullableSlug(slug string) interface{} {
+	if slug == "" {
+		return nil
+	}
+	return slug
+}
+
+// scoreScanner 在文章字段之后额外读取相关度得分
+type scoreScanner struct {
+	rowScanner
+	score *float64
+}
+
+// Scan 读取记录
+func (s scoreScanner) Scan(dest ...interface{}) error {
+	return s.rowScanner.Scan(append(dest, s.score)...)
+}
+
+// trashedScanner 在记录字段之后额外读取移入回收站的时间
+type trashedScanner struct {
+	rowScanner
+	deletedAt *time.Time
+}
+
+// Scan 读取记录
+func (s trashedScanner) Scan(dest ...interface{}) error {
+	return s.rowScanner.Scan(append(dest, s.deletedAt)...)
+}
+
+// restoreRow 执行移出回收站的语句，记录不在回收站中时返回错误
+func restoreRow(db *database, query string, id uint, name string) error {
+	result, err := db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("恢复%s失败: %w", name, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取恢复数量失败: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("回收站中不存在%s: %d: %w", name, id, utils.ErrNotFound)
+	}
+	return nil
+}
+
+// purgeRows 执行清理回收站的语句，返回永久删除的数量
+func purgeRows(db *database, query string, before time.Time, name string) (int64, error) {
+	result, err := db.Exec(query, before)
+	if err != nil {
+		return 0, fmt.Errorf("清理回收站%s失败: %w", name, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("获取清理数量失败: %w", err)
+	}
+	return affected, nil
+}
+
+// scanPost 读取包含正文的文章记录
+func scanPost(scanner rowScanner) (*entity.Post, error) {
+	post := &entity.Post{}
+	var publishedAt sql.NullTime
+	err := scanner.Scan(&post.ID, &post.Title, &post.Content, &post.Author, &post.TitleURL, &post.Slug, &post.ViewCount, &post.Status, &publishedAt, &post.CreatedAt, &post.UpdatedAt, &post.Version)
+	if err != nil {
+		return nil, err
+	}
+	if publishedAt.Valid {
+		post.PublishedAt = &publishedAt.Time
+	}
+	return post, nil
+}
+
+// scanPostSummary 读取不含正文的文章记录
+func scanPostSummary(scanner rowScanner) (*entity.Post, error) {
+	post := &entity.Post{}
+	var publishedAt sql.NullTime
+	err := scanner.Scan(&post.ID, &post.Title, &post.Author, &post.TitleURL, &post.Slug, &post.ViewCount, &post.Status, &publishedAt, &post.CreatedAt, &post.UpdatedAt, &post.Version)
+	if err != nil {
+		return nil, err
+	}
+	if publishedAt.Valid {
+		post.PublishedAt = &publishedAt.Time
+	}
+	return post, nil
+}
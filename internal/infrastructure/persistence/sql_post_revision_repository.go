@@ -0,0 +1,95 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+// SQLPostRevisionRepository 基于database/sql的文章修订版本存储库实现
+type SQLPostRevisionRepository struct {
+	db *database
+}
+
+// postRevisionColumns 修订版本查询字段
+const postRevisionColumns = `id, post_id, revision, title, content, title_url, author, created_at`
+
+// NewSQLPostRevisionRepository 创建文章修订版本存储库
+func NewSQLPostRevisionRepository(conn *Connection) repository.PostRevisionRepository {
+	return &SQLPostRevisionRepository{
+		db: newDatabase(conn),
+	}
+}
+
+// Create 保存修订版本，版本号为该文章当前最大版本号加1
+// 并发保存同一篇文章时由唯一索引保证版本号不重复
+func (r *SQLPostRevisionRepository) Create(revision *entity.PostRevision) error {
+	query := `
+		INSERT INTO post_revisions (post_id, revision, title, content, title_url, author, created_at)
+		SELECT ?, COALESCE(MAX(revision), 0) + 1, ?, ?, ?, ?, ? FROM post_revisions WHERE post_id = ?
+	`
+	id, err := r.db.insert(query, revision.PostID, revision.Title, revision.Content, revision.TitleURL, revision.Author, revision.CreatedAt, revision.PostID)
+	if err != nil {
+		return fmt.Errorf("保存文章修订版本失败: %w", err)
+	}
+
+	err = r.db.QueryRow(`SELECT revision FROM post_revisions WHERE id = ?`, id).Scan(&revision.Revision)
+	if err != nil {
+		return fmt.Errorf("获取文章修订版本号失败: %w", err)
+	}
+
+	revision.ID = uint(id)
+	return nil
+}
+
+// GetByRevision 根据文章ID和版本号获取修订版本
+func (r *SQLPostRevisionRepository) GetByRevision(postID uint, revision int) (*entity.PostRevision, error) {
+	query := `SELECT ` + postRevisionColumns + ` FROM post_revisions WHERE post_id = ? AND revision = ?`
+	postRevision, err := scanPostRevision(r.db.QueryRow(query, postID, revision))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("文章修订版本不存在: %d: %w", revision, utils.ErrNotFound)
+		}
+		return nil, fmt.Errorf("获取文章修订版本失败: %w", err)
+	}
+
+	return postRevision, nil
+}
+
+// ListByPostID 获取文章的所有修订版本，按版本号倒序
+func (r *SQLPostRevisionRepository) ListByPostID(postID uint) ([]*entity.PostRevision, error) {
+	query := `SELECT ` + postRevisionColumns + ` FROM post_revisions WHERE post_id = ? ORDER BY revision DESC`
+	rows, err := r.db.Query(query, postID)
+	if err != nil {
+		return nil, fmt.Errorf("获取文章修订版本列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []*entity.PostRevision
+	for rows.Next() {
+		revision, err := scanPostRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("读取文章修订版本数据失败: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历文章修订版本数据失败: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// scanPostRevision 读取一条修订版本记录
+func scanPostRevision(scanner rowScanner) (*entity.PostRevision, error) {
+	revision := &entity.PostRevision{}
+	err := scanner.Scan(&revision.ID, &revision.PostID, &revision.Revision, &revision.Title, &revision.Content, &revision.TitleURL, &revision.Author, &revision.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
@@ -0,0 +1,122 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+// SQLSlugRedirectRepository 基于database/sql的slug重定向存储库实现
+type SQLSlugRedirectRepository struct {
+	db *database
+}
+
+// NewSQLSlugRedirectRepository 创建slug重定向存储库
+func NewSQLSlugRedirectRepository(conn *Connection) repository.SlugRedirectRepository {
+	return &SQLSlugRedirectRepository{
+		db: newDatabase(conn),
+	}
+}
+
+// Save 保存重定向，旧slug已存在时改为指向新的文章
+func (r *SQLSlugRedirectRepository) Save(redirect *entity.SlugRedirect) error {
+	columns := []string{"old_slug", "post_id", "created_at"}
+	id, err := r.db.upsert("post_slug_redirects", columns, "old_slug", []string{"post_id", "created_at"}, redirect.OldSlug, redirect.PostID, redirect.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("保存slug重定向失败: %w", err)
+	}
+
+	redirect.ID = uint(id)
+	return nil
+}
+
+// GetByID 根据ID获取重定向
+func (r *SQLSlugRedirectRepository) GetByID(id uint) (*entity.SlugRedirect, error) {
+	query := `SELECT id, old_slug, post_id, created_at FROM post_slug_redirects WHERE id = ?`
+	redirect, err := scanSlugRedirect(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("slug重定向不存在: %d: %w", id, utils.ErrNotFound)
+		}
+		return nil, fmt.Errorf("获取slug重定向失败: %w", err)
+	}
+
+	return redirect, nil
+}
+
+// GetBySlug 根据旧slug获取重定向
+func (r *SQLSlugRedirectRepository) GetBySlug(oldSlug string) (*entity.SlugRedirect, error) {
+	query := `SELECT id, old_slug, post_id, created_at FROM post_slug_redirects WHERE old_slug = ?`
+	redirect, err := scanSlugRedirect(r.db.QueryRow(query, oldSlug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("slug重定向不存在: %s: %w", oldSlug, utils.ErrNotFound)
+		}
+		return nil, fmt.Errorf("获取slug重定向失败: %w", err)
+	}
+
+	return redirect, nil
+}
+
+// List 获取重定向列表，postID为0时返回全部
+func (r *SQLSlugRedirectRepository) List(postID uint) ([]*entity.SlugRedirect, error) {
+	query := `SELECT id, old_slug, post_id, created_at FROM post_slug_redirects`
+	var args []interface{}
+	if postID != 0 {
+		query += ` WHERE post_id = ?`
+		args = append(args, postID)
+	}
+	query += ` ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("获取slug重定向列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	var redirects []*entity.SlugRedirect
+	for rows.Next() {
+		redirect, err := scanSlugRedirect(rows)
+		if err != nil {
+			return nil, fmt.Errorf("读取slug重定向数据失败: %w", err)
+		}
+		redirects = append(redirects, redirect)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历slug重定向数据失败: %w", err)
+	}
+
+	return redirects, nil
+}
+
+// Delete 删除重定向
+func (r *SQLSlugRedirectRepository) Delete(id uint) error {
+	_, err := r.db.Exec(`DELETE FROM post_slug_redirects WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("删除slug重定向失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteBySlug 根据旧slug删除重定向
+func (r *SQLSlugRedirectRepository) DeleteBySlug(oldSlug string) error {
+	_, err := r.db.Exec(`DELETE FROM post_slug_redirects WHERE old_slug = ?`, oldSlug)
+	if err != nil {
+		return fmt.Errorf("删除slug重定向失败: %w", err)
+	}
+	return nil
+}
+
+// scanSlugRedirect 读取重定向记录
+func scanSlugRedirect(scanner rowScanner) (*entity.SlugRedirect, error) {
+	redirect := &entity.SlugRedirect{}
+	err := scanner.Scan(&redirect.ID, &redirect.OldSlug, &redirect.PostID, &redirect.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return redirect, nil
+}
@@ -0,0 +1,171 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+// SQLTagRepository 基于database/sql的标签存储库实现
+type SQLTagRepository struct {
+	db *database
+}
+
+// NewSQLTagRepository 创建标签存储库
+func NewSQLTagRepository(conn *Connection) repository.TagRepository {
+	return &SQLTagRepository{
+		db: newDatabase(conn),
+	}
+}
+
+// GetOrCreate 根据名称获取标签，不存在的标签会自动创建
+func (r *SQLTagRepository) GetOrCreate(names []string) ([]*entity.Tag, error) {
+	now := time.Now()
+	ids := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		// 标签已存在时取回已有ID
+		id, err := r.db.upsert("tags", []string{"name", "created_at"}, "name", nil, name, now)
+		if err != nil {
+			return nil, fmt.Errorf("创建标签失败: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT id, name, created_at FROM tags WHERE id IN (` + placeholders(len(ids)) + `) ORDER BY name`
+	return r.queryTags(query, ids...)
+}
+
+// GetByName 根据名称获取标签
+func (r *SQLTagRepository) GetByName(name string) (*entity.Tag, error) {
+	tag := &entity.Tag{}
+	err := r.db.QueryRow(`SELECT id, name, created_at FROM tags WHERE name = ?`, name).Scan(&tag.ID, &tag.Name, &tag.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("标签不存在: %s: %w", name, utils.ErrNotFound)
+		}
+		return nil, fmt.Errorf("获取标签失败: %w", err)
+	}
+
+	return tag, nil
+}
+
+// GetByPostID 获取文章的所有标签
+func (r *SQLTagRepository) GetByPostID(postID uint) ([]*entity.Tag, error) {
+	query := `
+		SELECT t.id, t.name, t.created_at
+		FROM tags t
+		JOIN post_tags pt ON t.id = pt.tag_id
+		WHERE pt.post_id = ?
+		ORDER BY t.name
+	`
+	return r.queryTags(query, postID)
+}
+
+// SetPostTags 替换文章的全部标签
+func (r *SQLTagRepository) SetPostTags(postID uint, tagIDs []uint) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM post_tags WHERE post_id = ?`, postID); err != nil {
+		return fmt.Errorf("清除文章标签失败: %w", err)
+	}
+
+	if len(tagIDs) > 0 {
+		values := make([]string, 0, len(tagIDs))
+		args := make([]interface{}, 0, len(tagIDs)*2)
+		for _, tagID := range tagIDs {
+			values = append(values, "(?, ?)")
+			args = append(args, postID, tagID)
+		}
+		query := `INSERT INTO post_tags (post_id, tag_id) VALUES ` + strings.Join(values, ", ")
+		if _, err := tx.Exec(query, args...); err != nil {
+			return fmt.Errorf("保存文章标签失败: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+	return nil
+}
+
+// GetCloud 获取标签云，只统计已发布的文章
+func (r *SQLTagRepository) GetCloud(limit int) ([]*repository.TagCount, error) {
+	query := `
+		SELECT t.id, t.name, t.created_at, COUNT(*) AS post_count
+		FROM tags t
+		JOIN post_tags pt ON t.id = pt.tag_id
+		JOIN posts p ON p.id = pt.post_id
+		WHERE p.status = ? AND p.deleted_at IS NULL
+		GROUP BY t.id, t.name, t.created_at
+		ORDER BY post_count DESC, t.name
+	`
+	args := []interface{}{entity.PostStatusPublished}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("获取标签云失败: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []*repository.TagCount
+	for rows.Next() {
+		count := &repository.TagCount{Tag: &entity.Tag{}}
+		err := rows.Scan(&count.Tag.ID, &count.Tag.Name, &count.Tag.CreatedAt, &count.PostCount)
+		if err != nil {
+			return nil, fmt.Errorf("读取标签数据失败: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历标签数据失败: %w", err)
+	}
+
+	return counts, nil
+}
+
+// queryTags 查询标签列表
+func (r *SQLTagRepository) queryTags(query string, args ...interface{}) ([]*entity.Tag, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("获取标签失败: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []*entity.Tag
+	for rows.Next() {
+		tag := &entity.Tag{}
+		err := rows.Scan(&tag.ID, &tag.Name, &tag.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("读取标签数据失败: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历标签数据失败: %w", err)
+	}
+
+	return tags, nil
+}
+
+// placeholders 生成n个以逗号分隔的占位符
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
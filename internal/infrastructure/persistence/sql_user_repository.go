@@ -0,0 +1,115 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/pkg/utils"
+)
+
+// firstUserID 第一个用户的ID
+const firstUserID = 1
+
+// SQLUserRepository 基于database/sql的用户存储库实现
+type SQLUserRepository struct {
+	db *database
+}
+
+// NewSQLUserRepository 创建用户存储库
+func NewSQLUserRepository(conn *Connection) repository.UserRepository {
+	return &SQLUserRepository{
+		db: newDatabase(conn),
+	}
+}
+
+// Create 创建用户
+func (r *SQLUserRepository) Create(user *entity.User) error {
+	query := `INSERT INTO users (username, password_hash, role, created_at) VALUES (?, ?, ?, ?)`
+	id, err := r.db.insert(query, user.Username, user.PasswordHash, user.Role, user.CreatedAt)
+	if err != nil {
+		return conflictError(err, "创建用户失败", "用户名已存在")
+	}
+
+	user.ID = uint(id)
+	return nil
+}
+
+// CreateFirst 以firstUserID创建第一个用户，由主键约束保证并发创建时只有一个成功
+func (r *SQLUserRepository) CreateFirst(user *entity.User) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO users (id, username, password_hash, role, created_at) VALUES (?, ?, ?, ?, ?)`
+	_, err = tx.Exec(query, firstUserID, user.Username, user.PasswordHash, user.Role, user.CreatedAt)
+	if err != nil {
+		return conflictError(err, "创建用户失败", "第一个用户已存在")
+	}
+	if err := r.db.dialect.syncIdentity(tx, "users"); err != nil {
+		return fmt.Errorf("同步用户ID失败: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	user.ID = firstUserID
+	return nil
+}
+
+// GetByID 根据ID获取用户
+func (r *SQLUserRepository) GetByID(id uint) (*entity.User, error) {
+	query := `SELECT id, username, password_hash, role, created_at FROM users WHERE id = ?`
+	row := r.db.QueryRow(query, id)
+
+	user := &entity.User{}
+	err := row.Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role, &user.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("用户不存在: %d: %w", id, utils.ErrNotFound)
+		}
+		return nil, fmt.Errorf("获取用户失败: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetByUsername 根据用户名获取用户
+func (r *SQLUserRepository) GetByUsername(username string) (*entity.User, error) {
+	query := `SELECT id, username, password_hash, role, created_at FROM users WHERE username = ?`
+	row := r.db.QueryRow(query, username)
+
+	user := &entity.User{}
+	err := row.Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role, &user.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("用户不存在: %s: %w", username, utils.ErrNotFound)
+		}
+		return nil, fmt.Errorf("获取用户失败: %w", err)
+	}
+
+	return user, nil
+}
+
+// Count 获取用户总数
+func (r *SQLUserRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("统计用户数量失败: %w", err)
+	}
+	return count, nil
+}
+
+// UpdateRole 更新用户角色
+// MySQL在角色未变化时报告0行受影响，因此不以受影响行数判断用户是否存在，由调用方先行查询
+func (r *SQLUserRepository) UpdateRole(id uint, role entity.Role) error {
+	query := `UPDATE users SET role = ? WHERE id = ?`
+	if _, err := r.db.Exec(query, role, id); err != nil {
+		return fmt.Errorf("更新用户角色失败: %w", err)
+	}
+	return nil
+}
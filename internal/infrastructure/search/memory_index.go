@@ -0,0 +1,222 @@
+package search
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"sync"
+
+	"blog/internal/domain/repository"
+)
+
+// titleBoost 标题中的词项权重
+const titleBoost = 3
+
+// document 索引中的文章及其词频
+type document struct {
+	doc *repository.SearchDocument
+	// termFreq 词项的加权词频，标题词频乘以titleBoost
+	termFreq map[string]int
+}
+
+// MemoryIndex 纯Go实现的内存倒排索引，支持中文分词，不依赖数据库的全文检索功能
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[uint]*document
+	postings map[string]map[uint]struct{}
+}
+
+// NewMemoryIndex 创建内存倒排索引
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		docs:     make(map[uint]*document),
+		postings: make(map[string]map[uint]struct{}),
+	}
+}
+
+// Index 添加或更新文章
+func (idx *MemoryIndex) Index(doc *repository.SearchDocument) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(doc.Post.ID)
+	idx.add(doc)
+	return nil
+}
+
+// Remove 从索引中移除文章
+func (idx *MemoryIndex) Remove(postID uint) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(postID)
+	return nil
+}
+
+// Rebuild 清空索引并写入全部文章
+func (idx *MemoryIndex) Rebuild(docs []*repository.SearchDocument) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.docs = make(map[uint]*document, len(docs))
+	idx.postings = make(map[string]map[uint]struct{})
+	for _, doc := range docs {
+		idx.add(doc)
+	}
+	return nil
+}
+
+// Search 搜索包含全部关键词词项的文章，按TF-IDF得分排序
+func (idx *MemoryIndex) Search(query *repository.PostSearchQuery) ([]*repository.PostSearchHit, int64, error) {
+	query.Normalize()
+	terms := uniqueTerms(Tokenize(query.Keyword))
+	if len(terms) == 0 {
+		return nil, 0, nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	// 从文档数最少的词项开始求交集
+	sort.Slice(terms, func(i, j int) bool {
+		return len(idx.postings[terms[i]]) < len(idx.postings[terms[j]])
+	})
+
+	var hits []*repository.PostSearchHit
+	for id := range idx.postings[terms[0]] {
+		doc := idx.docs[id]
+		if !containsAll(doc, terms[1:]) || !matchesFilter(doc.doc, &query.PostQuery) {
+			continue
+		}
+		hits = append(hits, &repository.PostSearchHit{
+			Post:  doc.doc.Post,
+			Score: idx.score(doc, terms),
+		})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Post.ID > hits[j].Post.ID
+	})
+
+	total := int64(len(hits))
+	start := query.Offset()
+	if start >= len(hits) {
+		return nil, total, nil
+	}
+	end := min(start+query.PageSize, len(hits))
+
+	// 返回副本，避免调用方修改索引内的数据
+	page := make([]*repository.PostSearchHit, 0, end-start)
+	for _, hit := range hits[start:end] {
+		post := *hit.Post
+		page = append(page, &repository.PostSearchHit{Post: &post, Score: hit.Score})
+	}
+	return page, total, nil
+}
+
+// Len 返回索引中的文章数量
+func (idx *MemoryIndex) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// add 写入文章，调用方需持有写锁
+func (idx *MemoryIndex) add(doc *repository.SearchDocument) {
+	post := *doc.Post
+	stored := &document{
+		doc: &repository.SearchDocument{
+			Post:        &post,
+			CategoryIDs: slices.Clone(doc.CategoryIDs),
+		},
+		termFreq: make(map[string]int),
+	}
+	for _, term := range tokenizeForIndex(post.Title) {
+		stored.termFreq[term] += titleBoost
+	}
+	for _, term := range tokenizeForIndex(post.Content) {
+		stored.termFreq[term]++
+	}
+
+	idx.docs[post.ID] = stored
+	for term := range stored.termFreq {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[uint]struct{})
+		}
+		idx.postings[term][post.ID] = struct{}{}
+	}
+}
+
+// remove 移除文章，调用方需持有写锁
+func (idx *MemoryIndex) remove(postID uint) {
+	doc, ok := idx.docs[postID]
+	if !ok {
+		return
+	}
+	for term := range doc.termFreq {
+		delete(idx.postings[term], postID)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	delete(idx.docs, postID)
+}
+
+// score 计算TF-IDF得分，词频取对数以削弱长文的优势
+func (idx *MemoryIndex) score(doc *document, terms []string) float64 {
+	total := float64(len(idx.docs))
+	var score float64
+	for _, term := range terms {
+		idf := math.Log(1 + total/float64(len(idx.postings[term])))
+		score += (1 + math.Log(float64(doc.termFreq[term]))) * idf
+	}
+	return score
+}
+
+// containsAll 判断文章是否包含全部词项
+func containsAll(doc *document, terms []string) bool {
+	for _, term := range terms {
+		if doc.termFreq[term] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesFilter 判断文章是否满足列表筛选条件
+func matchesFilter(doc *repository.SearchDocument, query *repository.PostQuery) bool {
+	post := doc.Post
+	if query.Status != "" && post.Status != query.Status {
+		return false
+	}
+	if query.Author != "" && post.Author != query.Author {
+		return false
+	}
+	if query.CategoryID != 0 && !slices.Contains(doc.CategoryIDs, query.CategoryID) {
+		return false
+	}
+	if !query.CreatedFrom.IsZero() && post.CreatedAt.Before(query.CreatedFrom) {
+		return false
+	}
+	if !query.CreatedTo.IsZero() && !post.CreatedAt.Before(query.CreatedTo) {
+		return false
+	}
+	return true
+}
+
+// uniqueTerms 去除重复词项
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]struct{}, len(terms))
+	result := terms[:0]
+	for _, term := range terms {
+		if _, ok := seen[term]; ok {
+			continue
+		}
+		seen[term] = struct{}{}
+		result = append(result, term)
+	}
+	return result
+}
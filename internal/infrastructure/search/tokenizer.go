@@ -0,0 +1,64 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize 将查询文本切分为词项
+// 字母和数字按连续片段成词并转为小写，中日韩文字没有空格分隔，按相邻两字切成二元组，单独出现的汉字保留为单字
+func Tokenize(text string) []string {
+	return tokenize(text, false)
+}
+
+// tokenizeForIndex 将文档切分为索引词项，额外为每个中日韩文字生成单字词项，以便匹配单字查询
+func tokenizeForIndex(text string) []string {
+	return tokenize(text, true)
+}
+
+func tokenize(text string, unigrams bool) []string {
+	var tokens []string
+	var word strings.Builder
+	var cjk []rune
+
+	flushWord := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	flushCJK := func() {
+		if len(cjk) == 1 || unigrams {
+			for _, r := range cjk {
+				tokens = append(tokens, string(r))
+			}
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			tokens = append(tokens, string(cjk[i:i+2]))
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word.WriteRune(unicode.ToLower(r))
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+
+	return tokens
+}
+
+// isCJK 判断是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
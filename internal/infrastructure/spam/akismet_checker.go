@@ -0,0 +1,134 @@
+package spam
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"blog/internal/domain/repository"
+)
+
+// akismetThanks submit-spam和submit-ham成功时的响应内容
+const akismetThanks = "Thanks for making the web a better place."
+
+// AkismetChecker 基于Akismet协议的外部垃圾评论检测器
+// 兼容Akismet的服务（如自建的反垃圾服务）只需替换endpoint
+// 请求失败时返回错误，由调用方决定放行，不会因外部服务不可用而阻塞评论
+type AkismetChecker struct {
+	endpoint string
+	apiKey   string
+	blogURL  string
+	client   *http.Client
+}
+
+// NewAkismetChecker 创建Akismet检测器，endpoint为服务地址（如 https://rest.akismet.com），blogURL为站点地址
+func NewAkismetChecker(endpoint, apiKey, blogURL string, timeout time.Duration) *AkismetChecker {
+	return &AkismetChecker{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		apiKey:   apiKey,
+		blogURL:  strings.TrimRight(blogURL, "/"),
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// Check 调用comment-check检测评论
+func (c *AkismetChecker) Check(input *repository.SpamCheckInput) (*repository.SpamCheckResult, error) {
+	body, resp, err := c.post("comment-check", input)
+	if err != nil {
+		return nil, err
+	}
+
+	switch body {
+	case "true":
+		reason := "Akismet判定为垃圾评论"
+		if resp.Header.Get("X-akismet-pro-tip") == "discard" {
+			reason += "（明显的垃圾评论）"
+		}
+		return spam(reason), nil
+	case "false":
+		return &repository.SpamCheckResult{}, nil
+	default:
+		return nil, akismetError("comment-check", body, resp)
+	}
+}
+
+// Learn 根据审核结果调用submit-spam或submit-ham
+func (c *AkismetChecker) Learn(input *repository.SpamCheckInput, isSpam bool) error {
+	method := "submit-ham"
+	if isSpam {
+		method = "submit-spam"
+	}
+
+	body, resp, err := c.post(method, input)
+	if err != nil {
+		return err
+	}
+	if body != akismetThanks {
+		return akismetError(method, body, resp)
+	}
+	return nil
+}
+
+// post 以表单形式调用Akismet接口，返回去除首尾空白的响应内容
+func (c *AkismetChecker) post(method string, input *repository.SpamCheckInput) (string, *http.Response, error) {
+	resp, err := c.client.PostForm(c.endpoint+"/1.1/"+method, c.form(input))
+	if err != nil {
+		return "", nil, fmt.Errorf("请求Akismet %s失败: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err != nil {
+		return "", nil, fmt.Errorf("读取Akismet %s响应失败: %w", method, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("Akismet %s返回状态码: %d", method, resp.StatusCode)
+	}
+	return strings.TrimSpace(string(data)), resp, nil
+}
+
+// form 构造请求参数
+func (c *AkismetChecker) form(input *repository.SpamCheckInput) url.Values {
+	comment := input.Comment
+	form := url.Values{}
+	form.Set("api_key", c.apiKey)
+	form.Set("blog", c.blogURL)
+	form.Set("blog_charset", "UTF-8")
+	form.Set("user_ip", comment.AuthorIP)
+	form.Set("user_agent", comment.UserAgent)
+	form.Set("comment_author", comment.Author)
+	form.Set("comment_content", comment.Content)
+	form.Set("comment_type", "comment")
+	if comment.ParentID != nil {
+		form.Set("comment_type", "reply")
+	}
+	if !comment.CreatedAt.IsZero() {
+		form.Set("comment_date_gmt", comment.CreatedAt.UTC().Format(time.RFC3339))
+	}
+	if input.Referrer != "" {
+		form.Set("referrer", input.Referrer)
+	}
+	if input.Post != nil {
+		form.Set("permalink", c.blogURL+"/posts/"+input.Post.Slug)
+		if !input.Post.UpdatedAt.IsZero() {
+			form.Set("comment_post_modified_gmt", input.Post.UpdatedAt.UTC().Format(time.RFC3339))
+		}
+	}
+	// 蜜罐字段一并提交，Akismet会将其作为判定依据
+	if input.Honeypot != "" {
+		form.Set("honeypot_field_name", "website")
+		form.Set("website", input.Honeypot)
+	}
+	return form
+}
+
+// akismetError 构造Akismet返回的异常响应错误，附带服务端给出的调试信息
+func akismetError(method, body string, resp *http.Response) error {
+	if help := resp.Header.Get("X-akismet-debug-help"); help != "" {
+		return fmt.Errorf("Akismet %s返回异常响应: %s（%s）", method, body, help)
+	}
+	return fmt.Errorf("Akismet %s返回异常响应: %s", method, body)
+}
@@ -0,0 +1,80 @@
+package spam
+
+import (
+	"math"
+	"sync"
+
+	"blog/internal/infrastructure/search"
+)
+
+// minTrainingDocs 每个类别至少需要的训练样本数，样本不足时分类器不参与判定
+const minTrainingDocs = 5
+
+const (
+	classHam = iota
+	classSpam
+)
+
+// BayesClassifier 多项式朴素贝叶斯分类器，使用拉普拉斯平滑
+type BayesClassifier struct {
+	mu sync.RWMutex
+	// wordCounts 各类别中每个词项出现的次数
+	wordCounts [2]map[string]int
+	// totalWords 各类别的词项总数
+	totalWords [2]int
+	// docCounts 各类别的训练样本数
+	docCounts  [2]int
+	vocabulary map[string]struct{}
+}
+
+// NewBayesClassifier 创建朴素贝叶斯分类器
+func NewBayesClassifier() *BayesClassifier {
+	return &BayesClassifier{
+		wordCounts: [2]map[string]int{make(map[string]int), make(map[string]int)},
+		vocabulary: make(map[string]struct{}),
+	}
+}
+
+// Learn 学习一条样本，spam为false表示正常内容
+func (b *BayesClassifier) Learn(text string, spam bool) {
+	class := classHam
+	if spam {
+		class = classSpam
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, token := range search.Tokenize(text) {
+		b.wordCounts[class][token]++
+		b.totalWords[class]++
+		b.vocabulary[token] = struct{}{}
+	}
+	b.docCounts[class]++
+}
+
+// SpamProbability 计算内容为垃圾内容的概率，训练样本不足时ok为false
+func (b *BayesClassifier) SpamProbability(text string) (probability float64, ok bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.docCounts[classHam] < minTrainingDocs || b.docCounts[classSpam] < minTrainingDocs {
+		return 0, false
+	}
+
+	// 在对数空间中累加，避免长文本的概率乘积下溢
+	totalDocs := float64(b.docCounts[classHam] + b.docCounts[classSpam])
+	vocabulary := float64(len(b.vocabulary))
+	var scores [2]float64
+	for class := range scores {
+		scores[class] = math.Log(float64(b.docCounts[class]) / totalDocs)
+	}
+	for _, token := range search.Tokenize(text) {
+		for class := range scores {
+			count := float64(b.wordCounts[class][token])
+			scores[class] += math.Log((count + 1) / (float64(b.totalWords[class]) + vocabulary))
+		}
+	}
+
+	return 1 / (1 + math.Exp(scores[classHam]-scores[classSpam])), true
+}
@@ -0,0 +1,48 @@
+package spam
+
+import (
+	"errors"
+	"log"
+
+	"blog/internal/domain/repository"
+)
+
+// ChainChecker 按顺序组合多个检测器，任一检测器判定为垃圾评论即为垃圾评论
+// 单个检测器出错时记录日志并跳过，评论过于频繁的错误直接返回
+type ChainChecker struct {
+	checkers []repository.SpamChecker
+}
+
+// NewChainChecker 创建组合检测器，检测器按传入顺序执行，应把开销小的本地检测放在前面
+func NewChainChecker(checkers ...repository.SpamChecker) *ChainChecker {
+	return &ChainChecker{checkers: checkers}
+}
+
+// Check 依次调用各检测器，遇到垃圾评论判定时不再调用后续检测器
+func (c *ChainChecker) Check(input *repository.SpamCheckInput) (*repository.SpamCheckResult, error) {
+	for _, checker := range c.checkers {
+		result, err := checker.Check(input)
+		if errors.Is(err, repository.ErrCommentRateLimited) {
+			return nil, err
+		}
+		if err != nil {
+			log.Printf("垃圾评论检测失败，已跳过: %v", err)
+			continue
+		}
+		if result.Spam {
+			return result, nil
+		}
+	}
+	return &repository.SpamCheckResult{}, nil
+}
+
+// Learn 将审核结果提交给所有检测器，返回所有检测器的错误
+func (c *ChainChecker) Learn(input *repository.SpamCheckInput, isSpam bool) error {
+	var errs []error
+	for _, checker := range c.checkers {
+		if err := checker.Learn(input, isSpam); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
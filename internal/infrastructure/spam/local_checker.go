@@ -0,0 +1,96 @@
+package spam
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"blog/internal/domain/repository"
+)
+
+// linkPattern 匹配评论中的链接，一个a标签只计为一个链接
+var linkPattern = regexp.MustCompile(`(?i)<a\s[^>]*>|(?:https?://|www\.)\S+`)
+
+// Settings 本地垃圾评论检测设置，数值为0时关闭对应的检测
+type Settings struct {
+	// MaxLinks 单条评论允许的最多链接数
+	MaxLinks int
+	// BannedWords 违禁词，不区分大小写
+	BannedWords []string
+	// RateLimit 同一IP在RateWindow内允许提交的评论数
+	RateLimit  int
+	RateWindow time.Duration
+	// BayesThreshold 贝叶斯分类器判定为垃圾评论的概率阈值
+	BayesThreshold float64
+}
+
+// LocalChecker 本地垃圾评论检测器
+// 依次检查提交频率、蜜罐字段、违禁词、链接数量，最后由朴素贝叶斯分类器判定
+type LocalChecker struct {
+	settings    Settings
+	bannedWords []string
+	limiter     *RateLimiter
+	classifier  *BayesClassifier
+}
+
+// NewLocalChecker 创建本地垃圾评论检测器
+func NewLocalChecker(settings Settings) *LocalChecker {
+	checker := &LocalChecker{
+		settings:   settings,
+		classifier: NewBayesClassifier(),
+	}
+	for _, word := range settings.BannedWords {
+		if word = strings.TrimSpace(word); word != "" {
+			checker.bannedWords = append(checker.bannedWords, strings.ToLower(word))
+		}
+	}
+	if settings.RateLimit > 0 && settings.RateWindow > 0 {
+		checker.limiter = NewRateLimiter(settings.RateLimit, settings.RateWindow)
+	}
+	return checker
+}
+
+// Check 检测评论是否为垃圾评论
+func (c *LocalChecker) Check(input *repository.SpamCheckInput) (*repository.SpamCheckResult, error) {
+	comment := input.Comment
+	if c.limiter != nil && comment.AuthorIP != "" && !c.limiter.Allow(comment.AuthorIP) {
+		return nil, repository.ErrCommentRateLimited
+	}
+
+	if input.Honeypot != "" {
+		return spam("蜜罐字段被填写"), nil
+	}
+
+	content := strings.ToLower(comment.Content)
+	for _, word := range c.bannedWords {
+		if strings.Contains(content, word) {
+			return spam(fmt.Sprintf("包含违禁词: %s", word)), nil
+		}
+	}
+
+	if c.settings.MaxLinks > 0 {
+		if links := len(linkPattern.FindAllString(comment.Content, -1)); links > c.settings.MaxLinks {
+			return spam(fmt.Sprintf("链接数量过多: %d", links)), nil
+		}
+	}
+
+	if c.settings.BayesThreshold > 0 {
+		if probability, ok := c.classifier.SpamProbability(comment.Content); ok && probability >= c.settings.BayesThreshold {
+			return spam(fmt.Sprintf("贝叶斯分类器判定为垃圾评论: %.2f", probability)), nil
+		}
+	}
+
+	return &repository.SpamCheckResult{}, nil
+}
+
+// Learn 根据审核结果训练贝叶斯分类器
+func (c *LocalChecker) Learn(input *repository.SpamCheckInput, isSpam bool) error {
+	c.classifier.Learn(input.Comment.Content, isSpam)
+	return nil
+}
+
+// spam 构造垃圾评论检测结果
+func spam(reason string) *repository.SpamCheckResult {
+	return &repository.SpamCheckResult{Spam: true, Reason: reason}
+}
@@ -0,0 +1,77 @@
+package spam
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter 按键计数的滑动窗口限流器
+type RateLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	hits   map[string][]time.Time
+	// lastSweep 上次清理过期记录的时间
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewRateLimiter 创建限流器，window时间内每个键最多允许limit次请求
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:  limit,
+		window: window,
+		hits:   make(map[string][]time.Time),
+		now:    time.Now,
+	}
+}
+
+// SetClock 替换时间来源，便于测试
+func (l *RateLimiter) SetClock(now func() time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.now = now
+}
+
+// Allow 记录一次请求并判断是否在限额内
+func (l *RateLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	cutoff := now.Add(-l.window)
+	if now.Sub(l.lastSweep) > l.window {
+		l.sweep(cutoff)
+		l.lastSweep = now
+	}
+
+	hits := recent(l.hits[key], cutoff)
+	if len(hits) >= l.limit {
+		l.hits[key] = hits
+		return false
+	}
+	l.hits[key] = append(hits, now)
+	return true
+}
+
+// sweep 删除所有已过期的记录，避免不活跃的键长期占用内存
+func (l *RateLimiter) sweep(cutoff time.Time) {
+	for key, hits := range l.hits {
+		hits = recent(hits, cutoff)
+		if len(hits) == 0 {
+			delete(l.hits, key)
+		} else {
+			l.hits[key] = hits
+		}
+	}
+}
+
+// recent 返回cutoff之后的请求时间，hits按时间正序排列
+func recent(hits []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(hits) && !hits[i].After(cutoff) {
+		i++
+	}
+	return hits[i:]
+}
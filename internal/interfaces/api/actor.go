@@ -0,0 +1,23 @@
+package api
+
+import (
+	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
+	"blog/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentActor 根据认证信息构造当前操作者，未登录时返回nil
+func currentActor(c *gin.Context) *policy.Actor {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return nil
+	}
+	username, _ := middleware.CurrentUsername(c)
+	return &policy.Actor{
+		UserID:   userID,
+		Username: username,
+		Role:     entity.Role(middleware.CurrentRole(c)),
+	}
+}
@@ -29,9 +29,11 @@ func (h *CategoryHandler) Register(router *gin.RouterGroup) {
 	{
 		categories.POST("", h.CreateCategory)
 		categories.GET("", h.GetAllCategories)
+		categories.GET("/trash", h.ListTrashedCategories)
 		categories.GET("/:id", h.GetCategoryByID)
 		categories.PUT("/:id", h.UpdateCategory)
 		categories.DELETE("/:id", h.DeleteCategory)
+		categories.POST("/:id/restore", h.RestoreCategory)
 	}
 }
 
@@ -39,16 +41,17 @@ func (h *CategoryHandler) Register(router *gin.RouterGroup) {
 func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	var req dto.CreateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.NewErrorResponse(err, "请求参数错误"))
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
 		return
 	}
 
-	category, err := h.categoryApp.CreateCategory(&req)
+	category, err := h.categoryApp.CreateCategory(currentActor(c), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.NewErrorResponse(err, "创建分类失败"))
+		abortWithError(c, err, "创建分类失败")
 		return
 	}
 
+	setETag(c, category.Version)
 	c.JSON(http.StatusCreated, utils.NewSuccessResponse(category, "分类创建成功"))
 }
 
@@ -56,66 +59,126 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 func (h *CategoryHandler) GetCategoryByID(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.NewErrorResponse(err, "无效的ID"))
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
 		return
 	}
 
 	category, err := h.categoryApp.GetCategoryByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, utils.NewErrorResponse(err, "分类不存在"))
+		abortWithError(c, err, "获取分类失败")
 		return
 	}
 
+	setETag(c, category.Version)
 	c.JSON(http.StatusOK, utils.NewSuccessResponse(category, "获取分类详情成功"))
 }
 
-// GetAllCategories 获取所有分类
+// GetAllCategories 获取所有分类，tree=true时以树形结构返回
 func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
+	var query dto.CategoryListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+
+	if query.Tree {
+		tree, err := h.categoryApp.GetCategoryTree()
+		if err != nil {
+			abortWithError(c, err, "获取分类树失败")
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.NewSuccessResponse(tree, "获取分类树成功"))
+		return
+	}
+
 	categories, err := h.categoryApp.GetAllCategories()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.NewErrorResponse(err, "获取分类列表失败"))
+		abortWithError(c, err, "获取分类列表失败")
 		return
 	}
 
 	c.JSON(http.StatusOK, utils.NewSuccessResponse(categories, "获取分类列表成功"))
 }
 
-// UpdateCategory 更新分类
+// UpdateCategory 更新分类，需通过If-Match携带读取时的版本号
 func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.NewErrorResponse(err, "无效的ID"))
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
+		return
+	}
+
+	version, ok := requireIfMatch(c)
+	if !ok {
 		return
 	}
 
 	var req dto.UpdateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.NewErrorResponse(err, "请求参数错误"))
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
 		return
 	}
 
-	category, err := h.categoryApp.UpdateCategory(uint(id), &req)
+	category, err := h.categoryApp.UpdateCategory(currentActor(c), uint(id), version, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.NewErrorResponse(err, "更新分类失败"))
+		abortWithError(c, err, "更新分类失败")
 		return
 	}
 
+	setETag(c, category.Version)
 	c.JSON(http.StatusOK, utils.NewSuccessResponse(category, "分类更新成功"))
 }
 
-// DeleteCategory 删除分类
+// DeleteCategory 将分类移入回收站
 func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.NewErrorResponse(err, "无效的ID"))
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
+		return
+	}
+
+	err = h.categoryApp.DeleteCategory(currentActor(c), uint(id))
+	if err != nil {
+		abortWithError(c, err, "删除分类失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(nil, "分类已移入回收站"))
+}
+
+// ListTrashedCategories 分页获取回收站中的分类
+func (h *CategoryHandler) ListTrashedCategories(c *gin.Context) {
+	var query dto.TrashQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+
+	result, err := h.categoryApp.ListTrashedCategories(currentActor(c), &query)
+	if err != nil {
+		abortWithError(c, err, "获取回收站分类失败")
+		return
+	}
+
+	pagination := utils.NewPagination(result.Total, result.Page, result.PageSize, c.Request.URL)
+	c.JSON(http.StatusOK, utils.NewPaginatedResponse(result.Categories, pagination, "获取回收站分类成功"))
+}
+
+// RestoreCategory 将分类移出回收站
+func (h *CategoryHandler) RestoreCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
 		return
 	}
 
-	err = h.categoryApp.DeleteCategory(uint(id))
+	category, err := h.categoryApp.RestoreCategory(currentActor(c), uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.NewErrorResponse(err, "删除分类失败"))
+		abortWithError(c, err, "恢复分类失败")
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.NewSuccessResponse(nil, "分类删除成功"))
+	setETag(c, category.Version)
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(category, "分类恢复成功"))
 }
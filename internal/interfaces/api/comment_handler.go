@@ -5,6 +5,8 @@ import (
 	"strconv"
 
 	"blog/internal/application"
+	"blog/internal/domain/entity"
+	"blog/internal/domain/service"
 	"blog/internal/interfaces/dto"
 	"blog/pkg/utils"
 
@@ -28,9 +30,13 @@ func (h *CommentHandler) Register(router *gin.RouterGroup) {
 	comments := router.Group("/comments")
 	{
 		comments.POST("", h.CreateComment)
+		comments.GET("/moderation", h.ListModerationQueue)
+		comments.POST("/moderation", h.ModerateComments)
+		comments.GET("/trash", h.ListTrashedComments)
 		comments.GET("/:id", h.GetCommentByID)
 		comments.GET("/post/:id", h.GetCommentsByPostID)
 		comments.DELETE("/:id", h.DeleteComment)
+		comments.POST("/:id/restore", h.RestoreComment)
 	}
 }
 
@@ -38,66 +44,152 @@ func (h *CommentHandler) Register(router *gin.RouterGroup) {
 func (h *CommentHandler) CreateComment(c *gin.Context) {
 	var req dto.CreateCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.NewErrorResponse(err, "请求参数错误"))
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
 		return
 	}
 
-	comment, err := h.commentApp.CreateComment(&req)
+	client := &service.CommentClient{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Referrer:  c.Request.Referer(),
+	}
+	comment, err := h.commentApp.CreateComment(currentActor(c), &req, client)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.NewErrorResponse(err, "创建评论失败"))
+		abortWithError(c, err, "创建评论失败")
 		return
 	}
 
-	c.JSON(http.StatusCreated, utils.NewSuccessResponse(comment, "评论创建成功"))
+	message := "评论创建成功"
+	if comment.Status == string(entity.CommentStatusPending) {
+		message = "评论已提交，审核通过后显示"
+	}
+	c.JSON(http.StatusCreated, utils.NewSuccessResponse(comment, message))
 }
 
 // GetCommentByID 根据ID获取评论
 func (h *CommentHandler) GetCommentByID(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.NewErrorResponse(err, "无效的ID"))
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
 		return
 	}
 
-	comment, err := h.commentApp.GetCommentByID(uint(id))
+	comment, err := h.commentApp.GetCommentByID(currentActor(c), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, utils.NewErrorResponse(err, "评论不存在"))
+		abortWithError(c, err, "获取评论失败")
 		return
 	}
 
 	c.JSON(http.StatusOK, utils.NewSuccessResponse(comment, "获取评论成功"))
 }
 
+// ListModerationQueue 分页获取审核队列中的评论
+func (h *CommentHandler) ListModerationQueue(c *gin.Context) {
+	var query dto.CommentModerationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+
+	result, err := h.commentApp.ListComments(currentActor(c), &query)
+	if err != nil {
+		abortWithError(c, err, "获取审核队列失败")
+		return
+	}
+
+	pagination := utils.NewPagination(result.Total, result.Page, result.PageSize, c.Request.URL)
+	c.JSON(http.StatusOK, utils.NewPaginatedResponse(result.Comments, pagination, "获取审核队列成功"))
+}
+
+// ModerateComments 批量审核评论
+func (h *CommentHandler) ModerateComments(c *gin.Context) {
+	var req dto.ModerateCommentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+
+	result, err := h.commentApp.ModerateComments(currentActor(c), &req)
+	if err != nil {
+		abortWithError(c, err, "审核评论失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(result, "评论审核成功"))
+}
+
 // GetCommentsByPostID 根据文章ID获取评论
 func (h *CommentHandler) GetCommentsByPostID(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.NewErrorResponse(err, "无效的文章ID"))
+		abortWithError(c, utils.NewValidationError(err), "无效的文章ID")
+		return
+	}
+
+	var query dto.CommentListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
 		return
 	}
 
-	comments, err := h.commentApp.GetCommentsByPostID(uint(id))
+	result, err := h.commentApp.GetCommentsByPostID(currentActor(c), uint(id), &query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.NewErrorResponse(err, "获取文章评论失败"))
+		abortWithError(c, err, "获取文章评论失败")
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.NewSuccessResponse(comments, "获取文章评论成功"))
+	cursor := utils.NewCursor(result.PageSize, result.NextCursor, result.PrevCursor, c.Request.URL)
+	c.JSON(http.StatusOK, utils.NewCursorResponse(result.Comments, cursor, "获取文章评论成功"))
 }
 
-// DeleteComment 删除评论
+// DeleteComment 将评论移入回收站
 func (h *CommentHandler) DeleteComment(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.NewErrorResponse(err, "无效的ID"))
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
+		return
+	}
+
+	err = h.commentApp.DeleteComment(currentActor(c), uint(id))
+	if err != nil {
+		abortWithError(c, err, "删除评论失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(nil, "评论已移入回收站"))
+}
+
+// ListTrashedComments 分页获取回收站中的评论
+func (h *CommentHandler) ListTrashedComments(c *gin.Context) {
+	var query dto.TrashQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+
+	result, err := h.commentApp.ListTrashedComments(currentActor(c), &query)
+	if err != nil {
+		abortWithError(c, err, "获取回收站评论失败")
+		return
+	}
+
+	pagination := utils.NewPagination(result.Total, result.Page, result.PageSize, c.Request.URL)
+	c.JSON(http.StatusOK, utils.NewPaginatedResponse(result.Comments, pagination, "获取回收站评论成功"))
+}
+
+// RestoreComment 将评论移出回收站
+func (h *CommentHandler) RestoreComment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
 		return
 	}
 
-	err = h.commentApp.DeleteComment(uint(id))
+	comment, err := h.commentApp.RestoreComment(currentActor(c), uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.NewErrorResponse(err, "删除评论失败"))
+		abortWithError(c, err, "恢复评论失败")
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.NewSuccessResponse(nil, "评论删除成功"))
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(comment, "评论恢复成功"))
 }
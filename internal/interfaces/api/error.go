@@ -0,0 +1,12 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// abortWithError 记录错误并中止请求，由middleware.ErrorHandler根据错误类别生成响应
+// message为响应中的提示信息
+func abortWithError(c *gin.Context, err error, message string) {
+	_ = c.Error(err).SetMeta(message)
+	c.Abort()
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"blog/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	// errIfMatchRequired 修改请求缺少If-Match请求头，映射为428
+	errIfMatchRequired = utils.NewAppError(http.StatusPreconditionRequired, "if_match_required", "缺少If-Match请求头", nil)
+	// errInvalidIfMatch If-Match请求头不是有效的版本号，映射为412
+	errInvalidIfMatch = utils.NewAppError(http.StatusPreconditionFailed, "invalid_if_match", "If-Match请求头格式错误", nil)
+)
+
+// setETag 以版本号作为强校验ETag写入响应头
+func setETag(c *gin.Context, version uint) {
+	c.Header("ETag", strconv.Quote(strconv.FormatUint(uint64(version), 10)))
+}
+
+// requireIfMatch 从If-Match请求头解析客户端持有的版本号，失败时记录错误并中止请求
+func requireIfMatch(c *gin.Context) (uint, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		abortWithError(c, errIfMatchRequired, "请携带If-Match请求头")
+		return 0, false
+	}
+
+	// 弱校验ETag和通配符无法保证客户端读取的是最新版本，一律拒绝
+	unquoted, err := strconv.Unquote(header)
+	if err != nil {
+		abortWithError(c, errInvalidIfMatch, "版本号无效")
+		return 0, false
+	}
+	version, err := strconv.ParseUint(unquoted, 10, 32)
+	if err != nil || version == 0 {
+		abortWithError(c, errInvalidIfMatch, "版本号无效")
+		return 0, false
+	}
+	return uint(version), true
+}
@@ -1,10 +1,16 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"path"
 	"strconv"
 
 	"blog/internal/application"
+	"blog/internal/domain/policy"
+	"blog/internal/domain/service"
 	"blog/internal/interfaces/dto"
 	"blog/pkg/utils"
 
@@ -29,9 +35,23 @@ func (h *PostHandler) Register(router *gin.RouterGroup) {
 	{
 		posts.POST("", h.CreatePost)
 		posts.GET("", h.GetAllPosts)
+		posts.GET("/search", h.SearchPosts)
+		posts.POST("/search/rebuild", h.RebuildSearchIndex)
+		posts.GET("/slug/:slug", h.GetPostBySlug)
+		posts.GET("/slug-redirects", h.ListSlugRedirects)
+		posts.DELETE("/slug-redirects/:id", h.DeleteSlugRedirect)
+		posts.GET("/trash", h.ListTrashedPosts)
 		posts.GET("/:id", h.GetPostByID)
 		posts.PUT("/:id", h.UpdatePost)
 		posts.DELETE("/:id", h.DeletePost)
+		posts.POST("/:id/restore", h.RestorePost)
+		posts.POST("/:id/publish", h.PublishPost)
+		posts.POST("/:id/unpublish", h.UnpublishPost)
+		posts.POST("/:id/schedule", h.SchedulePost)
+		posts.POST("/:id/archive", h.ArchivePost)
+		posts.GET("/:id/revisions", h.ListRevisions)
+		posts.GET("/:id/revisions/diff", h.DiffRevisions)
+		posts.POST("/:id/revisions/:rev/restore", h.RestoreRevision)
 		posts.GET("/category/:id", h.GetPostsByCategory)
 	}
 }
@@ -40,16 +60,17 @@ func (h *PostHandler) Register(router *gin.RouterGroup) {
 func (h *PostHandler) CreatePost(c *gin.Context) {
 	var req dto.CreatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.NewErrorResponse(err, "请求参数错误"))
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
 		return
 	}
 
-	post, err := h.postApp.CreatePost(&req)
+	post, err := h.postApp.CreatePost(currentActor(c), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.NewErrorResponse(err, "创建文章失败"))
+		abortWithError(c, err, "创建文章失败")
 		return
 	}
 
+	setETag(c, post.Version)
 	c.JSON(http.StatusCreated, utils.NewSuccessResponse(post, "文章创建成功"))
 }
 
@@ -57,83 +78,345 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 func (h *PostHandler) GetPostByID(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.NewErrorResponse(err, "无效的ID"))
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
 		return
 	}
 
-	post, err := h.postApp.GetPostByID(uint(id))
+	post, err := h.postApp.GetPostByID(currentActor(c), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, utils.NewErrorResponse(err, "文章不存在"))
+		abortWithError(c, err, "获取文章失败")
 		return
 	}
 
+	setETag(c, post.Version)
 	c.JSON(http.StatusOK, utils.NewSuccessResponse(post, "获取文章详情成功"))
 }
 
-// GetAllPosts 获取所有文章
+// GetPostBySlug 根据slug获取文章，旧slug返回301重定向到当前slug
+func (h *PostHandler) GetPostBySlug(c *gin.Context) {
+	post, err := h.postApp.GetPostBySlug(currentActor(c), c.Param("slug"))
+	if err != nil {
+		var moved *service.SlugMovedError
+		if errors.As(err, &moved) {
+			location := url.URL{Path: path.Join(path.Dir(c.Request.URL.Path), moved.Slug), RawQuery: c.Request.URL.RawQuery}
+			c.Header("Location", location.String())
+			c.JSON(http.StatusMovedPermanently, utils.NewErrorResponse(err, "文章地址已变更"))
+			return
+		}
+		abortWithError(c, err, "获取文章失败")
+		return
+	}
+
+	setETag(c, post.Version)
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(post, "获取文章详情成功"))
+}
+
+// ListSlugRedirects 获取slug重定向列表
+func (h *PostHandler) ListSlugRedirects(c *gin.Context) {
+	var query dto.SlugRedirectQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+
+	redirects, err := h.postApp.ListSlugRedirects(currentActor(c), &query)
+	if err != nil {
+		abortWithError(c, err, "获取slug重定向失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(redirects, "获取slug重定向成功"))
+}
+
+// DeleteSlugRedirect 删除slug重定向
+func (h *PostHandler) DeleteSlugRedirect(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
+		return
+	}
+
+	err = h.postApp.DeleteSlugRedirect(currentActor(c), uint(id))
+	if err != nil {
+		abortWithError(c, err, "删除slug重定向失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(nil, "slug重定向删除成功"))
+}
+
+// GetAllPosts 分页获取文章列表，支持按作者、分类、创建日期筛选和排序
 func (h *PostHandler) GetAllPosts(c *gin.Context) {
-	posts, err := h.postApp.GetAllPosts()
+	var query dto.PostListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+
+	listPosts(c, h.postApp, &query, "获取文章列表失败", "获取文章列表成功")
+}
+
+// SearchPosts 全文搜索文章
+func (h *PostHandler) SearchPosts(c *gin.Context) {
+	var query dto.PostSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+
+	result, err := h.postApp.SearchPosts(currentActor(c), &query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.NewErrorResponse(err, "获取文章列表失败"))
+		abortWithError(c, err, "搜索文章失败")
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.NewSuccessResponse(posts, "获取文章列表成功"))
+	pagination := utils.NewPagination(result.Total, result.Page, result.PageSize, c.Request.URL)
+	c.JSON(http.StatusOK, utils.NewPaginatedResponse(result.Posts, pagination, "搜索文章成功"))
 }
 
-// UpdatePost 更新文章
+// RebuildSearchIndex 重建独立搜索索引
+func (h *PostHandler) RebuildSearchIndex(c *gin.Context) {
+	result, err := h.postApp.RebuildSearchIndex(currentActor(c))
+	if err != nil {
+		abortWithError(c, err, "重建搜索索引失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(result, "搜索索引重建成功"))
+}
+
+// UpdatePost 更新文章，需通过If-Match携带读取时的版本号
 func (h *PostHandler) UpdatePost(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.NewErrorResponse(err, "无效的ID"))
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
+		return
+	}
+
+	version, ok := requireIfMatch(c)
+	if !ok {
 		return
 	}
 
 	var req dto.UpdatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.NewErrorResponse(err, "请求参数错误"))
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
 		return
 	}
 
-	post, err := h.postApp.UpdatePost(uint(id), &req)
+	post, err := h.postApp.UpdatePost(currentActor(c), uint(id), version, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.NewErrorResponse(err, "更新文章失败"))
+		abortWithError(c, err, "更新文章失败")
 		return
 	}
 
+	setETag(c, post.Version)
 	c.JSON(http.StatusOK, utils.NewSuccessResponse(post, "文章更新成功"))
 }
 
-// DeletePost 删除文章
+// ListRevisions 获取文章的修订历史
+func (h *PostHandler) ListRevisions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
+		return
+	}
+
+	revisions, err := h.postApp.ListRevisions(currentActor(c), uint(id))
+	if err != nil {
+		abortWithError(c, err, "获取修订历史失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(revisions, "获取修订历史成功"))
+}
+
+// DiffRevisions 比较文章的两个修订版本
+func (h *PostHandler) DiffRevisions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
+		return
+	}
+
+	var query dto.RevisionDiffQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+
+	diff, err := h.postApp.DiffRevisions(currentActor(c), uint(id), &query)
+	if err != nil {
+		abortWithError(c, err, "获取修订版本差异失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(diff, "获取修订版本差异成功"))
+}
+
+// RestoreRevision 将文章恢复为指定修订版本
+func (h *PostHandler) RestoreRevision(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("rev"))
+	if err == nil && revision < 1 {
+		err = fmt.Errorf("版本号必须大于0: %d", revision)
+	}
+	if err != nil {
+		abortWithError(c, utils.NewValidationError(err), "无效的版本号")
+		return
+	}
+
+	post, err := h.postApp.RestoreRevision(currentActor(c), uint(id), revision)
+	if err != nil {
+		abortWithError(c, err, "恢复修订版本失败")
+		return
+	}
+
+	setETag(c, post.Version)
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(post, "修订版本恢复成功"))
+}
+
+// PublishPost 立即发布文章
+func (h *PostHandler) PublishPost(c *gin.Context) {
+	h.changeStatus(c, h.postApp.PublishPost, "文章发布成功")
+}
+
+// UnpublishPost 将文章撤回为草稿
+func (h *PostHandler) UnpublishPost(c *gin.Context) {
+	h.changeStatus(c, h.postApp.UnpublishPost, "文章已撤回为草稿")
+}
+
+// ArchivePost 归档文章
+func (h *PostHandler) ArchivePost(c *gin.Context) {
+	h.changeStatus(c, h.postApp.ArchivePost, "文章归档成功")
+}
+
+// SchedulePost 设置文章定时发布
+func (h *PostHandler) SchedulePost(c *gin.Context) {
+	var req dto.SchedulePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+
+	h.changeStatus(c, func(actor *policy.Actor, id uint) (*dto.PostResponse, error) {
+		return h.postApp.SchedulePost(actor, id, &req)
+	}, "文章定时发布设置成功")
+}
+
+// changeStatus 执行文章状态变更
+func (h *PostHandler) changeStatus(c *gin.Context, change func(actor *policy.Actor, id uint) (*dto.PostResponse, error), successMessage string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
+		return
+	}
+
+	post, err := change(currentActor(c), uint(id))
+	if err != nil {
+		abortWithError(c, err, "修改文章状态失败")
+		return
+	}
+
+	setETag(c, post.Version)
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(post, successMessage))
+}
+
+// DeletePost 将文章移入回收站
 func (h *PostHandler) DeletePost(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.NewErrorResponse(err, "无效的ID"))
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
+		return
+	}
+
+	err = h.postApp.DeletePost(currentActor(c), uint(id))
+	if err != nil {
+		abortWithError(c, err, "删除文章失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(nil, "文章已移入回收站"))
+}
+
+// ListTrashedPosts 分页获取回收站中的文章
+func (h *PostHandler) ListTrashedPosts(c *gin.Context) {
+	var query dto.TrashQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+
+	result, err := h.postApp.ListTrashedPosts(currentActor(c), &query)
+	if err != nil {
+		abortWithError(c, err, "获取回收站文章失败")
+		return
+	}
+
+	pagination := utils.NewPagination(result.Total, result.Page, result.PageSize, c.Request.URL)
+	c.JSON(http.StatusOK, utils.NewPaginatedResponse(result.Posts, pagination, "获取回收站文章成功"))
+}
+
+// RestorePost 将文章移出回收站
+func (h *PostHandler) RestorePost(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
 		return
 	}
 
-	err = h.postApp.DeletePost(uint(id))
+	post, err := h.postApp.RestorePost(currentActor(c), uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.NewErrorResponse(err, "删除文章失败"))
+		abortWithError(c, err, "恢复文章失败")
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.NewSuccessResponse(nil, "文章删除成功"))
+	setETag(c, post.Version)
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(post, "文章恢复成功"))
 }
 
 // GetPostsByCategory 根据分类获取文章
 func (h *PostHandler) GetPostsByCategory(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.NewErrorResponse(err, "无效的分类ID"))
+		abortWithError(c, utils.NewValidationError(err), "无效的分类ID")
+		return
+	}
+
+	var query dto.PostListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+	query.CategoryID = uint(id)
+
+	listPosts(c, h.postApp, &query, "获取分类文章失败", "获取分类文章成功")
+}
+
+// listPosts 根据是否携带cursor参数选择游标分页或页码分页
+func listPosts(c *gin.Context, postApp *application.PostApp, query *dto.PostListQuery, failMessage, successMessage string) {
+	if query.Cursor != nil {
+		result, err := postApp.ListPostsByCursor(currentActor(c), query)
+		if err != nil {
+			abortWithError(c, err, failMessage)
+			return
+		}
+
+		cursor := utils.NewCursor(result.PageSize, result.NextCursor, result.PrevCursor, c.Request.URL)
+		c.JSON(http.StatusOK, utils.NewCursorResponse(result.Posts, cursor, successMessage))
 		return
 	}
 
-	posts, err := h.postApp.GetPostsByCategory(uint(id))
+	result, err := postApp.ListPosts(currentActor(c), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.NewErrorResponse(err, "获取分类文章失败"))
+		abortWithError(c, err, failMessage)
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.NewSuccessResponse(posts, "获取分类文章成功"))
+	pagination := utils.NewPagination(result.Total, result.Page, result.PageSize, c.Request.URL)
+	c.JSON(http.StatusOK, utils.NewPaginatedResponse(result.Posts, pagination, successMessage))
 }
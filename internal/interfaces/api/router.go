@@ -7,23 +7,32 @@ import (
 // Router API路由器
 type Router struct {
 	engine          *gin.Engine
+	authMiddleware  gin.HandlerFunc
+	userHandler     *UserHandler
 	postHandler     *PostHandler
 	commentHandler  *CommentHandler
 	categoryHandler *CategoryHandler
+	tagHandler      *TagHandler
 }
 
 // NewRouter 创建路由器
 func NewRouter(
 	engine *gin.Engine,
+	authMiddleware gin.HandlerFunc,
+	userHandler *UserHandler,
 	postHandler *PostHandler,
 	commentHandler *CommentHandler,
 	categoryHandler *CategoryHandler,
+	tagHandler *TagHandler,
 ) *Router {
 	return &Router{
 		engine:          engine,
+		authMiddleware:  authMiddleware,
+		userHandler:     userHandler,
 		postHandler:     postHandler,
 		commentHandler:  commentHandler,
 		categoryHandler: categoryHandler,
+		tagHandler:      tagHandler,
 	}
 }
 
@@ -31,10 +40,18 @@ func NewRouter(
 func (r *Router) SetupRoutes() {
 	api := r.engine.Group("/api")
 
+	// 注册、登录接口无需认证
+	r.userHandler.Register(api)
+
+	// 写操作需要认证，读操作保持公开
+	protected := api.Group("", r.authMiddleware)
+
 	// 注册各个处理器的路由
-	r.postHandler.Register(api)
-	r.commentHandler.Register(api)
-	r.categoryHandler.Register(api)
+	r.userHandler.RegisterAdmin(protected)
+	r.postHandler.Register(protected)
+	r.commentHandler.Register(protected)
+	r.categoryHandler.Register(protected)
+	r.tagHandler.Register(protected)
 }
 
 // Run 运行服务器
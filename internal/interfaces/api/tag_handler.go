@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+
+	"blog/internal/application"
+	"blog/internal/interfaces/dto"
+	"blog/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagHandler 标签处理器
+type TagHandler struct {
+	tagApp  *application.TagApp
+	postApp *application.PostApp
+}
+
+// NewTagHandler 创建标签处理器
+func NewTagHandler(tagApp *application.TagApp, postApp *application.PostApp) *TagHandler {
+	return &TagHandler{
+		tagApp:  tagApp,
+		postApp: postApp,
+	}
+}
+
+// Register 注册路由
+func (h *TagHandler) Register(router *gin.RouterGroup) {
+	tags := router.Group("/tags")
+	{
+		tags.GET("", h.GetTagCloud)
+		tags.GET("/:name/posts", h.GetPostsByTag)
+	}
+}
+
+// GetTagCloud 获取标签云
+func (h *TagHandler) GetTagCloud(c *gin.Context) {
+	var query dto.TagCloudQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+
+	tags, err := h.tagApp.GetTagCloud(&query)
+	if err != nil {
+		abortWithError(c, err, "获取标签云失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(tags, "获取标签云成功"))
+}
+
+// GetPostsByTag 根据标签获取文章
+func (h *TagHandler) GetPostsByTag(c *gin.Context) {
+	tag, err := h.tagApp.GetTagByName(c.Param("name"))
+	if err != nil {
+		abortWithError(c, err, "获取标签失败")
+		return
+	}
+
+	var query dto.PostListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+	query.Tag = tag.Name
+
+	listPosts(c, h.postApp, &query, "获取标签文章失败", "获取标签文章成功")
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"blog/internal/application"
+	"blog/internal/interfaces/dto"
+	"blog/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserHandler 用户处理器
+type UserHandler struct {
+	userApp *application.UserApp
+}
+
+// NewUserHandler 创建用户处理器
+func NewUserHandler(userApp *application.UserApp) *UserHandler {
+	return &UserHandler{
+		userApp: userApp,
+	}
+}
+
+// Register 注册路由
+func (h *UserHandler) Register(router *gin.RouterGroup) {
+	auth := router.Group("/auth")
+	{
+		auth.POST("/register", h.RegisterUser)
+		auth.POST("/login", h.Login)
+	}
+}
+
+// RegisterAdmin 注册需要认证的用户管理路由
+func (h *UserHandler) RegisterAdmin(router *gin.RouterGroup) {
+	users := router.Group("/users")
+	{
+		users.PUT("/:id/role", h.UpdateUserRole)
+	}
+}
+
+// RegisterUser 注册用户
+func (h *UserHandler) RegisterUser(c *gin.Context) {
+	var req dto.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+
+	user, err := h.userApp.Register(&req)
+	if err != nil {
+		abortWithError(c, err, "注册失败")
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.NewSuccessResponse(user, "注册成功"))
+}
+
+// Login 用户登录
+func (h *UserHandler) Login(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+
+	resp, err := h.userApp.Login(&req)
+	if err != nil {
+		abortWithError(c, err, "登录失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(resp, "登录成功"))
+}
+
+// UpdateUserRole 修改用户角色
+func (h *UserHandler) UpdateUserRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, utils.NewValidationError(err), "无效的ID")
+		return
+	}
+
+	var req dto.UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, utils.NewValidationError(err), "请求参数错误")
+		return
+	}
+
+	user, err := h.userApp.UpdateUserRole(currentActor(c), uint(id), &req)
+	if err != nil {
+		abortWithError(c, err, "修改用户角色失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.NewSuccessResponse(user, "用户角色修改成功"))
+}
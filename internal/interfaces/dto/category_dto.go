@@ -1,15 +1,29 @@
 package dto
 
+import (
+	"time"
+)
+
 // CreateCategoryRequest 创建分类请求
 type CreateCategoryRequest struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description"`
+	// ParentID 父分类ID，为空时创建顶级分类
+	ParentID *uint `json:"parent_id"`
 }
 
 // UpdateCategoryRequest 更新分类请求
 type UpdateCategoryRequest struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description"`
+	// ParentID 父分类ID，为空时移动为顶级分类
+	ParentID *uint `json:"parent_id"`
+}
+
+// CategoryListQuery 分类列表查询参数
+type CategoryListQuery struct {
+	// Tree 为true时以树形结构返回
+	Tree bool `form:"tree"`
 }
 
 // CategoryResponse 分类响应
@@ -17,4 +31,23 @@ type CategoryResponse struct {
 	ID          uint   `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	ParentID    *uint  `json:"parent_id"`
+	// Version 版本号，修改分类时通过If-Match请求头携带
+	Version uint `json:"version"`
+	// DeletedAt 移入回收站的时间，仅回收站列表返回
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// CategoryListResult 分类分页结果
+type CategoryListResult struct {
+	Categories []*CategoryResponse
+	Total      int64
+	Page       int
+	PageSize   int
+}
+
+// CategoryTreeResponse 分类树节点响应
+type CategoryTreeResponse struct {
+	*CategoryResponse
+	Children []*CategoryTreeResponse `json:"children"`
 }
@@ -4,18 +4,91 @@ import (
 	"time"
 )
 
+const (
+	// CommentFormatTree 以嵌套树形式返回评论
+	CommentFormatTree = "tree"
+	// CommentFormatFlat 以带层级和路径的平铺列表返回评论
+	CommentFormatFlat = "flat"
+)
+
 // CreateCommentRequest 创建评论请求
 type CreateCommentRequest struct {
-	PostID  uint   `json:"post_id" binding:"required"`
-	Content string `json:"content" binding:"required"`
-	Author  string `json:"author" binding:"required"`
+	PostID uint `json:"post_id" binding:"required"`
+	// ParentID 回复的评论ID，为空时发表顶级评论
+	ParentID *uint  `json:"parent_id"`
+	Content  string `json:"content" binding:"required"`
+	// Website 蜜罐字段，前端应隐藏该输入框，正常用户不会填写
+	Website string `json:"website"`
+}
+
+// CommentListQuery 评论列表查询参数
+type CommentListQuery struct {
+	Cursor   string `form:"cursor"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	// Format 返回格式，默认为tree
+	Format string `form:"format" binding:"omitempty,oneof=tree flat"`
+}
+
+// CommentModerationQuery 评论审核列表查询参数
+type CommentModerationQuery struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+	// Status 评论状态，默认为pending
+	Status string `form:"status" binding:"omitempty,oneof=pending approved spam rejected"`
+	PostID uint   `form:"post_id"`
+}
+
+// ModerateCommentsRequest 批量审核评论请求
+type ModerateCommentsRequest struct {
+	IDs    []uint `json:"ids" binding:"required,min=1,max=100,dive,min=1"`
+	Status string `json:"status" binding:"required,oneof=pending approved spam rejected"`
+}
+
+// ModerateCommentsResponse 批量审核评论响应
+type ModerateCommentsResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+// CommentListResult 评论分页结果
+type CommentListResult struct {
+	Comments []*CommentResponse
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+// CommentCursorResult 评论游标分页结果
+type CommentCursorResult struct {
+	// Comments 树形格式时为[]*CommentTreeResponse，平铺格式时为[]*CommentFlatResponse
+	Comments   interface{}
+	PageSize   int
+	NextCursor string
+	PrevCursor string
 }
 
 // CommentResponse 评论响应
 type CommentResponse struct {
 	ID        uint      `json:"id"`
 	PostID    uint      `json:"post_id"`
+	ParentID  *uint     `json:"parent_id"`
+	Depth     int       `json:"depth"`
 	Content   string    `json:"content"`
 	Author    string    `json:"author"`
+	Status    string    `json:"status"`
 	CreatedAt time.Time `json:"created_at"`
+	// DeletedAt 移入回收站的时间，仅回收站列表返回
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// CommentTreeResponse 评论树节点响应
+type CommentTreeResponse struct {
+	*CommentResponse
+	Replies []*CommentTreeResponse `json:"replies"`
+}
+
+// CommentFlatResponse 平铺评论响应
+type CommentFlatResponse struct {
+	*CommentResponse
+	// Path 从顶级评论到当前评论的ID路径
+	Path []uint `json:"path"`
 }
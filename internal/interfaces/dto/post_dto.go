@@ -6,11 +6,18 @@ import (
 
 // CreatePostRequest 创建文章请求
 type CreatePostRequest struct {
-	Title       string `json:"title" binding:"required"`
-	Content     string `json:"content" binding:"required"`
-	Author      string `json:"author" binding:"required"`
-	TitleURL    string `json:"title_url"`
+	Title    string `json:"title" binding:"required"`
+	Content  string `json:"content" binding:"required"`
+	TitleURL string `json:"title_url"`
+	// Slug 文章URL标识，为空时根据标题自动生成
+	Slug        string `json:"slug" binding:"max=100"`
 	CategoryIDs []uint `json:"category_ids"`
+	// Tags 标签名列表，不存在的标签自动创建
+	Tags []string `json:"tags" binding:"max=20,dive,max=50"`
+	// Status 初始状态，默认为草稿
+	Status string `json:"status" binding:"omitempty,oneof=draft published scheduled"`
+	// PublishAt 定时发布时间，状态为scheduled时必填
+	PublishAt *time.Time `json:"publish_at"`
 }
 
 // UpdatePostRequest 更新文章请求
@@ -18,28 +25,167 @@ type UpdatePostRequest struct {
 	Title    string `json:"title" binding:"required"`
 	Content  string `json:"content" binding:"required"`
 	TitleURL string `json:"title_url"`
+	// Slug 为空时保持不变
+	Slug string `json:"slug" binding:"max=100"`
+	// Tags 为null时保持不变，传入空数组时清空标签
+	Tags *[]string `json:"tags" binding:"omitempty,max=20,dive,max=50"`
 }
 
-// PostResponse 文章响应
-type PostResponse struct {
+// SchedulePostRequest 定时发布请求
+type SchedulePostRequest struct {
+	PublishAt time.Time `json:"publish_at" binding:"required"`
+}
+
+// PostListQuery 文章列表查询参数
+type PostListQuery struct {
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Status     string `form:"status" binding:"omitempty,oneof=draft published scheduled archived"`
+	Author     string `form:"author"`
+	CategoryID uint   `form:"category_id"`
+	// IncludeDescendants 按分类筛选时包含子孙分类的文章
+	IncludeDescendants bool      `form:"include_descendants"`
+	Tag                string    `form:"tag"`
+	From               time.Time `form:"from" time_format:"2006-01-02"`
+	To                 time.Time `form:"to" time_format:"2006-01-02"`
+	Sort               string    `form:"sort" binding:"omitempty,oneof=created_at updated_at view_count"`
+	Order              string    `form:"order" binding:"omitempty,oneof=asc desc"`
+	// Cursor 出现该参数（可为空）时使用游标分页，此时忽略page、sort和order
+	Cursor *string `form:"cursor"`
+}
+
+// PostSearchQuery 文章搜索查询参数
+type PostSearchQuery struct {
+	Q          string `form:"q" binding:"required,max=100"`
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Status     string `form:"status" binding:"omitempty,oneof=draft published scheduled archived"`
+	Author     string `form:"author"`
+	CategoryID uint   `form:"category_id"`
+}
+
+// PostListResult 文章分页结果
+type PostListResult struct {
+	Posts    []*PostResponse
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+// PostCursorResult 文章游标分页结果
+type PostCursorResult struct {
+	Posts      []*PostResponse
+	PageSize   int
+	NextCursor string
+	PrevCursor string
+}
+
+// PostSearchResult 文章搜索分页结果
+type PostSearchResult struct {
+	Posts    []*PostSearchResponse
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+// PostSearchResponse 文章搜索结果，高亮字段已做HTML转义，命中部分以<mark>标签包裹
+type PostSearchResponse struct {
+	*PostResponse
+	Score          float64 `json:"score"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
+
+// RebuildSearchIndexResponse 重建搜索索引响应
+type RebuildSearchIndexResponse struct {
+	Indexed int `json:"indexed"`
+}
+
+// SlugRedirectQuery slug重定向列表查询参数
+type SlugRedirectQuery struct {
+	PostID uint `form:"post_id"`
+}
+
+// SlugRedirectResponse slug重定向响应
+type SlugRedirectResponse struct {
 	ID        uint      `json:"id"`
+	OldSlug   string    `json:"old_slug"`
+	PostID    uint      `json:"post_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PostRevisionResponse 文章修订版本响应，不包含正文
+type PostRevisionResponse struct {
+	Revision  int       `json:"revision"`
 	Title     string    `json:"title"`
-	Author    string    `json:"author"`
 	TitleURL  string    `json:"title_url"`
-	ViewCount uint      `json:"view_count"`
+	Author    string    `json:"author"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RevisionDiffQuery 修订版本对比查询参数
+type RevisionDiffQuery struct {
+	From int `form:"from" binding:"required,min=1"`
+	To   int `form:"to" binding:"required,min=1"`
+}
+
+// DiffLineResponse 行级差异中的一行
+type DiffLineResponse struct {
+	// Op 操作类型：equal、insert、delete
+	Op   string `json:"op"`
+	Text string `json:"text"`
+	// OldLine 在旧版本中的行号，新增的行不返回
+	OldLine int `json:"old_line,omitempty"`
+	// NewLine 在新版本中的行号，删除的行不返回
+	NewLine int `json:"new_line,omitempty"`
+}
+
+// RevisionDiffResponse 修订版本对比响应
+type RevisionDiffResponse struct {
+	PostID uint                  `json:"post_id"`
+	From   *PostRevisionResponse `json:"from"`
+	To     *PostRevisionResponse `json:"to"`
+	// Added 新增的行数
+	Added int `json:"added"`
+	// Removed 删除的行数
+	Removed int                 `json:"removed"`
+	Lines   []*DiffLineResponse `json:"lines"`
+}
+
+// PostResponse 文章响应
+type PostResponse struct {
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Author      string     `json:"author"`
+	TitleURL    string     `json:"title_url"`
+	Slug        string     `json:"slug"`
+	ViewCount   uint       `json:"view_count"`
+	Status      string     `json:"status"`
+	PublishedAt *time.Time `json:"published_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	// Version 版本号，修改文章时通过If-Match请求头携带
+	Version uint `json:"version"`
+	// DeletedAt 移入回收站的时间，仅回收站列表返回
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Tags 仅在创建和更新文章时返回
+	Tags []string `json:"tags,omitempty"`
 }
 
 // PostDetailResponse 文章详情响应
 type PostDetailResponse struct {
-	ID         uint                `json:"id"`
-	Title      string              `json:"title"`
-	Content    string              `json:"content"`
-	Author     string              `json:"author"`
-	TitleURL   string              `json:"title_url"`
-	ViewCount  uint                `json:"view_count"`
-	CreatedAt  time.Time           `json:"created_at"`
-	UpdatedAt  time.Time           `json:"updated_at"`
-	Categories []*CategoryResponse `json:"categories"`
+	ID          uint                `json:"id"`
+	Title       string              `json:"title"`
+	Content     string              `json:"content"`
+	Author      string              `json:"author"`
+	TitleURL    string              `json:"title_url"`
+	Slug        string              `json:"slug"`
+	ViewCount   uint                `json:"view_count"`
+	Status      string              `json:"status"`
+	PublishedAt *time.Time          `json:"published_at"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	Version     uint                `json:"version"`
+	Categories  []*CategoryResponse `json:"categories"`
+	Tags        []string            `json:"tags"`
 }
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"time"
+)
+
+// TagCloudQuery 标签云查询参数
+type TagCloudQuery struct {
+	// Limit 返回的标签数量，为空时不限
+	Limit int `form:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+// TagResponse 标签响应
+type TagResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TagCountResponse 标签云条目响应
+type TagCountResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	PostCount int64  `json:"post_count"`
+}
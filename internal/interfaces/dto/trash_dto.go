@@ -0,0 +1,7 @@
+package dto
+
+// TrashQuery 回收站列表查询参数
+type TrashQuery struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"blog/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ContextUserID 上下文中的用户ID键
+	ContextUserID = "user_id"
+	// ContextUsername 上下文中的用户名键
+	ContextUsername = "username"
+	// ContextRole 上下文中的用户角色键
+	ContextRole = "role"
+)
+
+// UserLookup 根据用户ID查询用户当前的用户名和角色，用户不存在时返回的错误应包装utils.ErrNotFound
+type UserLookup func(userID uint) (username, role string, err error)
+
+// Auth JWT认证中间件
+// GET、HEAD、OPTIONS请求无需令牌即可访问，其余请求必须携带有效令牌
+// 令牌只用于确认用户身份，用户名和角色每次请求通过lookup查询，角色被修改后立即生效
+// 认证失败的错误交由ErrorHandler生成响应
+func Auth(secret string, lookup UserLookup) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := bearerToken(c.GetHeader("Authorization"))
+		if tokenString == "" {
+			if isReadOnly(c.Request.Method) {
+				c.Next()
+				return
+			}
+			_ = c.Error(fmt.Errorf("缺少认证令牌: %w", utils.ErrUnauthorized)).SetMeta("未登录")
+			c.Abort()
+			return
+		}
+
+		claims, err := utils.ParseToken(secret, tokenString)
+		if err != nil {
+			_ = c.Error(fmt.Errorf("%w: %w", utils.ErrUnauthorized, err)).SetMeta("认证令牌无效")
+			c.Abort()
+			return
+		}
+
+		username, role, err := lookup(claims.UserID)
+		if errors.Is(err, utils.ErrNotFound) {
+			_ = c.Error(fmt.Errorf("%w: %w", utils.ErrUnauthorized, err)).SetMeta("用户不存在")
+			c.Abort()
+			return
+		}
+		if err != nil {
+			_ = c.Error(fmt.Errorf("查询当前用户失败: %w", err))
+			c.Abort()
+			return
+		}
+
+		c.Set(ContextUserID, claims.UserID)
+		c.Set(ContextUsername, username)
+		c.Set(ContextRole, role)
+		c.Next()
+	}
+}
+
+// CurrentUsername 获取当前登录用户名
+func CurrentUsername(c *gin.Context) (string, bool) {
+	username := c.GetString(ContextUsername)
+	return username, username != ""
+}
+
+// CurrentRole 获取当前登录用户角色
+func CurrentRole(c *gin.Context) string {
+	return c.GetString(ContextRole)
+}
+
+// CurrentUserID 获取当前登录用户ID
+func CurrentUserID(c *gin.Context) (uint, bool) {
+	value, ok := c.Get(ContextUserID)
+	if !ok {
+		return 0, false
+	}
+	userID, ok := value.(uint)
+	return userID, ok
+}
+
+// 从Authorization头中提取Bearer令牌
+func bearerToken(header string) string {
+	const prefix = "Bearer "
+	if len(header) > len(prefix) && strings.EqualFold(header[:len(prefix)], prefix) {
+		return strings.TrimSpace(header[len(prefix):])
+	}
+	return ""
+}
+
+// 判断是否为只读请求
+func isReadOnly(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"blog/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errInternal 未归类的错误统一视为服务器内部错误
+var errInternal = utils.NewAppError(http.StatusInternalServerError, "internal_error", "服务器内部错误", nil)
+
+// ErrorHandler 错误处理中间件，将处理器通过c.Error记录的最后一个错误转换为统一的错误响应
+// 错误链中包含utils.AppError时使用其HTTP状态码和错误码，否则返回500
+// 错误的Meta为字符串时作为响应的提示信息，否则使用错误类别的默认提示
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		last := c.Errors.Last()
+		appErr := errInternal
+		errors.As(last.Err, &appErr)
+		if appErr.Code >= http.StatusInternalServerError {
+			log.Printf("请求 %s %s 处理失败: %v", c.Request.Method, c.Request.URL.Path, last.Err)
+		}
+
+		message, ok := last.Meta.(string)
+		if !ok || message == "" {
+			message = appErr.Message
+		}
+		resp := utils.NewErrorResponse(last.Err, message)
+		resp.Code = appErr.Reason
+		c.JSON(appErr.Code, resp)
+	}
+}
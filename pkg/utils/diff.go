@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"slices"
+	"strings"
+)
+
+// DiffOp 行级差异的操作类型
+type DiffOp string
+
+const (
+	// DiffEqual 两边相同的行
+	DiffEqual DiffOp = "equal"
+	// DiffInsert 新文本中新增的行
+	DiffInsert DiffOp = "insert"
+	// DiffDelete 旧文本中删除的行
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffLine 行级差异中的一行
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+	// OldLine 在旧文本中的行号，从1开始，新增的行为0
+	OldLine int
+	// NewLine 在新文本中的行号，从1开始，删除的行为0
+	NewLine int
+}
+
+// DiffLines 按行比较两段文本，返回最短编辑脚本
+// 使用Myers差分算法，时间复杂度为O((N+M)D)，D为差异行数
+func DiffLines(oldText, newText string) []DiffLine {
+	a, b := splitLines(oldText), splitLines(newText)
+	lines := myersDiff(a, b)
+
+	oldLine, newLine := 0, 0
+	for i := range lines {
+		switch lines[i].Op {
+		case DiffEqual:
+			oldLine++
+			newLine++
+			lines[i].OldLine, lines[i].NewLine = oldLine, newLine
+		case DiffDelete:
+			oldLine++
+			lines[i].OldLine = oldLine
+		case DiffInsert:
+			newLine++
+			lines[i].NewLine = newLine
+		}
+	}
+	return lines
+}
+
+// splitLines 将文本拆分为行，兼容\r\n换行，空文本没有任何行
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+// myersDiff 计算从a到b的最短编辑脚本
+func myersDiff(a, b []string) []DiffLine {
+	n, m := len(a), len(b)
+	offset := n + m
+	v := make([]int, 2*offset+2)
+
+	// trace[d]记录第d轮开始前各对角线到达的最远位置，用于回溯编辑路径
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, slices.Clone(v))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackDiff(trace, a, b, offset)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrackDiff 从终点沿trace回溯出编辑脚本
+func backtrackDiff(trace [][]int, a, b []string, offset int) []DiffLine {
+	var lines []DiffLine
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			lines = append(lines, DiffLine{Op: DiffEqual, Text: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				lines = append(lines, DiffLine{Op: DiffInsert, Text: b[y-1]})
+			} else {
+				lines = append(lines, DiffLine{Op: DiffDelete, Text: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	slices.Reverse(lines)
+	return lines
+}
@@ -1,14 +1,32 @@
 package utils
 
-import "fmt"
+import (
+	"fmt"
+	"net/http"
+)
 
-// AppError 应用错误
+// AppError 应用错误，Code为对应的HTTP状态码，Reason为返回给客户端的机器可读错误码
 type AppError struct {
 	Code    int
+	Reason  string
 	Message string
 	Err     error
 }
 
+// 错误类别，仓储和领域服务通过%w包装这些错误，由错误处理中间件统一映射为HTTP状态码
+var (
+	// ErrNotFound 资源不存在
+	ErrNotFound = NewAppError(http.StatusNotFound, "not_found", "资源不存在", nil)
+	// ErrConflict 资源冲突，如名称或slug已被使用
+	ErrConflict = NewAppError(http.StatusConflict, "conflict", "资源冲突", nil)
+	// ErrValidation 请求参数校验失败
+	ErrValidation = NewAppError(http.StatusBadRequest, "validation_failed", "参数校验失败", nil)
+	// ErrUnauthorized 未登录或身份校验失败
+	ErrUnauthorized = NewAppError(http.StatusUnauthorized, "unauthorized", "未登录或身份校验失败", nil)
+	// ErrForbidden 没有权限执行该操作
+	ErrForbidden = NewAppError(http.StatusForbidden, "forbidden", "没有权限执行该操作", nil)
+)
+
 // Error 实现error接口
 func (e *AppError) Error() string {
 	if e.Err != nil {
@@ -17,15 +35,26 @@ func (e *AppError) Error() string {
 	return e.Message
 }
 
+// Unwrap 返回原始错误
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
 // NewAppError 创建应用错误
-func NewAppError(code int, message string, err error) *AppError {
+func NewAppError(code int, reason, message string, err error) *AppError {
 	return &AppError{
 		Code:    code,
+		Reason:  reason,
 		Message: message,
 		Err:     err,
 	}
 }
 
+// NewValidationError 将请求参数绑定、解析失败的错误归类为参数校验错误
+func NewValidationError(err error) error {
+	return fmt.Errorf("%w: %w", ErrValidation, err)
+}
+
 // Wrap 包装错误
 func Wrap(err error, message string) error {
 	if err == nil {
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// HighlightOpenTag 高亮开始标签
+	HighlightOpenTag = "<mark>"
+	// HighlightCloseTag 高亮结束标签
+	HighlightCloseTag = "</mark>"
+)
+
+// SearchTerms 将搜索关键词拆分为高亮用的词项
+// 按空白拆分，连续的中日韩文字额外拆成二元组，以便与ngram分词的匹配结果对应
+func SearchTerms(keyword string) []string {
+	var terms []string
+	for _, field := range strings.Fields(strings.ToLower(keyword)) {
+		terms = append(terms, field)
+
+		runes := []rune(field)
+		for i := 0; i+1 < len(runes); i++ {
+			if isCJK(runes[i]) && isCJK(runes[i+1]) {
+				terms = append(terms, string(runes[i:i+2]))
+			}
+		}
+	}
+	return terms
+}
+
+// Highlight 截取文本中包含词项的片段，并用<mark>标签包裹命中部分
+// 返回值已做HTML转义，maxRunes为片段的最大字符数，小于等于0时返回全文
+func Highlight(text string, terms []string, maxRunes int) string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	matches := findMatches(lower, terms)
+
+	start, end := 0, len(runes)
+	if maxRunes > 0 && len(runes) > maxRunes {
+		// 命中位置前保留少量上下文
+		if len(matches) > 0 {
+			start = matches[0][0] - maxRunes/4
+			if start < 0 {
+				start = 0
+			}
+		}
+		end = start + maxRunes
+		if end > len(runes) {
+			end = len(runes)
+			start = end - maxRunes
+		}
+	}
+
+	var builder strings.Builder
+	if start > 0 {
+		builder.WriteString("…")
+	}
+	pos := start
+	for _, match := range matches {
+		matchStart, matchEnd := match[0], match[1]
+		if matchEnd <= start || matchStart >= end {
+			continue
+		}
+		if matchStart < start {
+			matchStart = start
+		}
+		if matchEnd > end {
+			matchEnd = end
+		}
+		builder.WriteString(html.EscapeString(string(runes[pos:matchStart])))
+		builder.WriteString(HighlightOpenTag)
+		builder.WriteString(html.EscapeString(string(runes[matchStart:matchEnd])))
+		builder.WriteString(HighlightCloseTag)
+		pos = matchEnd
+	}
+	builder.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		builder.WriteString("…")
+	}
+	return builder.String()
+}
+
+// findMatches 查找所有词项的命中区间，重叠或相邻的区间会被合并
+func findMatches(text []rune, terms []string) [][2]int {
+	var matches [][2]int
+	for _, term := range terms {
+		pattern := []rune(term)
+		if len(pattern) == 0 {
+			continue
+		}
+		for i := 0; i+len(pattern) <= len(text); i++ {
+			if runesEqual(text[i:i+len(pattern)], pattern) {
+				matches = append(matches, [2]int{i, i + len(pattern)})
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i][0] < matches[j][0]
+	})
+	merged := matches[:1]
+	for _, match := range matches[1:] {
+		last := &merged[len(merged)-1]
+		if match[0] <= last[1] {
+			if match[1] > last[1] {
+				last[1] = match[1]
+			}
+			continue
+		}
+		merged = append(merged, match)
+	}
+	return merged
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isCJK 判断是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
@@ -1,11 +1,38 @@
 package utils
 
+import (
+	"net/url"
+	"strconv"
+)
+
 // Response 统一响应结构
 type Response struct {
-	Success bool        `json:"success"`
-	Data    interface{} `json:"data"`
-	Message string      `json:"message"`
-	Error   string      `json:"error,omitempty"`
+	Success    bool        `json:"success"`
+	Data       interface{} `json:"data"`
+	Message    string      `json:"message"`
+	Error      string      `json:"error,omitempty"`
+	Code       string      `json:"code,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Cursor     *Cursor     `json:"cursor,omitempty"`
+}
+
+// Pagination 分页信息
+type Pagination struct {
+	Total      int64  `json:"total"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"page_size"`
+	TotalPages int    `json:"total_pages"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+}
+
+// Cursor 游标分页信息
+type Cursor struct {
+	PageSize   int    `json:"page_size"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
 }
 
 // NewSuccessResponse 创建成功响应
@@ -17,6 +44,26 @@ func NewSuccessResponse(data interface{}, message string) Response {
 	}
 }
 
+// NewPaginatedResponse 创建带分页信息的成功响应
+func NewPaginatedResponse(data interface{}, pagination *Pagination, message string) Response {
+	return Response{
+		Success:    true,
+		Data:       data,
+		Message:    message,
+		Pagination: pagination,
+	}
+}
+
+// NewCursorResponse 创建带游标分页信息的成功响应
+func NewCursorResponse(data interface{}, cursor *Cursor, message string) Response {
+	return Response{
+		Success: true,
+		Data:    data,
+		Message: message,
+		Cursor:  cursor,
+	}
+}
+
 // NewErrorResponse 创建错误响应
 func NewErrorResponse(err error, message string) Response {
 	errMsg := ""
@@ -31,3 +78,57 @@ func NewErrorResponse(err error, message string) Response {
 		Error:   errMsg,
 	}
 }
+
+// NewPagination 创建分页信息，上下页链接基于当前请求地址生成并保留其余查询参数
+func NewPagination(total int64, page, pageSize int, requestURL *url.URL) *Pagination {
+	totalPages := 0
+	if pageSize > 0 {
+		totalPages = int((total + int64(pageSize) - 1) / int64(pageSize))
+	}
+
+	pagination := &Pagination{
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}
+	if page < totalPages {
+		pagination.Next = pageLink(requestURL, page+1)
+	}
+	if page > 1 {
+		pagination.Prev = pageLink(requestURL, page-1)
+	}
+	return pagination
+}
+
+// NewCursor 创建游标分页信息，上下页链接基于当前请求地址生成并保留其余查询参数
+func NewCursor(pageSize int, nextCursor, prevCursor string, requestURL *url.URL) *Cursor {
+	cursor := &Cursor{
+		PageSize:   pageSize,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	}
+	if nextCursor != "" {
+		cursor.Next = queryLink(requestURL, "cursor", nextCursor)
+	}
+	if prevCursor != "" {
+		cursor.Prev = queryLink(requestURL, "cursor", prevCursor)
+	}
+	return cursor
+}
+
+// 生成指定页码的链接
+func pageLink(requestURL *url.URL, page int) string {
+	return queryLink(requestURL, "page", strconv.Itoa(page))
+}
+
+// 替换当前请求地址中的某个查询参数生成链接
+func queryLink(requestURL *url.URL, key, value string) string {
+	if requestURL == nil {
+		return ""
+	}
+	query := requestURL.Query()
+	query.Set(key, value)
+	link := url.URL{Path: requestURL.Path, RawQuery: query.Encode()}
+	return link.String()
+}
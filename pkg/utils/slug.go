@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+)
+
+// MaxSlugLength slug的最大长度
+const MaxSlugLength = 80
+
+// Slugify 将文本转换为URL友好的slug
+// 英文和数字转为小写，汉字转写为不带声调的拼音，其余字符视为分隔符，各部分以短横线连接
+func Slugify(text string) string {
+	var parts []string
+	var word strings.Builder
+
+	flush := func() {
+		if word.Len() > 0 {
+			parts = append(parts, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			if py := pinyin.LazyConvert(string(r), nil); len(py) > 0 {
+				parts = append(parts, py[0])
+			}
+		case r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(unicode.ToLower(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	slug := strings.Join(parts, "-")
+	if len(slug) > MaxSlugLength {
+		slug = slug[:MaxSlugLength]
+		// 尽量在单词边界截断
+		if i := strings.LastIndexByte(slug, '-'); i > 0 {
+			slug = slug[:i]
+		}
+		slug = strings.Trim(slug, "-")
+	}
+	return slug
+}
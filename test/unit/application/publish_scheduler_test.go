@@ -0,0 +1,173 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"blog/internal/application"
+	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
+	"blog/internal/domain/repository"
+	"blog/internal/domain/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// 模拟文章仓储，只实现后台任务用到的方法
+type MockPostRepository struct {
+	repository.PostRepository
+	mock.Mock
+}
+
+func (m *MockPostRepository) GetDueScheduled(now time.Time) ([]*entity.Post, error) {
+	args := m.Called(now)
+	return args.Get(0).([]*entity.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) Update(post *entity.Post) error {
+	args := m.Called(post)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) PurgeTrashed(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// 固定时钟
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+// 模拟锁
+type fakeLocker struct {
+	held     bool
+	err      error
+	released int
+}
+
+func (l *fakeLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	if l.err != nil {
+		return nil, false, l.err
+	}
+	if l.held {
+		return nil, false, nil
+	}
+	l.held = true
+	return func() {
+		l.held = false
+		l.released++
+	}, true, nil
+}
+
+func newScheduler(repo *MockPostRepository, locker application.Locker, clock application.Clock) *application.PublishScheduler {
+	postService := service.NewPostService(repo, nil, nil, nil, policy.NewPolicy())
+	return application.NewPublishScheduler(postService, locker, clock, time.Minute)
+}
+
+func TestPublishSchedulerRunOnce(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	publishAt := now.Add(-time.Minute)
+	post := &entity.Post{ID: 1, Title: "定时文章", Status: entity.PostStatusScheduled, PublishedAt: &publishAt}
+
+	repo := new(MockPostRepository)
+	repo.On("GetDueScheduled", now).Return([]*entity.Post{post}, nil)
+	repo.On("Update", post).Return(nil)
+
+	locker := &fakeLocker{}
+	scheduler := newScheduler(repo, locker, &fakeClock{now: now})
+
+	count, err := scheduler.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, entity.PostStatusPublished, post.Status)
+	assert.Equal(t, publishAt, *post.PublishedAt)
+	assert.Equal(t, 1, locker.released)
+	repo.AssertExpectations(t)
+}
+
+func TestPublishSchedulerContinuesAfterFailedPost(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	publishAt := now.Add(-time.Minute)
+	editing := &entity.Post{ID: 1, Title: "正在编辑的文章", Status: entity.PostStatusScheduled, PublishedAt: &publishAt}
+	post := &entity.Post{ID: 2, Title: "定时文章", Status: entity.PostStatusScheduled, PublishedAt: &publishAt}
+
+	repo := new(MockPostRepository)
+	repo.On("GetDueScheduled", now).Return([]*entity.Post{editing, post}, nil)
+	repo.On("Update", editing).Return(fmt.Errorf("更新文章 1 失败: %w", repository.ErrVersionConflict))
+	repo.On("Update", post).Return(nil)
+
+	scheduler := newScheduler(repo, &fakeLocker{}, &fakeClock{now: now})
+
+	// 版本冲突的文章被跳过，其余文章照常发布
+	count, err := scheduler.RunOnce(context.Background())
+	assert.ErrorIs(t, err, repository.ErrVersionConflict)
+	assert.Contains(t, err.Error(), "发布文章 1 失败")
+	assert.Equal(t, 1, count)
+	assert.Equal(t, entity.PostStatusPublished, post.Status)
+	repo.AssertExpectations(t)
+}
+
+func TestPublishSchedulerSkipsWhenLocked(t *testing.T) {
+	repo := new(MockPostRepository)
+	locker := &fakeLocker{held: true}
+	scheduler := newScheduler(repo, locker, &fakeClock{now: time.Now()})
+
+	count, err := scheduler.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	repo.AssertNotCalled(t, "GetDueScheduled", mock.Anything)
+}
+
+func TestPublishSchedulerLockError(t *testing.T) {
+	repo := new(MockPostRepository)
+	locker := &fakeLocker{err: errors.New("连接失败")}
+	scheduler := newScheduler(repo, locker, &fakeClock{now: time.Now()})
+
+	_, err := scheduler.RunOnce(context.Background())
+
+	assert.Error(t, err)
+	repo.AssertNotCalled(t, "GetDueScheduled", mock.Anything)
+}
+
+// 记录调用的时钟，用于确认后台任务已执行
+type signalClock struct {
+	now    time.Time
+	called chan struct{}
+}
+
+func (c *signalClock) Now() time.Time {
+	select {
+	case c.called <- struct{}{}:
+	default:
+	}
+	return c.now
+}
+
+func TestPublishSchedulerStartStop(t *testing.T) {
+	now := time.Now()
+	repo := new(MockPostRepository)
+	repo.On("GetDueScheduled", now).Return([]*entity.Post{}, nil)
+
+	clock := &signalClock{now: now, called: make(chan struct{}, 1)}
+	scheduler := newScheduler(repo, &fakeLocker{}, clock)
+	scheduler.Start()
+
+	select {
+	case <-clock.called:
+	case <-time.After(time.Second):
+		t.Fatal("定时发布任务未执行")
+	}
+	scheduler.Stop()
+	repo.AssertExpectations(t)
+}
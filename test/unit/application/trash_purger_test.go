@@ -0,0 +1,106 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"blog/internal/application"
+	"blog/internal/domain/policy"
+	"blog/internal/domain/repository"
+	"blog/internal/domain/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// 模拟评论仓储，只实现回收站清理用到的方法
+type MockCommentRepository struct {
+	repository.CommentRepository
+	mock.Mock
+}
+
+func (m *MockCommentRepository) PurgeTrashed(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// 模拟分类仓储，只实现回收站清理用到的方法
+type MockCategoryRepository struct {
+	repository.CategoryRepository
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) PurgeTrashed(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func newTrashPurger(postRepo *MockPostRepository, commentRepo *MockCommentRepository, categoryRepo *MockCategoryRepository, locker application.Locker, clock application.Clock) *application.TrashPurger {
+	p := policy.NewPolicy()
+	postService := service.NewPostService(postRepo, categoryRepo, nil, nil, p)
+	commentService := service.NewCommentService(commentRepo, postRepo, p, service.CommentSettings{})
+	categoryService := service.NewCategoryService(categoryRepo, p)
+	return application.NewTrashPurger(postService, commentService, categoryService, locker, clock, time.Hour, 30*24*time.Hour)
+}
+
+func TestTrashPurgerRunOnce(t *testing.T) {
+	now := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	before := now.Add(-30 * 24 * time.Hour)
+
+	postRepo := new(MockPostRepository)
+	commentRepo := new(MockCommentRepository)
+	categoryRepo := new(MockCategoryRepository)
+	commentRepo.On("PurgeTrashed", before).Return(int64(2), nil)
+	postRepo.On("PurgeTrashed", before).Return(int64(1), nil)
+	categoryRepo.On("PurgeTrashed", before).Return(int64(0), nil)
+
+	locker := &fakeLocker{}
+	purger := newTrashPurger(postRepo, commentRepo, categoryRepo, locker, &fakeClock{now: now})
+
+	count, err := purger.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.Equal(t, 1, locker.released)
+	postRepo.AssertExpectations(t)
+	commentRepo.AssertExpectations(t)
+	categoryRepo.AssertExpectations(t)
+}
+
+// 某类数据清理失败时不影响其余数据的清理
+func TestTrashPurgerContinuesAfterError(t *testing.T) {
+	now := time.Now()
+	before := now.Add(-30 * 24 * time.Hour)
+
+	postRepo := new(MockPostRepository)
+	commentRepo := new(MockCommentRepository)
+	categoryRepo := new(MockCategoryRepository)
+	commentRepo.On("PurgeTrashed", before).Return(int64(0), errors.New("连接失败"))
+	postRepo.On("PurgeTrashed", before).Return(int64(1), nil)
+	categoryRepo.On("PurgeTrashed", before).Return(int64(1), nil)
+
+	purger := newTrashPurger(postRepo, commentRepo, categoryRepo, &fakeLocker{}, &fakeClock{now: now})
+
+	count, err := purger.RunOnce(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, int64(2), count)
+	postRepo.AssertExpectations(t)
+	categoryRepo.AssertExpectations(t)
+}
+
+func TestTrashPurgerSkipsWhenLocked(t *testing.T) {
+	postRepo := new(MockPostRepository)
+	commentRepo := new(MockCommentRepository)
+	categoryRepo := new(MockCategoryRepository)
+	purger := newTrashPurger(postRepo, commentRepo, categoryRepo, &fakeLocker{held: true}, &fakeClock{now: time.Now()})
+
+	count, err := purger.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+	commentRepo.AssertNotCalled(t, "PurgeTrashed", mock.Anything)
+	postRepo.AssertNotCalled(t, "PurgeTrashed", mock.Anything)
+}
@@ -0,0 +1,35 @@
+package entity_test
+
+import (
+	"testing"
+
+	"blog/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCategoryTree(t *testing.T) {
+	tech, life := uint(1), uint(2)
+	missing := uint(99)
+	categories := []*entity.Category{
+		{ID: 3, Name: "Go", ParentID: &tech},
+		{ID: 1, Name: "技术"},
+		{ID: 4, Name: "并发", ParentID: &[]uint{3}[0]},
+		{ID: 2, Name: "生活"},
+		{ID: 5, Name: "旅行", ParentID: &life},
+		{ID: 6, Name: "孤立分类", ParentID: &missing},
+	}
+
+	roots := entity.BuildCategoryTree(categories)
+	assert.Len(t, roots, 3)
+	assert.Equal(t, "技术", roots[0].Category.Name)
+	assert.Equal(t, "生活", roots[1].Category.Name)
+	// 父分类不存在时作为顶级节点
+	assert.Equal(t, "孤立分类", roots[2].Category.Name)
+
+	assert.Len(t, roots[0].Children, 1)
+	assert.Equal(t, "Go", roots[0].Children[0].Category.Name)
+	assert.Len(t, roots[0].Children[0].Children, 1)
+	assert.Equal(t, "并发", roots[0].Children[0].Children[0].Category.Name)
+	assert.Len(t, roots[1].Children, 1)
+}
@@ -0,0 +1,38 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"blog/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPost_Lifecycle(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	post := entity.NewPost("标题", "内容", "作者", "")
+	assert.Equal(t, entity.PostStatusDraft, post.Status)
+	assert.Nil(t, post.PublishedAt)
+
+	// 定时发布时间必须晚于当前时间
+	assert.ErrorIs(t, post.Schedule(now.Add(-time.Hour), now), entity.ErrInvalidSchedule)
+	assert.NoError(t, post.Schedule(now.Add(time.Hour), now))
+	assert.Equal(t, entity.PostStatusScheduled, post.Status)
+
+	// 提前发布时使用实际发布时间
+	post.Publish(now)
+	assert.True(t, post.IsPublished())
+	assert.Equal(t, now, *post.PublishedAt)
+
+	// 再次发布保留首次发布时间
+	post.Publish(now.Add(time.Hour))
+	assert.Equal(t, now, *post.PublishedAt)
+
+	post.Archive(now)
+	assert.Equal(t, entity.PostStatusArchived, post.Status)
+
+	post.Unpublish(now)
+	assert.Equal(t, entity.PostStatusDraft, post.Status)
+	assert.Nil(t, post.PublishedAt)
+}
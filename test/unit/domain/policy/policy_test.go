@@ -0,0 +1,60 @@
+package policy_test
+
+import (
+	"testing"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_PostPermissions(t *testing.T) {
+	p := policy.NewPolicy()
+	post := &entity.Post{ID: 1, Author: "zhangsan"}
+
+	owner := &policy.Actor{UserID: 1, Username: "zhangsan", Role: entity.RoleAuthor}
+	other := &policy.Actor{UserID: 2, Username: "lisi", Role: entity.RoleAuthor}
+	editor := &policy.Actor{UserID: 3, Username: "wangwu", Role: entity.RoleEditor}
+	reader := &policy.Actor{UserID: 4, Username: "zhaoliu", Role: entity.RoleReader}
+
+	assert.NoError(t, p.CanCreatePost(owner))
+	assert.ErrorIs(t, p.CanCreatePost(reader), policy.ErrForbidden)
+	assert.ErrorIs(t, p.CanCreatePost(nil), policy.ErrForbidden)
+
+	assert.NoError(t, p.CanUpdatePost(owner, post))
+	assert.NoError(t, p.CanUpdatePost(editor, post))
+	assert.ErrorIs(t, p.CanUpdatePost(other, post), policy.ErrForbidden)
+	assert.ErrorIs(t, p.CanDeletePost(reader, post), policy.ErrForbidden)
+}
+
+func TestPolicy_CommentAndCategoryPermissions(t *testing.T) {
+	p := policy.NewPolicy()
+	post := &entity.Post{ID: 1, Author: "zhangsan"}
+
+	admin := &policy.Actor{UserID: 1, Username: "admin", Role: entity.RoleAdmin}
+	editor := &policy.Actor{UserID: 2, Username: "wangwu", Role: entity.RoleEditor}
+	owner := &policy.Actor{UserID: 3, Username: "zhangsan", Role: entity.RoleAuthor}
+	reader := &policy.Actor{UserID: 4, Username: "zhaoliu", Role: entity.RoleReader}
+
+	assert.NoError(t, p.CanCreateComment(reader))
+	assert.ErrorIs(t, p.CanCreateComment(nil), policy.ErrForbidden)
+
+	assert.NoError(t, p.CanDeleteComment(editor, post))
+	assert.NoError(t, p.CanDeleteComment(owner, post))
+	assert.ErrorIs(t, p.CanDeleteComment(reader, post), policy.ErrForbidden)
+
+	assert.NoError(t, p.CanModerateComments(editor))
+	assert.ErrorIs(t, p.CanModerateComments(owner), policy.ErrForbidden)
+	pending := &entity.Comment{ID: 1, Author: "zhaoliu", Status: entity.CommentStatusPending}
+	assert.NoError(t, p.CanViewComment(reader, pending))
+	assert.NoError(t, p.CanViewComment(editor, pending))
+	assert.ErrorIs(t, p.CanViewComment(owner, pending), policy.ErrForbidden)
+	assert.ErrorIs(t, p.CanViewComment(nil, pending), policy.ErrForbidden)
+
+	assert.NoError(t, p.CanManageCategories(admin))
+	assert.ErrorIs(t, p.CanManageCategories(editor), policy.ErrForbidden)
+
+	assert.NoError(t, p.CanRebuildSearchIndex(admin))
+	assert.ErrorIs(t, p.CanRebuildSearchIndex(editor), policy.ErrForbidden)
+}
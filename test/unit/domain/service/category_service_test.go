@@ -0,0 +1,130 @@
+package service_test
+
+import (
+	"fmt"
+	"testing"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
+	"blog/internal/domain/service"
+	"blog/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var categoryAdmin = &policy.Actor{UserID: 5, Username: "管理员", Role: entity.RoleAdmin}
+
+// 测试修改父分类时的环检测
+func TestUpdateCategoryParent(t *testing.T) {
+	mockCategoryRepo := new(MockCategoryRepository)
+	categoryService := service.NewCategoryService(mockCategoryRepo, policy.NewPolicy())
+
+	// 分类层级：1 -> 2 -> 3
+	one, two := uint(1), uint(2)
+	mockCategoryRepo.On("GetByID", uint(1)).Return(&entity.Category{ID: 1, Name: "技术", Version: 1}, nil)
+	mockCategoryRepo.On("GetByID", uint(2)).Return(&entity.Category{ID: 2, Name: "后端", ParentID: &one, Version: 1}, nil)
+	mockCategoryRepo.On("GetByID", uint(3)).Return(&entity.Category{ID: 3, Name: "Go", ParentID: &two, Version: 1}, nil)
+
+	// 不能设置为自身的子分类
+	_, err := categoryService.UpdateCategory(categoryAdmin, 1, 1, "技术", "", &one)
+	assert.ErrorIs(t, err, service.ErrCategoryCycle)
+
+	// 不能移动到子孙分类下
+	three := uint(3)
+	_, err = categoryService.UpdateCategory(categoryAdmin, 1, 1, "技术", "", &three)
+	assert.ErrorIs(t, err, service.ErrCategoryCycle)
+	mockCategoryRepo.AssertNotCalled(t, "Update", mock.Anything)
+
+	// 移动到其他分支正常保存
+	mockCategoryRepo.On("Update", mock.MatchedBy(func(category *entity.Category) bool {
+		return category.ID == 3 && category.ParentID != nil && *category.ParentID == 1
+	})).Return(nil)
+	category, err := categoryService.UpdateCategory(categoryAdmin, 3, 1, "Go", "", &one)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), *category.ParentID)
+	mockCategoryRepo.AssertExpectations(t)
+}
+
+// 测试祖先分类在回收站中时修改父分类
+func TestUpdateCategoryParentWithTrashedAncestor(t *testing.T) {
+	mockCategoryRepo := new(MockCategoryRepository)
+	categoryService := service.NewCategoryService(mockCategoryRepo, policy.NewPolicy())
+
+	// 分类层级：4 -> 1 -> 2，分类1在回收站中，分类2作为顶级分类显示
+	one, two, four := uint(1), uint(2), uint(4)
+	notFound := fmt.Errorf("分类不存在: %d: %w", 1, utils.ErrNotFound)
+	mockCategoryRepo.On("GetByID", uint(1)).Return((*entity.Category)(nil), notFound)
+	mockCategoryRepo.On("GetTrashedByID", uint(1)).Return(&entity.Category{ID: 1, Name: "技术", ParentID: &four, Version: 1}, nil)
+	mockCategoryRepo.On("GetByID", uint(2)).Return(&entity.Category{ID: 2, Name: "后端", ParentID: &one, Version: 1}, nil)
+	mockCategoryRepo.On("GetByID", uint(3)).Return(&entity.Category{ID: 3, Name: "Go", Version: 1}, nil)
+	mockCategoryRepo.On("GetByID", uint(4)).Return(&entity.Category{ID: 4, Name: "编程", Version: 1}, nil)
+
+	// 新的父分类本身在回收站中时返回不存在
+	_, err := categoryService.UpdateCategory(categoryAdmin, 3, 1, "Go", "", &one)
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+
+	// 回收站中的祖先分类不影响移动
+	mockCategoryRepo.On("Update", mock.MatchedBy(func(category *entity.Category) bool {
+		return category.ID == 3 && category.ParentID != nil && *category.ParentID == 2
+	})).Return(nil)
+	category, err := categoryService.UpdateCategory(categoryAdmin, 3, 1, "Go", "", &two)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), *category.ParentID)
+
+	// 经过回收站中的祖先分类形成的环同样被拒绝
+	_, err = categoryService.UpdateCategory(categoryAdmin, 4, 1, "编程", "", &two)
+	assert.ErrorIs(t, err, service.ErrCategoryCycle)
+}
+
+// 测试移动为顶级分类
+func TestUpdateCategoryToRoot(t *testing.T) {
+	mockCategoryRepo := new(MockCategoryRepository)
+	categoryService := service.NewCategoryService(mockCategoryRepo, policy.NewPolicy())
+
+	one := uint(1)
+	mockCategoryRepo.On("GetByID", uint(2)).Return(&entity.Category{ID: 2, Name: "后端", ParentID: &one, Version: 1}, nil)
+	mockCategoryRepo.On("Update", mock.MatchedBy(func(category *entity.Category) bool {
+		return category.ID == 2 && category.ParentID == nil
+	})).Return(nil)
+
+	category, err := categoryService.UpdateCategory(categoryAdmin, 2, 1, "后端", "", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, category.ParentID)
+	mockCategoryRepo.AssertExpectations(t)
+}
+
+// 测试删除不存在或已在回收站中的分类
+func TestDeleteCategoryNotFound(t *testing.T) {
+	mockCategoryRepo := new(MockCategoryRepository)
+	categoryService := service.NewCategoryService(mockCategoryRepo, policy.NewPolicy())
+
+	mockCategoryRepo.On("GetByID", uint(9)).Return((*entity.Category)(nil), fmt.Errorf("分类不存在: %d: %w", 9, utils.ErrNotFound))
+	mockCategoryRepo.On("GetByID", uint(1)).Return(&entity.Category{ID: 1, Name: "技术", Version: 1}, nil)
+	mockCategoryRepo.On("Delete", uint(1)).Return(nil)
+
+	err := categoryService.DeleteCategory(categoryAdmin, 9)
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+	mockCategoryRepo.AssertNotCalled(t, "Delete", uint(9))
+
+	err = categoryService.DeleteCategory(categoryAdmin, 1)
+	assert.NoError(t, err)
+	mockCategoryRepo.AssertExpectations(t)
+}
+
+// 测试只有管理员可以恢复分类
+func TestRestoreCategory(t *testing.T) {
+	mockCategoryRepo := new(MockCategoryRepository)
+	categoryService := service.NewCategoryService(mockCategoryRepo, policy.NewPolicy())
+
+	mockCategoryRepo.On("GetTrashedByID", uint(1)).Return(&entity.Category{ID: 1, Name: "技术"}, nil)
+	mockCategoryRepo.On("Restore", uint(1)).Return(nil)
+
+	_, err := categoryService.RestoreCategory(editorActor, 1)
+	assert.ErrorIs(t, err, policy.ErrForbidden)
+	mockCategoryRepo.AssertNotCalled(t, "Restore", mock.Anything)
+
+	category, err := categoryService.RestoreCategory(categoryAdmin, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "技术", category.Name)
+}
@@ -0,0 +1,442 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
+	"blog/internal/domain/repository"
+	"blog/internal/domain/service"
+	"blog/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// 模拟评论仓储
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) Create(comment *entity.Comment) error {
+	args := m.Called(comment)
+	comment.ID = 10 // 模拟数据库自增ID
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) GetByID(id uint) (*entity.Comment, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entity.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetByIDs(ids []uint) ([]*entity.Comment, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]*entity.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetByPostID(postID uint, page *repository.CursorQuery) ([]*entity.Comment, bool, error) {
+	args := m.Called(postID, page)
+	return args.Get(0).([]*entity.Comment), args.Bool(1), args.Error(2)
+}
+
+func (m *MockCommentRepository) GetReplies(parentIDs []uint) ([]*entity.Comment, error) {
+	args := m.Called(parentIDs)
+	return args.Get(0).([]*entity.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) List(query *repository.CommentQuery) ([]*entity.Comment, int64, error) {
+	args := m.Called(query)
+	return args.Get(0).([]*entity.Comment), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCommentRepository) UpdateStatus(ids []uint, status entity.CommentStatus) (int64, error) {
+	args := m.Called(ids, status)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCommentRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) GetTrashedByID(id uint) (*entity.Comment, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entity.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) ListTrashed(query *repository.TrashQuery) ([]*entity.Comment, int64, error) {
+	args := m.Called(query)
+	return args.Get(0).([]*entity.Comment), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCommentRepository) Restore(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) PurgeTrashed(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// 模拟垃圾评论检测器
+type MockSpamChecker struct {
+	mock.Mock
+}
+
+func (m *MockSpamChecker) Check(input *repository.SpamCheckInput) (*repository.SpamCheckResult, error) {
+	args := m.Called(input)
+	result, _ := args.Get(0).(*repository.SpamCheckResult)
+	return result, args.Error(1)
+}
+
+func (m *MockSpamChecker) Learn(input *repository.SpamCheckInput, spam bool) error {
+	args := m.Called(input, spam)
+	return args.Error(0)
+}
+
+// 测试回复评论
+func TestCreateReply(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	mockPostRepo := new(MockPostRepository)
+	commentService := service.NewCommentService(mockCommentRepo, mockPostRepo, policy.NewPolicy(), service.CommentSettings{MaxDepth: 2})
+
+	post := &entity.Post{ID: 1, Status: entity.PostStatusPublished}
+	mockPostRepo.On("GetByID", uint(1)).Return(post, nil)
+
+	one := uint(1)
+	approved := entity.CommentStatusApproved
+	mockCommentRepo.On("GetByID", uint(1)).Return(&entity.Comment{ID: 1, PostID: 1, Status: approved}, nil)
+	mockCommentRepo.On("GetByID", uint(2)).Return(&entity.Comment{ID: 2, PostID: 1, ParentID: &one, Depth: 2, Status: approved}, nil)
+	mockCommentRepo.On("GetByID", uint(3)).Return(&entity.Comment{ID: 3, PostID: 9, Status: approved}, nil)
+	mockCommentRepo.On("GetByID", uint(4)).Return(&entity.Comment{ID: 4, PostID: 1, Status: entity.CommentStatusPending}, nil)
+	mockCommentRepo.On("Create", mock.MatchedBy(func(comment *entity.Comment) bool {
+		return comment.ParentID != nil && *comment.ParentID == 1 && comment.Depth == 1
+	})).Return(nil)
+
+	reply, err := commentService.CreateComment(readerActor, 1, &one, "回复内容", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reply.Depth)
+	assert.Equal(t, readerActor.Username, reply.Author)
+
+	// 父评论属于其他文章
+	three := uint(3)
+	_, err = commentService.CreateComment(readerActor, 1, &three, "回复内容", nil)
+	assert.ErrorIs(t, err, service.ErrInvalidParentComment)
+
+	// 不能回复未通过审核的评论
+	four := uint(4)
+	_, err = commentService.CreateComment(readerActor, 1, &four, "回复内容", nil)
+	assert.ErrorIs(t, err, service.ErrInvalidParentComment)
+
+	// 超过最大层级
+	two := uint(2)
+	_, err = commentService.CreateComment(readerActor, 1, &two, "回复内容", nil)
+	assert.ErrorIs(t, err, service.ErrCommentTooDeep)
+
+	mockCommentRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+// 测试获取评论树
+func TestGetCommentsByPostIDTree(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockPostRepo.On("GetByID", uint(1)).Return(&entity.Post{ID: 1, Status: entity.PostStatusPublished}, nil)
+	mockCommentRepo := new(MockCommentRepository)
+	commentService := service.NewCommentService(mockCommentRepo, mockPostRepo, policy.NewPolicy(), service.CommentSettings{MaxDepth: 5})
+
+	one, three := uint(1), uint(3)
+	roots := []*entity.Comment{{ID: 2, PostID: 1}, {ID: 1, PostID: 1}}
+	replies := []*entity.Comment{
+		{ID: 3, PostID: 1, ParentID: &one, Depth: 1},
+		{ID: 4, PostID: 1, ParentID: &three, Depth: 2},
+		{ID: 5, PostID: 1, ParentID: &one, Depth: 1},
+	}
+	page := &repository.CursorQuery{Limit: 10}
+	mockCommentRepo.On("GetByPostID", uint(1), page).Return(roots, true, nil)
+	mockCommentRepo.On("GetReplies", []uint{2, 1}).Return(replies, nil)
+
+	threads, hasMore, err := commentService.GetCommentsByPostID(nil, 1, page)
+	assert.NoError(t, err)
+	assert.True(t, hasMore)
+	assert.Len(t, threads, 2)
+	assert.Empty(t, threads[0].Replies)
+	assert.Len(t, threads[1].Replies, 2)
+	assert.Equal(t, uint(3), threads[1].Replies[0].Comment.ID)
+	assert.Equal(t, uint(4), threads[1].Replies[0].Replies[0].Comment.ID)
+	assert.Equal(t, uint(5), threads[1].Replies[1].Comment.ID)
+	mockCommentRepo.AssertExpectations(t)
+}
+
+// 测试草稿文章的评论对读者不可见
+func TestGetCommentsByPostIDHidesDraftPost(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockPostRepo.On("GetByID", uint(1)).Return(&entity.Post{ID: 1, Author: "author", Status: entity.PostStatusDraft}, nil)
+	mockCommentRepo := new(MockCommentRepository)
+	commentService := service.NewCommentService(mockCommentRepo, mockPostRepo, policy.NewPolicy(), service.CommentSettings{MaxDepth: 5})
+
+	reader := &policy.Actor{UserID: 2, Username: "reader", Role: entity.RoleReader}
+	_, _, err := commentService.GetCommentsByPostID(reader, 1, nil)
+	assert.True(t, errors.Is(err, utils.ErrNotFound))
+	_, _, err = commentService.GetCommentsByPostID(nil, 1, nil)
+	assert.True(t, errors.Is(err, utils.ErrNotFound))
+	mockCommentRepo.AssertNotCalled(t, "GetByPostID", mock.Anything, mock.Anything)
+
+	// 作者本人可以查看
+	author := &policy.Actor{UserID: 3, Username: "author", Role: entity.RoleAuthor}
+	mockCommentRepo.On("GetByPostID", uint(1), (*repository.CursorQuery)(nil)).Return([]*entity.Comment{}, false, nil)
+	threads, _, err := commentService.GetCommentsByPostID(author, 1, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, threads)
+}
+
+// 测试评论的初始审核状态
+func TestCreateCommentModeration(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockPostRepo.On("GetByID", uint(1)).Return(&entity.Post{ID: 1, Status: entity.PostStatusPublished}, nil)
+
+	mockCommentRepo := new(MockCommentRepository)
+	mockCommentRepo.On("Create", mock.Anything).Return(nil)
+	manual := service.NewCommentService(mockCommentRepo, mockPostRepo, policy.NewPolicy(), service.CommentSettings{})
+
+	// 未开启自动审核时普通用户的评论进入审核队列
+	comment, err := manual.CreateComment(readerActor, 1, nil, "评论内容", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.CommentStatusPending, comment.Status)
+
+	// 审核者的评论直接通过
+	comment, err = manual.CreateComment(editorActor, 1, nil, "评论内容", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.CommentStatusApproved, comment.Status)
+
+	auto := service.NewCommentService(mockCommentRepo, mockPostRepo, policy.NewPolicy(), service.CommentSettings{AutoApprove: true})
+	comment, err = auto.CreateComment(readerActor, 1, nil, "评论内容", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.CommentStatusApproved, comment.Status)
+}
+
+// 测试审核队列和批量审核
+func TestModerateComments(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	commentService := service.NewCommentService(mockCommentRepo, new(MockPostRepository), policy.NewPolicy(), service.CommentSettings{})
+
+	// 普通用户无权审核
+	_, _, err := commentService.ListComments(readerActor, &repository.CommentQuery{})
+	assert.ErrorIs(t, err, policy.ErrForbidden)
+	_, err = commentService.ModerateComments(authorActor, []uint{1}, entity.CommentStatusApproved)
+	assert.ErrorIs(t, err, policy.ErrForbidden)
+
+	// 未指定状态时返回待审核评论
+	pending := []*entity.Comment{{ID: 1, Status: entity.CommentStatusPending}}
+	mockCommentRepo.On("List", mock.MatchedBy(func(query *repository.CommentQuery) bool {
+		return query.Status == entity.CommentStatusPending
+	})).Return(pending, int64(1), nil)
+	comments, total, err := commentService.ListComments(editorActor, &repository.CommentQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, pending, comments)
+
+	_, err = commentService.ModerateComments(editorActor, []uint{1}, entity.CommentStatus("deleted"))
+	assert.ErrorIs(t, err, service.ErrInvalidCommentStatus)
+
+	mockCommentRepo.On("UpdateStatus", []uint{1, 2}, entity.CommentStatusSpam).Return(int64(2), nil)
+	updated, err := commentService.ModerateComments(editorActor, []uint{1, 2}, entity.CommentStatusSpam)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), updated)
+	mockCommentRepo.AssertExpectations(t)
+}
+
+// 测试未通过审核的评论的可见性
+func TestGetCommentByIDVisibility(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockPostRepo.On("GetByID", uint(1)).Return(&entity.Post{ID: 1, Status: entity.PostStatusPublished}, nil)
+	mockCommentRepo := new(MockCommentRepository)
+	commentService := service.NewCommentService(mockCommentRepo, mockPostRepo, policy.NewPolicy(), service.CommentSettings{})
+
+	pending := &entity.Comment{ID: 1, PostID: 1, Author: readerActor.Username, Status: entity.CommentStatusPending}
+	mockCommentRepo.On("GetByID", uint(1)).Return(pending, nil)
+
+	_, err := commentService.GetCommentByID(nil, 1)
+	assert.Error(t, err)
+	_, err = commentService.GetCommentByID(otherActor, 1)
+	assert.Error(t, err)
+
+	comment, err := commentService.GetCommentByID(readerActor, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, pending, comment)
+	_, err = commentService.GetCommentByID(editorActor, 1)
+	assert.NoError(t, err)
+}
+
+// 测试文章撤回发布后，其下已通过审核的评论对读者不可见
+func TestGetCommentByIDHidesUnpublishedPost(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockPostRepo.On("GetByID", uint(1)).Return(&entity.Post{ID: 1, Author: "author", Status: entity.PostStatusDraft}, nil)
+	mockCommentRepo := new(MockCommentRepository)
+	commentService := service.NewCommentService(mockCommentRepo, mockPostRepo, policy.NewPolicy(), service.CommentSettings{})
+
+	approved := &entity.Comment{ID: 2, PostID: 1, Author: readerActor.Username, Status: entity.CommentStatusApproved}
+	mockCommentRepo.On("GetByID", uint(2)).Return(approved, nil)
+
+	_, err := commentService.GetCommentByID(readerActor, 2)
+	assert.True(t, errors.Is(err, utils.ErrNotFound))
+	_, err = commentService.GetCommentByID(nil, 2)
+	assert.True(t, errors.Is(err, utils.ErrNotFound))
+
+	comment, err := commentService.GetCommentByID(editorActor, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, approved, comment)
+}
+
+// 测试创建评论时的垃圾评论检测
+func TestCreateCommentSpamCheck(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockPostRepo.On("GetByID", uint(1)).Return(&entity.Post{ID: 1, Status: entity.PostStatusPublished}, nil)
+	mockCommentRepo := new(MockCommentRepository)
+	mockCommentRepo.On("Create", mock.Anything).Return(nil)
+	checker := new(MockSpamChecker)
+	commentService := service.NewCommentService(mockCommentRepo, mockPostRepo, policy.NewPolicy(), service.CommentSettings{AutoApprove: true})
+	commentService.SetSpamChecker(checker)
+
+	client := &service.CommentClient{IP: "203.0.113.7", UserAgent: "Mozilla/5.0", Honeypot: "http://spam.example"}
+	checker.On("Check", mock.MatchedBy(func(input *repository.SpamCheckInput) bool {
+		return input.Comment.Content == "垃圾广告" && input.Honeypot == client.Honeypot && input.Post.ID == 1
+	})).Return(&repository.SpamCheckResult{Spam: true, Reason: "蜜罐字段被填写"}, nil)
+	checker.On("Check", mock.MatchedBy(func(input *repository.SpamCheckInput) bool {
+		return input.Comment.Content == "正常评论"
+	})).Return(&repository.SpamCheckResult{}, nil)
+	checker.On("Check", mock.MatchedBy(func(input *repository.SpamCheckInput) bool {
+		return input.Comment.Content == "检测失败"
+	})).Return(nil, errors.New("检测服务不可用"))
+	checker.On("Check", mock.MatchedBy(func(input *repository.SpamCheckInput) bool {
+		return input.Comment.Content == "频繁评论"
+	})).Return(nil, repository.ErrCommentRateLimited)
+
+	comment, err := commentService.CreateComment(readerActor, 1, nil, "垃圾广告", client)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.CommentStatusSpam, comment.Status)
+	assert.Equal(t, "203.0.113.7", comment.AuthorIP)
+	assert.Equal(t, "Mozilla/5.0", comment.UserAgent)
+
+	comment, err = commentService.CreateComment(readerActor, 1, nil, "正常评论", client)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.CommentStatusApproved, comment.Status)
+
+	// 检测器出错时不阻塞评论
+	comment, err = commentService.CreateComment(readerActor, 1, nil, "检测失败", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.CommentStatusApproved, comment.Status)
+
+	_, err = commentService.CreateComment(readerActor, 1, nil, "频繁评论", nil)
+	assert.ErrorIs(t, err, repository.ErrCommentRateLimited)
+
+	// 审核者的评论不做检测
+	_, err = commentService.CreateComment(editorActor, 1, nil, "垃圾广告", client)
+	assert.NoError(t, err)
+	checker.AssertNumberOfCalls(t, "Check", 4)
+	mockCommentRepo.AssertNumberOfCalls(t, "Create", 4)
+}
+
+// 测试审核结果用于训练垃圾评论检测器
+func TestModerateCommentsTrainsSpamChecker(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	checker := new(MockSpamChecker)
+	commentService := service.NewCommentService(mockCommentRepo, new(MockPostRepository), policy.NewPolicy(), service.CommentSettings{})
+	commentService.SetSpamChecker(checker)
+
+	// 状态未变化的评论不重复学习
+	comments := []*entity.Comment{
+		{ID: 1, Content: "广告", Status: entity.CommentStatusPending},
+		{ID: 2, Content: "广告", Status: entity.CommentStatusSpam},
+	}
+	mockCommentRepo.On("GetByIDs", []uint{1, 2}).Return(comments, nil)
+	mockCommentRepo.On("UpdateStatus", []uint{1, 2}, entity.CommentStatusSpam).Return(int64(1), nil)
+	checker.On("Learn", mock.MatchedBy(func(input *repository.SpamCheckInput) bool {
+		return input.Comment.ID == 1
+	}), true).Return(nil)
+
+	// 学习在后台进行
+	updated, err := commentService.ModerateComments(editorActor, []uint{1, 2}, entity.CommentStatusSpam)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), updated)
+	commentService.WaitLearning()
+	checker.AssertNumberOfCalls(t, "Learn", 1)
+
+	// 标记为拒绝时不参与训练
+	mockCommentRepo.On("UpdateStatus", []uint{3}, entity.CommentStatusRejected).Return(int64(1), nil)
+	_, err = commentService.ModerateComments(editorActor, []uint{3}, entity.CommentStatusRejected)
+	assert.NoError(t, err)
+	commentService.WaitLearning()
+	checker.AssertNumberOfCalls(t, "Learn", 1)
+	mockCommentRepo.AssertNotCalled(t, "GetByIDs", []uint{3})
+}
+
+// 测试检测器学习失败不影响审核结果，也不阻塞审核请求
+func TestModerateCommentsLearnsInBackground(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	checker := new(MockSpamChecker)
+	commentService := service.NewCommentService(mockCommentRepo, new(MockPostRepository), policy.NewPolicy(), service.CommentSettings{})
+	commentService.SetSpamChecker(checker)
+
+	comments := []*entity.Comment{
+		{ID: 1, Content: "好文章", Status: entity.CommentStatusPending},
+		{ID: 2, Content: "写得好", Status: entity.CommentStatusPending},
+	}
+	mockCommentRepo.On("GetByIDs", []uint{1, 2}).Return(comments, nil)
+	mockCommentRepo.On("UpdateStatus", []uint{1, 2}, entity.CommentStatusApproved).Return(int64(2), nil)
+	release := make(chan struct{})
+	checker.On("Learn", mock.Anything, false).Run(func(mock.Arguments) { <-release }).Return(errors.New("检测服务超时"))
+
+	updated, err := commentService.ModerateComments(editorActor, []uint{1, 2}, entity.CommentStatusApproved)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), updated)
+
+	close(release)
+	commentService.WaitLearning()
+	checker.AssertNumberOfCalls(t, "Learn", 2)
+}
+
+// 测试用历史审核结果训练垃圾评论检测器
+func TestTrainSpamChecker(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	checker := new(MockSpamChecker)
+	commentService := service.NewCommentService(mockCommentRepo, new(MockPostRepository), policy.NewPolicy(), service.CommentSettings{})
+
+	spamComments := []*entity.Comment{{ID: 1, Content: "广告一"}, {ID: 2, Content: "广告二"}, {ID: 3, Content: "广告三"}}
+	hamComments := []*entity.Comment{{ID: 4, Content: "好文章"}}
+	mockCommentRepo.On("List", mock.MatchedBy(func(query *repository.CommentQuery) bool {
+		return query.Status == entity.CommentStatusSpam && query.Page == 1
+	})).Return(spamComments, int64(3), nil)
+	mockCommentRepo.On("List", mock.MatchedBy(func(query *repository.CommentQuery) bool {
+		return query.Status == entity.CommentStatusApproved && query.Page == 1
+	})).Return(hamComments, int64(1), nil)
+	checker.On("Learn", mock.Anything, true).Return(nil)
+	checker.On("Learn", mock.Anything, false).Return(nil)
+
+	count, err := commentService.TrainSpamChecker(checker, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	checker.AssertNumberOfCalls(t, "Learn", 3)
+}
+
+// 测试恢复评论需要文章作者或编辑权限
+func TestRestoreComment(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	mockPostRepo := new(MockPostRepository)
+	commentService := service.NewCommentService(mockCommentRepo, mockPostRepo, policy.NewPolicy(), service.CommentSettings{})
+
+	deletedAt := time.Now()
+	mockCommentRepo.On("GetTrashedByID", uint(1)).Return(&entity.Comment{ID: 1, PostID: 1, DeletedAt: &deletedAt}, nil)
+	mockPostRepo.On("GetByID", uint(1)).Return(&entity.Post{ID: 1, Author: authorActor.Username}, nil)
+	mockCommentRepo.On("Restore", uint(1)).Return(nil)
+
+	_, err := commentService.RestoreComment(readerActor, 1)
+	assert.ErrorIs(t, err, policy.ErrForbidden)
+	mockCommentRepo.AssertNotCalled(t, "Restore", mock.Anything)
+
+	comment, err := commentService.RestoreComment(authorActor, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, comment.DeletedAt)
+	mockCommentRepo.AssertExpectations(t)
+}
@@ -0,0 +1,95 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
+	"blog/internal/domain/repository"
+	"blog/internal/domain/service"
+	"blog/internal/infrastructure/persistence"
+	"blog/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 使用内存存储库组装的领域服务，适合验证跨多个存储库的完整流程，无需逐个方法设置mock
+
+// memoryServices 共享同一份内存数据的领域服务
+type memoryServices struct {
+	posts    *service.PostService
+	comments *service.CommentService
+}
+
+func newMemoryServices() *memoryServices {
+	store := persistence.NewMemoryStore()
+	postRepo := persistence.NewMemoryPostRepository(store)
+	accessPolicy := policy.NewPolicy()
+	return &memoryServices{
+		posts: service.NewPostService(postRepo, persistence.NewMemoryCategoryRepository(store), persistence.NewMemorySlugRedirectRepository(store),
+			persistence.NewMemoryPostRevisionRepository(store), accessPolicy),
+		comments: service.NewCommentService(persistence.NewMemoryCommentRepository(store), postRepo, accessPolicy, service.CommentSettings{MaxDepth: 2}),
+	}
+}
+
+func TestMemoryRepositories_PostLifecycle(t *testing.T) {
+	services := newMemoryServices()
+	editor := &policy.Actor{UserID: 1, Username: "editor", Role: entity.RoleEditor}
+
+	post, err := services.posts.CreatePost(editor, "Go并发编程", "内容", "", "", entity.PostStatusPublished, nil)
+	require.NoError(t, err)
+
+	// 修改slug后旧slug重定向到文章，并记录修订版本
+	post, err = services.posts.UpdatePost(editor, post.ID, post.Version, "Go并发编程实战", "新的内容", "", "go-concurrency")
+	require.NoError(t, err)
+	revisions, err := services.posts.ListRevisions(editor, post.ID)
+	require.NoError(t, err)
+	assert.Len(t, revisions, 2)
+	redirects, err := services.posts.ListSlugRedirects(editor, post.ID)
+	require.NoError(t, err)
+	assert.Len(t, redirects, 1)
+
+	// 使用过期的版本号更新时返回版本冲突
+	_, err = services.posts.UpdatePost(editor, post.ID, post.Version-1, "标题", "内容", "", "")
+	assert.True(t, errors.Is(err, repository.ErrVersionConflict))
+
+	// 移入回收站后不可见，恢复后重新可见
+	require.NoError(t, services.posts.DeletePost(editor, post.ID))
+	_, err = services.posts.GetPostBySlug(nil, "go-concurrency")
+	assert.True(t, errors.Is(err, utils.ErrNotFound))
+	_, err = services.posts.RestorePost(editor, post.ID)
+	require.NoError(t, err)
+	found, err := services.posts.GetPostBySlug(nil, "go-concurrency")
+	require.NoError(t, err)
+	assert.Equal(t, "Go并发编程实战", found.Title)
+}
+
+func TestMemoryRepositories_CommentThread(t *testing.T) {
+	services := newMemoryServices()
+	editor := &policy.Actor{UserID: 1, Username: "editor", Role: entity.RoleEditor}
+	reader := &policy.Actor{UserID: 2, Username: "reader", Role: entity.RoleReader}
+
+	post, err := services.posts.CreatePost(editor, "Go并发编程", "内容", "", "", entity.PostStatusPublished, nil)
+	require.NoError(t, err)
+
+	root, err := services.comments.CreateComment(editor, post.ID, nil, "置顶评论", nil)
+	require.NoError(t, err)
+	reply, err := services.comments.CreateComment(reader, post.ID, &root.ID, "读者回复", nil)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CommentStatusPending, reply.Status)
+
+	// 回复审核通过前不出现在评论树中
+	nodes, _, err := services.comments.GetCommentsByPostID(reader, post.ID, nil)
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	assert.Empty(t, nodes[0].Replies)
+
+	_, err = services.comments.ModerateComments(editor, []uint{reply.ID}, entity.CommentStatusApproved)
+	require.NoError(t, err)
+	nodes, _, err = services.comments.GetCommentsByPostID(reader, post.ID, nil)
+	require.NoError(t, err)
+	require.Len(t, nodes[0].Replies, 1)
+	assert.Equal(t, reply.ID, nodes[0].Replies[0].Comment.ID)
+}
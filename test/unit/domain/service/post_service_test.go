@@ -2,11 +2,15 @@ package service_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
+	"blog/internal/domain/repository"
 	"blog/internal/domain/service"
+	"blog/pkg/utils"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -28,6 +32,16 @@ func (m *MockPostRepository) GetByID(id uint) (*entity.Post, error) {
 	return args.Get(0).(*entity.Post), args.Error(1)
 }
 
+func (m *MockPostRepository) GetBySlug(slug string) (*entity.Post, error) {
+	args := m.Called(slug)
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) SlugExists(slug string, excludeID uint) (bool, error) {
+	args := m.Called(slug, excludeID)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockPostRepository) GetAll() ([]*entity.Post, error) {
 	args := m.Called()
 	return args.Get(0).([]*entity.Post), args.Error(1)
@@ -38,16 +52,116 @@ func (m *MockPostRepository) Update(post *entity.Post) error {
 	return args.Error(0)
 }
 
+func (m *MockPostRepository) IncrementViewCount(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 func (m *MockPostRepository) Delete(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockPostRepository) GetByCategory(categoryID uint) ([]*entity.Post, error) {
-	args := m.Called(categoryID)
+func (m *MockPostRepository) GetTrashedByID(id uint) (*entity.Post, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) ListTrashed(query *repository.TrashQuery) ([]*entity.Post, int64, error) {
+	args := m.Called(query)
+	return args.Get(0).([]*entity.Post), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPostRepository) Restore(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) PurgeTrashed(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPostRepository) List(query *repository.PostQuery) ([]*entity.Post, int64, error) {
+	args := m.Called(query)
+	return args.Get(0).([]*entity.Post), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPostRepository) ListByCursor(query *repository.PostQuery, page *repository.CursorQuery) ([]*entity.Post, bool, error) {
+	args := m.Called(query, page)
+	return args.Get(0).([]*entity.Post), args.Bool(1), args.Error(2)
+}
+
+func (m *MockPostRepository) Search(query *repository.PostSearchQuery) ([]*repository.PostSearchHit, int64, error) {
+	args := m.Called(query)
+	return args.Get(0).([]*repository.PostSearchHit), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPostRepository) GetDueScheduled(now time.Time) ([]*entity.Post, error) {
+	args := m.Called(now)
 	return args.Get(0).([]*entity.Post), args.Error(1)
 }
 
+// 模拟搜索索引
+type MockSearchIndex struct {
+	mock.Mock
+}
+
+func (m *MockSearchIndex) Index(doc *repository.SearchDocument) error {
+	args := m.Called(doc)
+	return args.Error(0)
+}
+
+func (m *MockSearchIndex) Remove(postID uint) error {
+	args := m.Called(postID)
+	return args.Error(0)
+}
+
+func (m *MockSearchIndex) Search(query *repository.PostSearchQuery) ([]*repository.PostSearchHit, int64, error) {
+	args := m.Called(query)
+	return args.Get(0).([]*repository.PostSearchHit), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockSearchIndex) Rebuild(docs []*repository.SearchDocument) error {
+	args := m.Called(docs)
+	return args.Error(0)
+}
+
+// 模拟slug重定向仓储
+type MockSlugRedirectRepository struct {
+	mock.Mock
+}
+
+func (m *MockSlugRedirectRepository) Save(redirect *entity.SlugRedirect) error {
+	args := m.Called(redirect)
+	return args.Error(0)
+}
+
+func (m *MockSlugRedirectRepository) GetByID(id uint) (*entity.SlugRedirect, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entity.SlugRedirect), args.Error(1)
+}
+
+func (m *MockSlugRedirectRepository) GetBySlug(oldSlug string) (*entity.SlugRedirect, error) {
+	args := m.Called(oldSlug)
+	return args.Get(0).(*entity.SlugRedirect), args.Error(1)
+}
+
+func (m *MockSlugRedirectRepository) List(postID uint) ([]*entity.SlugRedirect, error) {
+	args := m.Called(postID)
+	return args.Get(0).([]*entity.SlugRedirect), args.Error(1)
+}
+
+func (m *MockSlugRedirectRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockSlugRedirectRepository) DeleteBySlug(oldSlug string) error {
+	args := m.Called(oldSlug)
+	return args.Error(0)
+}
+
 // 模拟分类仓储
 type MockCategoryRepository struct {
 	mock.Mock
@@ -78,6 +192,26 @@ func (m *MockCategoryRepository) Delete(id uint) error {
 	return args.Error(0)
 }
 
+func (m *MockCategoryRepository) GetTrashedByID(id uint) (*entity.Category, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) ListTrashed(query *repository.TrashQuery) ([]*entity.Category, int64, error) {
+	args := m.Called(query)
+	return args.Get(0).([]*entity.Category), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCategoryRepository) Restore(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) PurgeTrashed(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockCategoryRepository) AddPostToCategory(postID, categoryID uint) error {
 	args := m.Called(postID, categoryID)
 	return args.Error(0)
@@ -93,6 +227,41 @@ func (m *MockCategoryRepository) GetCategoriesByPostID(postID uint) ([]*entity.C
 	return args.Get(0).([]*entity.Category), args.Error(1)
 }
 
+// 测试用的操作者
+var (
+	authorActor = &policy.Actor{UserID: 1, Username: "测试作者", Role: entity.RoleAuthor}
+	otherActor  = &policy.Actor{UserID: 2, Username: "其他作者", Role: entity.RoleAuthor}
+	editorActor = &policy.Actor{UserID: 3, Username: "编辑", Role: entity.RoleEditor}
+	readerActor = &policy.Actor{UserID: 4, Username: "读者", Role: entity.RoleReader}
+)
+
+// 模拟文章修订版本仓储
+type MockPostRevisionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostRevisionRepository) Create(revision *entity.PostRevision) error {
+	args := m.Called(revision)
+	return args.Error(0)
+}
+
+func (m *MockPostRevisionRepository) GetByRevision(postID uint, revision int) (*entity.PostRevision, error) {
+	args := m.Called(postID, revision)
+	return args.Get(0).(*entity.PostRevision), args.Error(1)
+}
+
+func (m *MockPostRevisionRepository) ListByPostID(postID uint) ([]*entity.PostRevision, error) {
+	args := m.Called(postID)
+	return args.Get(0).([]*entity.PostRevision), args.Error(1)
+}
+
+// newRevisionRepo 创建接受任意修订版本保存的模拟仓储
+func newRevisionRepo() *MockPostRevisionRepository {
+	repo := new(MockPostRevisionRepository)
+	repo.On("Create", mock.Anything).Return(nil).Maybe()
+	return repo
+}
+
 // 测试创建文章
 func TestCreatePost(t *testing.T) {
 	mockPostRepo := new(MockPostRepository)
@@ -100,18 +269,23 @@ func TestCreatePost(t *testing.T) {
 
 	title := "测试标题"
 	content := "测试内容"
-	author := "测试作者"
+	author := authorActor.Username
+	titleURL := "https://example.com/cover.jpg"
 
+	mockPostRepo.On("SlugExists", "ce-shi-biao-ti", uint(0)).Return(false, nil)
 	mockPostRepo.On("Create", mock.AnythingOfType("*entity.Post")).Return(nil)
 
-	postService := service.NewPostService(mockPostRepo, mockCategoryRepo)
-	post, err := postService.CreatePost(title, content, author)
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+	post, err := postService.CreatePost(authorActor, title, content, titleURL, "", "", nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, post)
 	assert.Equal(t, title, post.Title)
 	assert.Equal(t, content, post.Content)
 	assert.Equal(t, author, post.Author)
+	assert.Equal(t, titleURL, post.TitleURL)
+	assert.Equal(t, "ce-shi-biao-ti", post.Slug)
+	assert.Equal(t, entity.PostStatusDraft, post.Status)
 	mockPostRepo.AssertExpectations(t)
 }
 
@@ -126,14 +300,15 @@ func TestGetPostByID(t *testing.T) {
 		Title:     "测试标题",
 		Content:   "测试内容",
 		Author:    "测试作者",
+		Status:    entity.PostStatusPublished,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 
 	mockPostRepo.On("GetByID", uint(1)).Return(expectedPost, nil)
 
-	postService := service.NewPostService(mockPostRepo, mockCategoryRepo)
-	post, err := postService.GetPostByID(1)
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+	post, err := postService.GetPostByID(nil, 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedPost, post)
@@ -145,11 +320,11 @@ func TestGetPostByIDNotFound(t *testing.T) {
 	mockPostRepo := new(MockPostRepository)
 	mockCategoryRepo := new(MockCategoryRepository)
 
-	expectedError := errors.New("文章不存在")
+	expectedError := fmt.Errorf("文章不存在: 999: %w", utils.ErrNotFound)
 	mockPostRepo.On("GetByID", uint(999)).Return((*entity.Post)(nil), expectedError)
 
-	postService := service.NewPostService(mockPostRepo, mockCategoryRepo)
-	post, err := postService.GetPostByID(999)
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+	post, err := postService.GetPostByID(nil, 999)
 
 	assert.Error(t, err)
 	assert.Nil(t, post)
@@ -168,6 +343,8 @@ func TestUpdatePost(t *testing.T) {
 		Title:     "旧标题",
 		Content:   "旧内容",
 		Author:    "测试作者",
+		Slug:      "jiu-biao-ti",
+		Version:   1,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -178,8 +355,8 @@ func TestUpdatePost(t *testing.T) {
 	mockPostRepo.On("GetByID", uint(1)).Return(existingPost, nil)
 	mockPostRepo.On("Update", mock.AnythingOfType("*entity.Post")).Return(nil)
 
-	postService := service.NewPostService(mockPostRepo, mockCategoryRepo)
-	post, err := postService.UpdatePost(1, newTitle, newContent)
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+	post, err := postService.UpdatePost(authorActor, 1, 1, newTitle, newContent, "", "")
 
 	assert.NoError(t, err)
 	assert.Equal(t, newTitle, post.Title)
@@ -187,16 +364,432 @@ func TestUpdatePost(t *testing.T) {
 	mockPostRepo.AssertExpectations(t)
 }
 
+// 测试版本号不一致时拒绝修改
+func TestUpdatePostVersionConflict(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	postService := service.NewPostService(mockPostRepo, new(MockCategoryRepository), new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+
+	post := &entity.Post{ID: 1, Title: "标题", Content: "内容", Author: authorActor.Username, Slug: "biao-ti", Version: 3}
+	mockPostRepo.On("GetByID", uint(1)).Return(post, nil)
+
+	_, err := postService.UpdatePost(authorActor, 1, 2, "新标题", "新内容", "", "")
+	assert.ErrorIs(t, err, repository.ErrVersionConflict)
+	mockPostRepo.AssertNotCalled(t, "Update", mock.Anything)
+
+	// 读取与保存之间被他人修改时，仓储返回的冲突错误原样向上传递
+	conflict := fmt.Errorf("更新文章 1 失败: %w", repository.ErrVersionConflict)
+	mockPostRepo.On("Update", post).Return(conflict)
+	_, err = postService.UpdatePost(authorActor, 1, 3, "新标题", "新内容", "", "")
+	assert.ErrorIs(t, err, repository.ErrVersionConflict)
+}
+
 // 测试删除文章
 func TestDeletePost(t *testing.T) {
 	mockPostRepo := new(MockPostRepository)
 	mockCategoryRepo := new(MockCategoryRepository)
 
+	mockPostRepo.On("GetByID", uint(1)).Return(&entity.Post{ID: 1, Author: authorActor.Username}, nil)
+	mockPostRepo.On("Delete", uint(1)).Return(nil)
+
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+	err := postService.DeletePost(authorActor, 1)
+
+	assert.NoError(t, err)
+	mockPostRepo.AssertExpectations(t)
+}
+
+// 测试读者不能发表文章
+func TestCreatePostForbiddenForReader(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+	post, err := postService.CreatePost(readerActor, "标题", "内容", "", "", "", nil)
+
+	assert.ErrorIs(t, err, policy.ErrForbidden)
+	assert.Nil(t, post)
+	mockPostRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// 测试作者不能修改他人的文章，编辑可以
+func TestUpdatePostOwnership(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+
+	existingPost := &entity.Post{ID: 1, Title: "旧标题", Content: "旧内容", Author: authorActor.Username, Slug: "jiu-biao-ti", Version: 1}
+	mockPostRepo.On("GetByID", uint(1)).Return(existingPost, nil)
+	mockPostRepo.On("Update", mock.AnythingOfType("*entity.Post")).Return(nil)
+
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+
+	_, err := postService.UpdatePost(otherActor, 1, 1, "新标题", "新内容", "", "")
+	assert.ErrorIs(t, err, policy.ErrForbidden)
+	mockPostRepo.AssertNotCalled(t, "Update", mock.Anything)
+
+	post, err := postService.UpdatePost(editorActor, 1, 1, "新标题", "新内容", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "新标题", post.Title)
+	assert.Equal(t, authorActor.Username, post.Author)
+}
+
+// 测试草稿仅对作者本人和编辑可见
+func TestGetDraftPostVisibility(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+
+	draft := entity.NewPost("草稿标题", "草稿内容", authorActor.Username, "")
+	draft.ID = 1
+	mockPostRepo.On("GetByID", uint(1)).Return(draft, nil)
+
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+
+	_, err := postService.GetPostByID(nil, 1)
+	assert.Error(t, err)
+	_, err = postService.GetPostByID(otherActor, 1)
+	assert.Error(t, err)
+
+	post, err := postService.GetPostByID(authorActor, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, draft, post)
+	_, err = postService.GetPostByID(editorActor, 1)
+	assert.NoError(t, err)
+}
+
+// 测试公开列表只返回已发布文章，作者查询草稿时只能看到自己的文章
+func TestListPostsScopesByStatus(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+
+	mockPostRepo.On("List", mock.AnythingOfType("*repository.PostQuery")).Return([]*entity.Post{}, int64(0), nil)
+
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+
+	publicQuery := &repository.PostQuery{}
+	_, _, err := postService.ListPosts(nil, publicQuery)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.PostStatusPublished, publicQuery.Status)
+
+	draftQuery := &repository.PostQuery{Status: entity.PostStatusDraft}
+	_, _, err = postService.ListPosts(authorActor, draftQuery)
+	assert.NoError(t, err)
+	assert.Equal(t, authorActor.Username, draftQuery.Author)
+
+	_, _, err = postService.ListPosts(nil, &repository.PostQuery{Status: entity.PostStatusDraft})
+	assert.ErrorIs(t, err, policy.ErrForbidden)
+}
+
+func TestSearchPosts(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+
+	hits := []*repository.PostSearchHit{{Post: &entity.Post{ID: 1, Title: "Go语言入门"}, Score: 1.5}}
+	mockPostRepo.On("Search", mock.AnythingOfType("*repository.PostSearchQuery")).Return(hits, int64(1), nil)
+
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+
+	query := &repository.PostSearchQuery{Keyword: "  Go语言 "}
+	result, total, err := postService.SearchPosts(nil, query)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "Go语言", query.Keyword)
+	assert.Equal(t, entity.PostStatusPublished, query.Status)
+
+	_, _, err = postService.SearchPosts(nil, &repository.PostSearchQuery{Keyword: "   "})
+	assert.ErrorIs(t, err, service.ErrEmptyKeyword)
+}
+
+func TestSearchIndexSync(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockIndex := new(MockSearchIndex)
+
+	post := &entity.Post{ID: 1, Title: "原标题", Author: authorActor.Username, Slug: "yuan-biao-ti", Version: 1}
+	mockPostRepo.On("GetByID", uint(1)).Return(post, nil)
+	mockPostRepo.On("Update", post).Return(nil)
 	mockPostRepo.On("Delete", uint(1)).Return(nil)
+	mockCategoryRepo.On("GetCategoriesByPostID", uint(1)).Return([]*entity.Category{{ID: 2}}, nil)
+	mockIndex.On("Index", mock.MatchedBy(func(doc *repository.SearchDocument) bool {
+		return doc.Post.Title == "新标题" && len(doc.CategoryIDs) == 1 && doc.CategoryIDs[0] == 2
+	})).Return(nil)
+	mockIndex.On("Remove", uint(1)).Return(nil)
+
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+	postService.SetSearchIndex(mockIndex)
+
+	_, err := postService.UpdatePost(authorActor, 1, 1, "新标题", "新内容", "", "")
+	assert.NoError(t, err)
+	assert.NoError(t, postService.DeletePost(authorActor, 1))
+
+	mockIndex.AssertExpectations(t)
+}
+
+func TestRebuildSearchIndex(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+
+	adminActor := &policy.Actor{UserID: 9, Username: "admin", Role: entity.RoleAdmin}
+	_, err := postService.RebuildSearchIndex(adminActor)
+	assert.ErrorIs(t, err, service.ErrSearchIndexDisabled)
+
+	mockIndex := new(MockSearchIndex)
+	postService.SetSearchIndex(mockIndex)
+
+	_, err = postService.RebuildSearchIndex(editorActor)
+	assert.ErrorIs(t, err, policy.ErrForbidden)
+
+	posts := []*entity.Post{{ID: 1}, {ID: 2}}
+	mockPostRepo.On("GetAll").Return(posts, nil)
+	mockCategoryRepo.On("GetCategoriesByPostID", mock.Anything).Return([]*entity.Category{}, nil)
+	mockIndex.On("Rebuild", mock.AnythingOfType("[]*repository.SearchDocument")).Return(nil)
+
+	count, err := postService.RebuildSearchIndex(adminActor)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	mockIndex.AssertExpectations(t)
+}
+
+func TestCreatePostSlugDeduplication(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+
+	mockPostRepo.On("SlugExists", "go-yu-yan", uint(0)).Return(true, nil)
+	mockPostRepo.On("SlugExists", "go-yu-yan-2", uint(0)).Return(true, nil)
+	mockPostRepo.On("SlugExists", "go-yu-yan-3", uint(0)).Return(false, nil)
+	mockPostRepo.On("SlugExists", "custom", uint(0)).Return(true, nil)
+	mockPostRepo.On("Create", mock.AnythingOfType("*entity.Post")).Return(nil)
+
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+
+	post, err := postService.CreatePost(authorActor, "Go 语言", "内容", "", "", "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "go-yu-yan-3", post.Slug)
+
+	// 指定的slug被占用时不自动追加后缀
+	_, err = postService.CreatePost(authorActor, "标题", "内容", "", "Custom", "", nil)
+	assert.ErrorIs(t, err, service.ErrSlugTaken)
+
+	_, err = postService.CreatePost(authorActor, "标题", "内容", "", "!!!", "", nil)
+	assert.ErrorIs(t, err, service.ErrInvalidSlug)
+}
+
+func TestGetPostBySlug(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+
+	published := &entity.Post{ID: 1, Slug: "hello", Author: authorActor.Username, Status: entity.PostStatusPublished}
+	draft := &entity.Post{ID: 2, Slug: "draft", Author: authorActor.Username, Status: entity.PostStatusDraft}
+	mockPostRepo.On("GetBySlug", "hello").Return(published, nil)
+	mockPostRepo.On("GetBySlug", "draft").Return(draft, nil)
+
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+
+	post, err := postService.GetPostBySlug(nil, "hello")
+	assert.NoError(t, err)
+	assert.Equal(t, published, post)
+
+	_, err = postService.GetPostBySlug(nil, "draft")
+	assert.Error(t, err)
+	post, err = postService.GetPostBySlug(authorActor, "draft")
+	assert.NoError(t, err)
+	assert.Equal(t, draft, post)
+}
+
+func TestUpdatePostSlugRecordsRedirect(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockRedirectRepo := new(MockSlugRedirectRepository)
+
+	post := &entity.Post{ID: 1, Title: "旧标题", Author: authorActor.Username, Slug: "old-slug", Version: 1}
+	mockPostRepo.On("GetByID", uint(1)).Return(post, nil)
+	mockPostRepo.On("SlugExists", "new-slug", uint(1)).Return(false, nil)
+	mockPostRepo.On("Update", post).Return(nil)
+	mockRedirectRepo.On("DeleteBySlug", "new-slug").Return(nil)
+	mockRedirectRepo.On("Save", mock.MatchedBy(func(redirect *entity.SlugRedirect) bool {
+		return redirect.OldSlug == "old-slug" && redirect.PostID == 1
+	})).Return(nil)
+
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, mockRedirectRepo, newRevisionRepo(), policy.NewPolicy())
+	updated, err := postService.UpdatePost(authorActor, 1, 1, "新标题", "内容", "", "New Slug")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new-slug", updated.Slug)
+	mockRedirectRepo.AssertExpectations(t)
+}
+
+func TestGetPostBySlugRedirect(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockRedirectRepo := new(MockSlugRedirectRepository)
+
+	notFound := fmt.Errorf("文章不存在: old-slug: %w", utils.ErrNotFound)
+	mockPostRepo.On("GetBySlug", "old-slug").Return((*entity.Post)(nil), notFound)
+	mockPostRepo.On("GetBySlug", "missing").Return((*entity.Post)(nil), notFound)
+	mockRedirectRepo.On("GetBySlug", "old-slug").Return(&entity.SlugRedirect{ID: 1, OldSlug: "old-slug", PostID: 1}, nil)
+	mockRedirectRepo.On("GetBySlug", "missing").Return((*entity.SlugRedirect)(nil), fmt.Errorf("slug重定向不存在: missing: %w", utils.ErrNotFound))
+	mockPostRepo.On("GetByID", uint(1)).Return(&entity.Post{ID: 1, Slug: "new-slug", Status: entity.PostStatusPublished}, nil)
+
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, mockRedirectRepo, newRevisionRepo(), policy.NewPolicy())
+
+	_, err := postService.GetPostBySlug(nil, "old-slug")
+	var moved *service.SlugMovedError
+	assert.ErrorAs(t, err, &moved)
+	assert.Equal(t, "new-slug", moved.Slug)
+
+	_, err = postService.GetPostBySlug(nil, "missing")
+	assert.Equal(t, notFound, err)
+
+	// 数据库异常时不能当作文章不存在继续查找重定向
+	dbErr := errors.New("连接失败")
+	mockPostRepo.On("GetBySlug", "broken").Return((*entity.Post)(nil), dbErr)
+	_, err = postService.GetPostBySlug(nil, "broken")
+	assert.Equal(t, dbErr, err)
+	mockRedirectRepo.AssertNotCalled(t, "GetBySlug", "broken")
+}
+
+func TestSlugRedirectManagement(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockRedirectRepo := new(MockSlugRedirectRepository)
+
+	mockRedirectRepo.On("List", uint(0)).Return([]*entity.SlugRedirect{{ID: 1}}, nil)
+	mockRedirectRepo.On("GetByID", uint(1)).Return(&entity.SlugRedirect{ID: 1}, nil)
+	mockRedirectRepo.On("Delete", uint(1)).Return(nil)
+
+	postService := service.NewPostService(mockPostRepo, mockCategoryRepo, mockRedirectRepo, newRevisionRepo(), policy.NewPolicy())
+
+	_, err := postService.ListSlugRedirects(authorActor, 0)
+	assert.ErrorIs(t, err, policy.ErrForbidden)
+
+	redirects, err := postService.ListSlugRedirects(editorActor, 0)
+	assert.NoError(t, err)
+	assert.Len(t, redirects, 1)
+
+	assert.NoError(t, postService.DeleteSlugRedirect(editorActor, 1))
+	mockRedirectRepo.AssertExpectations(t)
+}
+
+// 测试修改文章时保存修订版本
+func TestUpdatePostRecordsRevision(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	revisionRepo := new(MockPostRevisionRepository)
+	postService := service.NewPostService(mockPostRepo, new(MockCategoryRepository), new(MockSlugRedirectRepository), revisionRepo, policy.NewPolicy())
+
+	post := &entity.Post{ID: 1, Title: "标题", Content: "内容", Author: authorActor.Username, Slug: "biao-ti", Version: 1}
+	mockPostRepo.On("GetByID", uint(1)).Return(post, nil)
+	mockPostRepo.On("Update", mock.AnythingOfType("*entity.Post")).Return(nil)
+	revisionRepo.On("Create", mock.MatchedBy(func(revision *entity.PostRevision) bool {
+		return revision.PostID == 1 && revision.Content == "新内容" && revision.Author == editorActor.Username
+	})).Return(nil).Once()
+
+	_, err := postService.UpdatePost(editorActor, 1, 1, "标题", "新内容", "", "")
+	assert.NoError(t, err)
+
+	// 标题和内容未变化时不保存新版本
+	_, err = postService.UpdatePost(editorActor, 1, 1, "标题", "新内容", "", "")
+	assert.NoError(t, err)
+	revisionRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+// 测试查看修订历史和对比修订版本
+func TestListAndDiffRevisions(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	revisionRepo := new(MockPostRevisionRepository)
+	postService := service.NewPostService(mockPostRepo, new(MockCategoryRepository), new(MockSlugRedirectRepository), revisionRepo, policy.NewPolicy())
+
+	mockPostRepo.On("GetByID", uint(1)).Return(&entity.Post{ID: 1, Author: authorActor.Username}, nil)
+	first := &entity.PostRevision{PostID: 1, Revision: 1, Title: "标题", Content: "第一行\n第二行"}
+	second := &entity.PostRevision{PostID: 1, Revision: 2, Title: "标题", Content: "第一行\n第二行（修改）"}
+	revisionRepo.On("ListByPostID", uint(1)).Return([]*entity.PostRevision{second, first}, nil)
+	revisionRepo.On("GetByRevision", uint(1), 1).Return(first, nil)
+	revisionRepo.On("GetByRevision", uint(1), 2).Return(second, nil)
+
+	// 只有可以修改文章的用户才能查看修订历史
+	_, err := postService.ListRevisions(otherActor, 1)
+	assert.ErrorIs(t, err, policy.ErrForbidden)
+	_, err = postService.DiffRevisions(readerActor, 1, 1, 2)
+	assert.ErrorIs(t, err, policy.ErrForbidden)
+
+	revisions, err := postService.ListRevisions(authorActor, 1)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 2)
+
+	diff, err := postService.DiffRevisions(editorActor, 1, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, first, diff.From)
+	assert.Equal(t, second, diff.To)
+	assert.Equal(t, []utils.DiffLine{
+		{Op: utils.DiffEqual, Text: "第一行", OldLine: 1, NewLine: 1},
+		{Op: utils.DiffDelete, Text: "第二行", OldLine: 2},
+		{Op: utils.DiffInsert, Text: "第二行（修改）", NewLine: 2},
+	}, diff.Lines)
+}
+
+// 测试恢复修订版本
+func TestRestoreRevision(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	revisionRepo := new(MockPostRevisionRepository)
+	postService := service.NewPostService(mockPostRepo, new(MockCategoryRepository), new(MockSlugRedirectRepository), revisionRepo, policy.NewPolicy())
+
+	post := &entity.Post{ID: 1, Title: "新标题", Content: "新内容", Author: authorActor.Username, Slug: "xin-biao-ti"}
+	mockPostRepo.On("GetByID", uint(1)).Return(post, nil)
+	mockPostRepo.On("Update", mock.AnythingOfType("*entity.Post")).Return(nil)
+	revisionRepo.On("GetByRevision", uint(1), 1).Return(&entity.PostRevision{PostID: 1, Revision: 1, Title: "旧标题", Content: "旧内容", TitleURL: "https://example.com"}, nil)
+	revisionRepo.On("Create", mock.MatchedBy(func(revision *entity.PostRevision) bool {
+		return revision.Title == "旧标题" && revision.Content == "旧内容" && revision.Author == authorActor.Username
+	})).Return(nil)
+
+	_, err := postService.RestoreRevision(otherActor, 1, 1)
+	assert.ErrorIs(t, err, policy.ErrForbidden)
+
+	restored, err := postService.RestoreRevision(authorActor, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "旧标题", restored.Title)
+	assert.Equal(t, "旧内容", restored.Content)
+	assert.Equal(t, "https://example.com", restored.TitleURL)
+	// 恢复修订版本不改变slug
+	assert.Equal(t, "xin-biao-ti", restored.Slug)
+	revisionRepo.AssertExpectations(t)
+}
+
+// 测试作者只能查看自己的回收站，读者无权查看
+func TestListTrashedPostsScopesByAuthor(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	postService := service.NewPostService(mockPostRepo, new(MockCategoryRepository), new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+
+	mockPostRepo.On("ListTrashed", mock.MatchedBy(func(query *repository.TrashQuery) bool {
+		return query.Author == authorActor.Username
+	})).Return([]*entity.Post{}, int64(0), nil).Once()
+	_, _, err := postService.ListTrashedPosts(authorActor, &repository.TrashQuery{})
+	assert.NoError(t, err)
+
+	mockPostRepo.On("ListTrashed", mock.MatchedBy(func(query *repository.TrashQuery) bool {
+		return query.Author == ""
+	})).Return([]*entity.Post{}, int64(0), nil).Once()
+	_, _, err = postService.ListTrashedPosts(editorActor, &repository.TrashQuery{})
+	assert.NoError(t, err)
+
+	_, _, err = postService.ListTrashedPosts(readerActor, &repository.TrashQuery{})
+	assert.ErrorIs(t, err, policy.ErrForbidden)
+	mockPostRepo.AssertExpectations(t)
+}
+
+// 测试从回收站恢复文章
+func TestRestorePost(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	postService := service.NewPostService(mockPostRepo, new(MockCategoryRepository), new(MockSlugRedirectRepository), newRevisionRepo(), policy.NewPolicy())
+
+	deletedAt := time.Now()
+	mockPostRepo.On("GetTrashedByID", uint(1)).Return(&entity.Post{ID: 1, Author: authorActor.Username, DeletedAt: &deletedAt}, nil)
+	mockPostRepo.On("Restore", uint(1)).Return(nil).Once()
 
-	postService := service.NewPostService(mockPostRepo, mockCategoryRepo)
-	err := postService.DeletePost(1)
+	_, err := postService.RestorePost(otherActor, 1)
+	assert.ErrorIs(t, err, policy.ErrForbidden)
+	mockPostRepo.AssertNotCalled(t, "Restore", mock.Anything)
 
+	post, err := postService.RestorePost(authorActor, 1)
 	assert.NoError(t, err)
+	assert.Nil(t, post.DeletedAt)
 	mockPostRepo.AssertExpectations(t)
 }
@@ -0,0 +1,86 @@
+package service_test
+
+import (
+	"testing"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/internal/domain/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// 模拟标签仓储
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) GetOrCreate(names []string) ([]*entity.Tag, error) {
+	args := m.Called(names)
+	return args.Get(0).([]*entity.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetByName(name string) (*entity.Tag, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetByPostID(postID uint) ([]*entity.Tag, error) {
+	args := m.Called(postID)
+	return args.Get(0).([]*entity.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) SetPostTags(postID uint, tagIDs []uint) error {
+	args := m.Called(postID, tagIDs)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) GetCloud(limit int) ([]*repository.TagCount, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]*repository.TagCount), args.Error(1)
+}
+
+func TestSetPostTags(t *testing.T) {
+	mockRepo := new(MockTagRepository)
+	tagService := service.NewTagService(mockRepo)
+
+	tags := []*entity.Tag{{ID: 1, Name: "Go"}, {ID: 2, Name: "数据库 设计"}}
+	// 名称去除首尾空白、合并空白，重复项不区分大小写
+	mockRepo.On("GetOrCreate", []string{"Go", "数据库 设计"}).Return(tags, nil)
+	mockRepo.On("SetPostTags", uint(1), []uint{1, 2}).Return(nil)
+
+	result, err := tagService.SetPostTags(1, []string{" Go ", "go", "", "数据库   设计"})
+	assert.NoError(t, err)
+	assert.Equal(t, tags, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSetPostTagsClear(t *testing.T) {
+	mockRepo := new(MockTagRepository)
+	tagService := service.NewTagService(mockRepo)
+
+	mockRepo.On("SetPostTags", uint(1), []uint{}).Return(nil)
+
+	result, err := tagService.SetPostTags(1, []string{"  "})
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+	mockRepo.AssertNotCalled(t, "GetOrCreate", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetTagByName(t *testing.T) {
+	mockRepo := new(MockTagRepository)
+	tagService := service.NewTagService(mockRepo)
+
+	tag := &entity.Tag{ID: 1, Name: "golang"}
+	mockRepo.On("GetByName", "golang").Return(tag, nil)
+
+	result, err := tagService.GetTagByName("  golang ")
+	assert.NoError(t, err)
+	assert.Equal(t, tag, result)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,182 @@
+package service_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/policy"
+	"blog/internal/domain/service"
+	"blog/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// 模拟用户仓储
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(user *entity.User) error {
+	args := m.Called(user)
+	user.ID = 1 // 模拟数据库自增ID
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) CreateFirst(user *entity.User) error {
+	args := m.Called(user)
+	user.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetByID(id uint) (*entity.User, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByUsername(username string) (*entity.User, error) {
+	args := m.Called(username)
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Count() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateRole(id uint, role entity.Role) error {
+	args := m.Called(id, role)
+	return args.Error(0)
+}
+
+// 测试注册并登录
+func TestRegisterAndAuthenticate(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+
+	var created *entity.User
+	mockUserRepo.On("GetByUsername", "zhangsan").Return((*entity.User)(nil), fmt.Errorf("用户不存在: zhangsan: %w", utils.ErrNotFound)).Once()
+	mockUserRepo.On("Count").Return(int64(0), nil)
+	mockUserRepo.On("CreateFirst", mock.AnythingOfType("*entity.User")).Run(func(args mock.Arguments) {
+		created = args.Get(0).(*entity.User)
+	}).Return(nil)
+
+	userService := service.NewUserService(mockUserRepo, policy.NewPolicy())
+	user, err := userService.Register("zhangsan", "secret123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), user.ID)
+	assert.NotEqual(t, "secret123", user.PasswordHash)
+	assert.Equal(t, entity.RoleAdmin, user.Role, "第一个注册的用户应为管理员")
+
+	mockUserRepo.On("GetByUsername", "zhangsan").Return(created, nil)
+
+	user, err = userService.Authenticate("zhangsan", "secret123")
+	assert.NoError(t, err)
+	assert.Equal(t, "zhangsan", user.Username)
+
+	_, err = userService.Authenticate("zhangsan", "wrong-password")
+	assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// 测试重复注册
+func TestRegisterDuplicateUsername(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+
+	mockUserRepo.On("GetByUsername", "zhangsan").Return(entity.NewUser("zhangsan", "hash", entity.RoleReader), nil)
+
+	userService := service.NewUserService(mockUserRepo, policy.NewPolicy())
+	user, err := userService.Register("zhangsan", "secret123")
+
+	assert.ErrorIs(t, err, service.ErrUserExists)
+	assert.ErrorIs(t, err, utils.ErrConflict)
+	assert.Nil(t, user)
+	mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// 测试同时注册第一个用户时，未能成为第一个用户的请求按读者注册
+func TestRegisterLosesFirstUserRace(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+
+	mockUserRepo.On("GetByUsername", "lisi").Return((*entity.User)(nil), fmt.Errorf("用户不存在: lisi: %w", utils.ErrNotFound))
+	mockUserRepo.On("Count").Return(int64(0), nil)
+	mockUserRepo.On("CreateFirst", mock.AnythingOfType("*entity.User")).Return(fmt.Errorf("第一个用户已存在: %w", utils.ErrConflict))
+	mockUserRepo.On("Create", mock.MatchedBy(func(user *entity.User) bool {
+		return user.Role == entity.RoleReader
+	})).Return(nil)
+
+	userService := service.NewUserService(mockUserRepo, policy.NewPolicy())
+	user, err := userService.Register("lisi", "secret123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.RoleReader, user.Role)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// 测试检查用户名后被并发注册抢先时，唯一约束冲突返回用户名已存在
+func TestRegisterUniqueViolation(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+
+	mockUserRepo.On("GetByUsername", "zhangsan").Return((*entity.User)(nil), fmt.Errorf("用户不存在: zhangsan: %w", utils.ErrNotFound))
+	mockUserRepo.On("Count").Return(int64(3), nil)
+	mockUserRepo.On("Create", mock.AnythingOfType("*entity.User")).Return(fmt.Errorf("用户名已存在: %w", utils.ErrConflict))
+
+	userService := service.NewUserService(mockUserRepo, policy.NewPolicy())
+	user, err := userService.Register("zhangsan", "secret123")
+
+	assert.ErrorIs(t, err, service.ErrUserExists)
+	assert.Nil(t, user)
+	mockUserRepo.AssertNotCalled(t, "CreateFirst", mock.Anything)
+}
+
+// 测试查询用户失败时不能当作用户不存在继续注册
+func TestRegisterLookupError(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+
+	dbErr := errors.New("连接失败")
+	mockUserRepo.On("GetByUsername", "zhangsan").Return((*entity.User)(nil), dbErr)
+
+	userService := service.NewUserService(mockUserRepo, policy.NewPolicy())
+	_, err := userService.Register("zhangsan", "secret123")
+	assert.Equal(t, dbErr, err)
+
+	_, err = userService.Authenticate("zhangsan", "secret123")
+	assert.Equal(t, dbErr, err)
+	mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// 测试只有管理员可以修改角色
+func TestUpdateUserRole(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+
+	mockUserRepo.On("GetByID", uint(2)).Return(&entity.User{ID: 2, Username: "lisi", Role: entity.RoleReader}, nil)
+	mockUserRepo.On("UpdateRole", uint(2), entity.RoleAuthor).Return(nil)
+
+	userService := service.NewUserService(mockUserRepo, policy.NewPolicy())
+
+	editor := &policy.Actor{UserID: 3, Username: "editor", Role: entity.RoleEditor}
+	_, err := userService.UpdateUserRole(editor, 2, entity.RoleAuthor)
+	assert.ErrorIs(t, err, policy.ErrForbidden)
+
+	admin := &policy.Actor{UserID: 1, Username: "admin", Role: entity.RoleAdmin}
+	user, err := userService.UpdateUserRole(admin, 2, entity.RoleAuthor)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.RoleAuthor, user.Role)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// 测试角色未变化时不更新
+func TestUpdateUserRoleUnchanged(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("GetByID", uint(2)).Return(&entity.User{ID: 2, Username: "lisi", Role: entity.RoleAuthor}, nil)
+
+	userService := service.NewUserService(mockUserRepo, policy.NewPolicy())
+
+	admin := &policy.Actor{UserID: 1, Username: "admin", Role: entity.RoleAdmin}
+	user, err := userService.UpdateUserRole(admin, 2, entity.RoleAuthor)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.RoleAuthor, user.Role)
+	mockUserRepo.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything)
+}
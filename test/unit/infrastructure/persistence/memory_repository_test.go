@@ -0,0 +1,140 @@
+package persistence_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/internal/infrastructure/persistence"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryPostRepository_ReturnsCopies(t *testing.T) {
+	repos := openMemoryRepositories(t)
+	post := createPost(t, repos, "Go并发编程", "内容", "go-concurrency", time.Now())
+
+	// 保存后修改调用方持有的实体不影响已保存的数据
+	post.Title = "被修改的标题"
+	found, err := repos.posts.GetByID(post.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Go并发编程", found.Title)
+
+	// 修改返回值同样不影响已保存的数据
+	found.Title = "再次修改"
+	found, err = repos.posts.GetByID(post.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Go并发编程", found.Title)
+
+	// 列表不含正文
+	posts, _, err := repos.posts.List(&repository.PostQuery{})
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Empty(t, posts[0].Content)
+}
+
+func TestMemoryStore_ConcurrentAccess(t *testing.T) {
+	store := persistence.NewMemoryStore()
+	posts := persistence.NewMemoryPostRepository(store)
+	comments := persistence.NewMemoryCommentRepository(store)
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			post := entity.NewPost(fmt.Sprintf("文章%d", i), "内容", "作者", "")
+			post.Slug = fmt.Sprintf("post-%d", i)
+			if !assert.NoError(t, posts.Create(post)) {
+				return
+			}
+			assert.NoError(t, comments.Create(entity.NewComment(post.ID, "评论", "读者")))
+			assert.NoError(t, posts.IncrementViewCount(post.ID))
+			_, _, err := posts.List(&repository.PostQuery{})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	all, err := posts.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, all, workers)
+	ids := make(map[uint]bool)
+	for _, post := range all {
+		ids[post.ID] = true
+		assert.Equal(t, uint(1), post.ViewCount)
+	}
+	assert.Len(t, ids, workers)
+
+	_, total, err := comments.List(&repository.CommentQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(workers), total)
+}
+
+func TestMemoryPostRepository_ListFilters(t *testing.T) {
+	repos := openMemoryRepositories(t)
+	base := time.Now().Add(-time.Hour)
+	goPost := createPost(t, repos, "Go并发编程", "内容", "go-concurrency", base)
+	dbPost := createPost(t, repos, "数据库索引", "内容", "database-index", base.Add(time.Minute))
+	createPost(t, repos, "旅行随笔", "内容", "travel", base.Add(2*time.Minute))
+
+	parent := entity.NewCategory("编程", "", nil)
+	require.NoError(t, repos.categories.Create(parent))
+	child := entity.NewCategory("Go", "", &parent.ID)
+	require.NoError(t, repos.categories.Create(child))
+	require.NoError(t, repos.categories.AddPostToCategory(goPost.ID, child.ID))
+	require.NoError(t, repos.categories.AddPostToCategory(dbPost.ID, parent.ID))
+
+	tags, err := repos.tags.GetOrCreate([]string{"Go"})
+	require.NoError(t, err)
+	require.NoError(t, repos.tags.SetPostTags(goPost.ID, []uint{tags[0].ID}))
+
+	posts, total, err := repos.posts.List(&repository.PostQuery{CategoryID: parent.ID, IncludeDescendants: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []uint{dbPost.ID, goPost.ID}, postIDs(posts))
+
+	// 标签名不区分大小写
+	posts, _, err = repos.posts.List(&repository.PostQuery{Tag: "go"})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{goPost.ID}, postIDs(posts))
+
+	// 创建时间区间为左闭右开
+	posts, _, err = repos.posts.List(&repository.PostQuery{
+		CreatedFrom: goPost.CreatedAt,
+		CreatedTo:   goPost.CreatedAt.Add(2 * time.Minute),
+		Ascending:   true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{goPost.ID, dbPost.ID}, postIDs(posts))
+
+	// 永久删除分类后，文章与分类的关联一并删除
+	require.NoError(t, repos.categories.Delete(child.ID))
+	_, err = repos.categories.PurgeTrashed(time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	posts, _, err = repos.posts.List(&repository.PostQuery{CategoryID: parent.ID, IncludeDescendants: true})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{dbPost.ID}, postIDs(posts))
+}
+
+func TestMemoryPostRepository_SearchMatchesEveryTerm(t *testing.T) {
+	repos := openMemoryRepositories(t)
+	createPost(t, repos, "SQLite入门", "单文件数据库", "sqlite-intro", time.Now())
+	both := createPost(t, repos, "数据库选型", "MySQL与SQLite的对比", "choosing-database", time.Now())
+
+	hits, total, err := repos.posts.Search(&repository.PostSearchQuery{Keyword: "sqlite 对比"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, hits, 1)
+	assert.Equal(t, both.ID, hits[0].Post.ID)
+	assert.Equal(t, 2.0, hits[0].Score)
+
+	_, total, err = repos.posts.Search(&repository.PostSearchQuery{Keyword: "  "})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
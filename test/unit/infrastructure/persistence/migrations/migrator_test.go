@@ -0,0 +1,304 @@
+package migrations_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"blog/internal/infrastructure/persistence/migrations"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试使用的两个迁移
+var testMigrations = []migrations.Migration{
+	{
+		Version: 1,
+		Name:    "create_posts",
+		Up:      "-- 创建文章表\nCREATE TABLE posts (\n    id INT\n);\nCREATE INDEX idx ON posts (id);\n",
+		Down:    "DROP TABLE posts;\n",
+	},
+	{
+		Version: 2,
+		Name:    "add_slug",
+		Up:      "ALTER TABLE posts ADD COLUMN slug VARCHAR(100);\n",
+		Down:    "ALTER TABLE posts DROP COLUMN slug;\n",
+	},
+}
+
+// 创建mock数据库和迁移执行器，并预期创建迁移记录表
+func newMigrator(t *testing.T) (*migrations.Migrator, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	return migrations.NewMigrator(db, "mysql", testMigrations), mock
+}
+
+// 预期获取迁移锁
+func expectLock(mock sqlmock.Sqlmock, acquired int) {
+	mock.ExpectQuery("SELECT GET_LOCK\\(\\?, \\?\\)").
+		WithArgs("blog:schema_migrations", 60).
+		WillReturnRows(sqlmock.NewRows([]string{"acquired"}).AddRow(acquired))
+}
+
+// 预期查询已执行的迁移
+func expectApplied(mock sqlmock.Sqlmock, versions ...int64) {
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range versions {
+		rows.AddRow(version, time.Now())
+	}
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").WillReturnRows(rows)
+}
+
+func TestMigratorUp(t *testing.T) {
+	migrator, mock := newMigrator(t)
+
+	expectLock(mock, 1)
+	expectApplied(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec("ALTER TABLE posts ADD COLUMN slug VARCHAR\\(100\\);").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations \\(version, name\\) VALUES \\(\\?, \\?\\)").
+		WithArgs(2, "add_slug").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("SELECT RELEASE_LOCK\\(\\?\\)").WithArgs("blog:schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := migrator.Up(context.Background())
+	assert.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, int64(2), applied[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorUpSplitsStatements(t *testing.T) {
+	migrator, mock := newMigrator(t)
+
+	expectLock(mock, 1)
+	expectApplied(mock, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec("^CREATE TABLE posts \\(\\s+id INT\\s+\\);$").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^CREATE INDEX idx ON posts \\(id\\);$").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(1, "create_posts").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("SELECT RELEASE_LOCK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := migrator.Up(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorUpFailureStopsAndReleasesLock(t *testing.T) {
+	migrator, mock := newMigrator(t)
+
+	expectLock(mock, 1)
+	expectApplied(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE posts").WillReturnError(errors.New("语法错误"))
+	mock.ExpectRollback()
+	mock.ExpectExec("SELECT RELEASE_LOCK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := migrator.Up(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "执行迁移 1_create_posts 失败")
+	assert.Empty(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorUpLockTimeout(t *testing.T) {
+	migrator, mock := newMigrator(t)
+
+	expectLock(mock, 0)
+
+	_, err := migrator.Up(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "等待迁移锁超时")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorDown(t *testing.T) {
+	migrator, mock := newMigrator(t)
+
+	expectLock(mock, 1)
+	expectApplied(mock, 1, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec("ALTER TABLE posts DROP COLUMN slug;").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations WHERE version = \\?").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("SELECT RELEASE_LOCK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	reverted, err := migrator.Down(context.Background(), 1)
+	assert.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, "add_slug", reverted[0].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorPostgres(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+	migrator := migrations.NewMigrator(db, "postgres", testMigrations)
+
+	// 咨询锁未获取到时重试，记录迁移版本使用编号占位符
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations \\(\\s+version BIGINT").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT pg_try_advisory_lock\\(hashtext\\(\\$1\\)\\)").
+		WithArgs("blog:schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"acquired"}).AddRow(false))
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").
+		WillReturnRows(sqlmock.NewRows([]string{"acquired"}).AddRow(true))
+	expectApplied(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec("ALTER TABLE posts ADD COLUMN slug").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations \\(version, name\\) VALUES \\(\\$1, \\$2\\)").
+		WithArgs(2, "add_slug").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("SELECT pg_advisory_unlock\\(hashtext\\(\\$1\\)\\)").
+		WithArgs("blog:schema_migrations").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := migrator.Up(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorStatus(t *testing.T) {
+	migrator, mock := newMigrator(t)
+
+	expectApplied(mock, 1, 9)
+
+	statuses, err := migrator.Status(context.Background())
+	assert.NoError(t, err)
+	require.Len(t, statuses, 3)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+	assert.Equal(t, int64(9), statuses[2].Version)
+	assert.True(t, statuses[2].Missing)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_slug.up.sql":       {Data: []byte("ALTER TABLE posts ADD COLUMN slug VARCHAR(100);")},
+		"0002_add_slug.down.sql":     {Data: []byte("ALTER TABLE posts DROP COLUMN slug;")},
+		"0001_create_posts.up.sql":   {Data: []byte("CREATE TABLE posts (id INT);")},
+		"0001_create_posts.down.sql": {Data: []byte("DROP TABLE posts;")},
+	}
+
+	list, err := migrations.Load(fsys)
+	assert.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, int64(1), list[0].Version)
+	assert.Equal(t, "create_posts", list[0].Name)
+	assert.Equal(t, "DROP TABLE posts;", list[0].Down)
+	assert.Equal(t, int64(2), list[1].Version)
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		message string
+	}{
+		{
+			name:    "缺少down文件",
+			fsys:    fstest.MapFS{"0001_create_posts.up.sql": {Data: []byte("CREATE TABLE posts (id INT);")}},
+			message: "缺少up或down文件",
+		},
+		{
+			name: "版本号重复",
+			fsys: fstest.MapFS{
+				"0001_create_posts.up.sql": {Data: []byte("SELECT 1;")},
+				"0001_create_tags.up.sql":  {Data: []byte("SELECT 1;")},
+			},
+			message: "迁移版本号 1 重复",
+		},
+		{
+			name:    "文件名不合法",
+			fsys:    fstest.MapFS{"create_posts.sql": {Data: []byte("SELECT 1;")}},
+			message: "迁移文件名不合法",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := migrations.Load(tt.fsys)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
+
+func TestEmbedded(t *testing.T) {
+	var expected []migrations.Migration
+	for _, driver := range migrations.Drivers {
+		list, err := migrations.Embedded(driver)
+		assert.NoError(t, err)
+		require.NotEmpty(t, list)
+		assert.Equal(t, "initial_schema", list[0].Name)
+
+		// 各数据库的迁移版本号和名称一一对应
+		if expected == nil {
+			expected = list
+			continue
+		}
+		require.Len(t, list, len(expected), driver)
+		for i := range list {
+			assert.Equal(t, expected[i].Version, list[i].Version, driver)
+			assert.Equal(t, expected[i].Name, list[i].Name, driver)
+		}
+	}
+
+	_, err := migrations.Embedded("oracle")
+	assert.Error(t, err)
+}
+
+func TestCreate(t *testing.T) {
+	root := t.TempDir()
+
+	paths, err := migrations.Create(root, "create_posts")
+	assert.NoError(t, err)
+	require.Len(t, paths, 2*len(migrations.Drivers))
+	assert.Equal(t, filepath.Join(root, "mysql", "0001_create_posts.up.sql"), paths[0])
+	assert.Equal(t, filepath.Join(root, "mysql", "0001_create_posts.down.sql"), paths[1])
+
+	// 每种数据库都生成同一版本的迁移文件
+	paths, err = migrations.Create(root, "add_slug")
+	assert.NoError(t, err)
+	for _, driver := range migrations.Drivers {
+		assert.Contains(t, paths, filepath.Join(root, driver, "0002_add_slug.up.sql"))
+		_, err = os.Stat(filepath.Join(root, driver, "0002_add_slug.down.sql"))
+		assert.NoError(t, err)
+	}
+
+	_, err = migrations.Create(root, "Add Slug")
+	assert.Error(t, err)
+}
+
+func TestCreateRejectsDivergedVersions(t *testing.T) {
+	root := t.TempDir()
+	_, err := migrations.Create(root, "create_posts")
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(filepath.Join(root, "sqlite", "0001_create_posts.up.sql")))
+	require.NoError(t, os.Remove(filepath.Join(root, "sqlite", "0001_create_posts.down.sql")))
+
+	_, err = migrations.Create(root, "add_slug")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "迁移版本不一致")
+	_, err = os.Stat(filepath.Join(root, "mysql", "0002_add_slug.up.sql"))
+	assert.True(t, os.IsNotExist(err))
+}
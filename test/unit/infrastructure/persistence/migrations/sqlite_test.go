@@ -0,0 +1,110 @@
+package migrations_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"blog/internal/config"
+	"blog/internal/infrastructure/persistence"
+	"blog/internal/infrastructure/persistence/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 创建空的SQLite数据库和使用内置迁移的迁移执行器
+func newSQLiteMigrator(t *testing.T) (*sql.DB, *migrations.Migrator) {
+	conn, err := persistence.Open(&config.DatabaseConfig{
+		Driver: "sqlite",
+		Path:   filepath.Join(t.TempDir(), "blog.db"),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	list, err := migrations.Embedded("sqlite")
+	require.NoError(t, err)
+	return conn.DB, migrations.NewMigrator(conn.DB, "sqlite", list)
+}
+
+// 查询单个整数
+func queryInt(t *testing.T, db *sql.DB, query string) int {
+	var n int
+	require.NoError(t, db.QueryRow(query).Scan(&n))
+	return n
+}
+
+func TestSQLiteMigrationsUpgradeBaselineDatabase(t *testing.T) {
+	db, migrator := newSQLiteMigrator(t)
+
+	// 最早版本的InitTables创建的表结构和数据，没有schema_migrations表
+	for _, statement := range []string{
+		`CREATE TABLE posts (id INTEGER PRIMARY KEY AUTOINCREMENT, title TEXT NOT NULL, content TEXT NOT NULL, author TEXT NOT NULL, title_url TEXT, view_count INTEGER DEFAULT 0, created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP)`,
+		`CREATE TABLE comments (id INTEGER PRIMARY KEY AUTOINCREMENT, post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE, content TEXT NOT NULL, author TEXT NOT NULL, created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP)`,
+		`CREATE TABLE categories (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL UNIQUE COLLATE NOCASE, description TEXT)`,
+		`CREATE TABLE post_categories (id INTEGER PRIMARY KEY AUTOINCREMENT, post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE, category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE, UNIQUE (post_id, category_id))`,
+		`INSERT INTO posts (title, content, author, title_url) VALUES ('旧文章', '内容', '作者', 'old-post')`,
+		`INSERT INTO comments (post_id, content, author) VALUES (1, '旧评论', '读者')`,
+		`INSERT INTO categories (name) VALUES ('编程')`,
+		`INSERT INTO post_categories (post_id, category_id) VALUES (1, 1)`,
+	} {
+		_, err := db.Exec(statement)
+		require.NoError(t, err)
+	}
+
+	_, err := migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	// 已有文章视为已发布，已有评论视为已通过审核，并补全第1版修订版本
+	var status string
+	var publishedAt sql.NullTime
+	require.NoError(t, db.QueryRow(`SELECT status, published_at FROM posts WHERE id = 1`).Scan(&status, &publishedAt))
+	assert.Equal(t, "published", status)
+	assert.True(t, publishedAt.Valid)
+	require.NoError(t, db.QueryRow(`SELECT status FROM comments WHERE id = 1`).Scan(&status))
+	assert.Equal(t, "approved", status)
+	assert.Equal(t, 1, queryInt(t, db, `SELECT COUNT(*) FROM post_revisions WHERE post_id = 1 AND revision = 1`))
+	assert.Equal(t, 1, queryInt(t, db, `SELECT version FROM posts WHERE id = 1`))
+	assert.Equal(t, 0, queryInt(t, db, `SELECT COUNT(*) FROM posts WHERE deleted_at IS NOT NULL OR slug IS NOT NULL`))
+
+	// 新增的文章默认为草稿，新增的评论默认为待审核
+	_, err = db.Exec(`INSERT INTO posts (title, content, author) VALUES ('新文章', '内容', '作者')`)
+	require.NoError(t, err)
+	require.NoError(t, db.QueryRow(`SELECT status FROM posts WHERE id = 2`).Scan(&status))
+	assert.Equal(t, "draft", status)
+}
+
+func TestSQLiteMigrationsDownAndUp(t *testing.T) {
+	db, migrator := newSQLiteMigrator(t)
+	ctx := context.Background()
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO posts (title, content, author) VALUES ('文章', '内容', '作者')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO categories (name) VALUES ('编程')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO post_categories (post_id, category_id) VALUES (1, 1)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO comments (post_id, content, author) VALUES (1, '评论', '读者')`)
+	require.NoError(t, err)
+
+	// 回滚到初始表结构，重建分类表和评论表时保留数据
+	reverted, err := migrator.Down(ctx, len(applied)-1)
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(applied)-1)
+	assert.Equal(t, 1, queryInt(t, db, `SELECT COUNT(*) FROM post_categories`))
+	assert.Equal(t, 1, queryInt(t, db, `SELECT COUNT(*) FROM comments`))
+	assert.Equal(t, 0, queryInt(t, db, `SELECT COUNT(*) FROM sqlite_master WHERE name IN ('users', 'tags', 'post_revisions')`))
+
+	// 重新执行迁移
+	again, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, again, len(applied)-1)
+	assert.Equal(t, 1, queryInt(t, db, `SELECT COUNT(*) FROM post_revisions`))
+
+	_, err = migrator.Down(ctx, len(applied))
+	require.NoError(t, err)
+	assert.Equal(t, 0, queryInt(t, db, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name NOT IN ('schema_migrations', 'sqlite_sequence')`))
+}
@@ -0,0 +1,136 @@
+package persistence_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/internal/infrastructure/persistence"
+	"blog/pkg/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPostgresMock 创建使用PostgreSQL方言的mock数据库连接
+func newPostgresMock(t *testing.T) (*persistence.Connection, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return persistence.NewConnection(db, persistence.Postgres), mock
+}
+
+func TestPostgres_CreatePostReturningID(t *testing.T) {
+	conn, mock := newPostgresMock(t)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	post := entity.NewPost("测试标题", "测试内容", "测试作者", "")
+	post.Slug = "ce-shi-biao-ti"
+
+	mock.ExpectQuery("INSERT INTO posts \\(.+\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7, \\$8, \\$9, \\$10, \\$11\\) RETURNING id").
+		WithArgs(post.Title, post.Content, post.Author, post.TitleURL, post.Slug, post.ViewCount, post.Status, post.PublishedAt, post.CreatedAt, post.UpdatedAt, post.Version).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	require.NoError(t, repo.Create(post))
+	assert.Equal(t, uint(7), post.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgres_UniqueViolationIsConflict(t *testing.T) {
+	conn, mock := newPostgresMock(t)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	mock.ExpectQuery("INSERT INTO posts").
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "uk_posts_slug"})
+
+	err := repo.Create(entity.NewPost("测试标题", "测试内容", "测试作者", ""))
+	assert.True(t, errors.Is(err, utils.ErrConflict))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgres_SearchUsesTsvector(t *testing.T) {
+	conn, mock := newPostgresMock(t)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM posts p WHERE .+ AND p.status = \\$1 AND p.search_vector @@ plainto_tsquery\\('simple', \\$2\\)").
+		WithArgs(entity.PostStatusPublished, "golang").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "title_url", "slug", "view_count", "status", "published_at", "created_at", "updated_at", "version", "score"}).
+		AddRow(1, "Golang", "内容", "作者", "", "golang", 0, "published", now, now, now, 1, 0.6)
+	mock.ExpectQuery("ts_rank\\(p.search_vector, plainto_tsquery\\('simple', \\$1\\)\\) AS score .+ p.status = \\$2 AND p.search_vector @@ plainto_tsquery\\('simple', \\$3\\) .+ LIMIT \\$4 OFFSET \\$5").
+		WithArgs("golang", entity.PostStatusPublished, "golang", 10, 0).
+		WillReturnRows(rows)
+
+	hits, total, err := repo.Search(&repository.PostSearchQuery{
+		PostQuery: repository.PostQuery{Status: entity.PostStatusPublished},
+		Keyword:   "golang",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, hits, 1)
+	assert.Equal(t, 0.6, hits[0].Score)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgres_TagUpsertAndTransaction(t *testing.T) {
+	conn, mock := newPostgresMock(t)
+	repo := persistence.NewSQLTagRepository(conn)
+
+	mock.ExpectQuery("INSERT INTO tags \\(name, created_at\\) VALUES \\(\\$1, \\$2\\) ON CONFLICT \\(name\\) DO UPDATE SET name = excluded.name RETURNING id").
+		WithArgs("go", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery("SELECT id, name, created_at FROM tags WHERE id IN \\(\\$1\\)").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at"}).AddRow(3, "go", time.Now()))
+
+	tags, err := repo.GetOrCreate([]string{"go"})
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, uint(3), tags[0].ID)
+
+	// 事务中的语句同样改写占位符
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM post_tags WHERE post_id = \\$1").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO post_tags \\(post_id, tag_id\\) VALUES \\(\\$1, \\$2\\), \\(\\$3, \\$4\\)").
+		WithArgs(1, 3, 1, 4).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.SetPostTags(1, []uint{3, 4}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresLocker(t *testing.T) {
+	conn, mock := newPostgresMock(t)
+	locker := persistence.NewPostgresLocker(conn)
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock\\(hashtext\\(\\$1\\)\\)").
+		WithArgs("publish").
+		WillReturnRows(sqlmock.NewRows([]string{"acquired"}).AddRow(true))
+	mock.ExpectExec("SELECT pg_advisory_unlock\\(hashtext\\(\\$1\\)\\)").
+		WithArgs("publish").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	unlock, acquired, err := locker.TryLock(context.Background(), "publish")
+	require.NoError(t, err)
+	require.True(t, acquired)
+	unlock()
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").
+		WillReturnRows(sqlmock.NewRows([]string{"acquired"}).AddRow(false))
+	_, acquired, err = locker.TryLock(context.Background(), "publish")
+	require.NoError(t, err)
+	assert.False(t, acquired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
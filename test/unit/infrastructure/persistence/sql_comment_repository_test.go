@@ -0,0 +1,110 @@
+package persistence_test
+
+import (
+	"testing"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/internal/infrastructure/persistence"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLCommentRepository_CreateReply(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLCommentRepository(conn)
+
+	reply := entity.NewReply(&entity.Comment{ID: 1, PostID: 2}, "回复内容", "读者")
+	reply.AuthorIP = "203.0.113.7"
+	mock.ExpectExec("INSERT INTO comments \\(post_id, parent_id, depth, content, author, status, author_ip, user_agent, created_at\\)").
+		WithArgs(2, 1, 1, "回复内容", "读者", entity.CommentStatusPending, "203.0.113.7", "", reply.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	err = repo.Create(reply)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), reply.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLCommentRepository_GetReplies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLCommentRepository(conn)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "post_id", "parent_id", "depth", "content", "author", "status", "author_ip", "user_agent", "created_at"}).
+		AddRow(3, 1, 1, 1, "回复", "读者", "approved", "", "", now).
+		AddRow(4, 1, 3, 2, "回复的回复", "作者", "approved", "", "", now.Add(time.Minute))
+	mock.ExpectQuery("WITH RECURSIVE comment_thread AS (.+) WHERE c.parent_id IN \\(\\?, \\?\\) AND c.status = \\? (.+) ORDER BY c.created_at, c.id").
+		WithArgs(1, 2, entity.CommentStatusApproved, entity.CommentStatusApproved).
+		WillReturnRows(rows)
+
+	replies, err := repo.GetReplies([]uint{1, 2})
+	assert.NoError(t, err)
+	assert.Len(t, replies, 2)
+	assert.Equal(t, uint(3), *replies[1].ParentID)
+	assert.Equal(t, 2, replies[1].Depth)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLCommentRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLCommentRepository(conn)
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM comments c WHERE c.deleted_at IS NULL AND EXISTS (.+) AND c.status = \\?").
+		WithArgs(entity.CommentStatusPending).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	rows := sqlmock.NewRows([]string{"id", "post_id", "parent_id", "depth", "content", "author", "status", "author_ip", "user_agent", "created_at"}).
+		AddRow(7, 1, nil, 0, "待审核评论", "读者", "pending", "203.0.113.7", "Mozilla/5.0", time.Now())
+	mock.ExpectQuery("FROM comments c WHERE c.deleted_at IS NULL AND EXISTS (.+) AND c.status = \\? ORDER BY c.created_at, c.id LIMIT \\? OFFSET \\?").
+		WithArgs(entity.CommentStatusPending, 10, 0).
+		WillReturnRows(rows)
+
+	comments, total, err := repo.List(&repository.CommentQuery{Status: entity.CommentStatusPending})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, comments, 1)
+	assert.Nil(t, comments[0].ParentID)
+	assert.Equal(t, entity.CommentStatusPending, comments[0].Status)
+	assert.Equal(t, "203.0.113.7", comments[0].AuthorIP)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLCommentRepository_UpdateStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLCommentRepository(conn)
+
+	mock.ExpectExec("UPDATE comments SET status = \\? WHERE id IN \\(\\?, \\?\\) AND deleted_at IS NULL").
+		WithArgs(entity.CommentStatusApproved, 3, 4).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	updated, err := repo.UpdateStatus([]uint{3, 4}, entity.CommentStatusApproved)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
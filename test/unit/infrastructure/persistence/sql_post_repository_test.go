@@ -0,0 +1,496 @@
+package persistence_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/internal/infrastructure/persistence"
+	"blog/pkg/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLPostRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	now := time.Now()
+	post := &entity.Post{
+		Title:     "测试标题",
+		Content:   "测试内容",
+		Author:    "测试作者",
+		Slug:      "ce-shi-biao-ti",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	mock.ExpectExec("INSERT INTO posts").
+		WithArgs(post.Title, post.Content, post.Author, post.TitleURL, post.Slug, post.ViewCount, post.Status, post.PublishedAt, post.CreatedAt, post.UpdatedAt, post.Version).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.Create(post)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), post.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLPostRepository_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	now := time.Now()
+	expectedPost := &entity.Post{
+		ID:        1,
+		Title:     "测试标题",
+		Content:   "测试内容",
+		Author:    "测试作者",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "title_url", "slug", "view_count", "status", "published_at", "created_at", "updated_at", "version"}).
+		AddRow(expectedPost.ID, expectedPost.Title, expectedPost.Content, expectedPost.Author, expectedPost.TitleURL, "ce-shi", expectedPost.ViewCount, "published", now, expectedPost.CreatedAt, expectedPost.UpdatedAt, 1)
+
+	mock.ExpectQuery("SELECT (.+) FROM posts p WHERE p.id = ?").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	post, err := repo.GetByID(1)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedPost.ID, post.ID)
+	assert.Equal(t, expectedPost.Title, post.Title)
+	assert.Equal(t, expectedPost.Content, post.Content)
+	assert.Equal(t, expectedPost.Author, post.Author)
+	assert.Equal(t, entity.PostStatusPublished, post.Status)
+	assert.NotNil(t, post.PublishedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLPostRepository_GetByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	mock.ExpectQuery("SELECT (.+) FROM posts p WHERE p.id = ?").
+		WithArgs(999).
+		WillReturnError(sql.ErrNoRows)
+
+	post, err := repo.GetByID(999)
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+	assert.Nil(t, post)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 查询失败不能当作文章不存在
+func TestSQLPostRepository_GetByID_QueryError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	mock.ExpectQuery("SELECT (.+) FROM posts p WHERE p.id = ?").
+		WithArgs(1).
+		WillReturnError(sql.ErrConnDone)
+
+	_, err = repo.GetByID(1)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.NotErrorIs(t, err, utils.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// slug唯一索引冲突归类为资源冲突
+func TestSQLPostRepository_CreateDuplicateSlug(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	mock.ExpectExec("INSERT INTO posts").
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'biao-ti' for key 'uk_posts_slug'"})
+
+	err = repo.Create(entity.NewPost("标题", "内容", "作者", ""))
+	assert.ErrorIs(t, err, utils.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLPostRepository_GetBySlug(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "title_url", "slug", "view_count", "status", "published_at", "created_at", "updated_at", "version"}).
+		AddRow(1, "你好世界", "内容", "作者", "", "ni-hao-shi-jie", 0, "published", now, now, now, 1)
+	mock.ExpectQuery("SELECT (.+) FROM posts p WHERE p.slug = ?").
+		WithArgs("ni-hao-shi-jie").
+		WillReturnRows(rows)
+
+	post, err := repo.GetBySlug("ni-hao-shi-jie")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), post.ID)
+	assert.Equal(t, "ni-hao-shi-jie", post.Slug)
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM posts WHERE slug = \\? AND id <> \\?").
+		WithArgs("ni-hao-shi-jie", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	exists, err := repo.SlugExists("ni-hao-shi-jie", 1)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLPostRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	post := &entity.Post{
+		ID:      1,
+		Title:   "更新标题",
+		Content: "更新内容",
+		Slug:    "geng-xin-biao-ti",
+		Version: 2,
+	}
+
+	mock.ExpectExec("UPDATE posts SET title = \\?, content = \\?, title_url = \\?, slug = \\?, status = \\?, published_at = \\?, updated_at = \\?, version = version \\+ 1 WHERE id = \\? AND version = \\?").
+		WithArgs(post.Title, post.Content, post.TitleURL, post.Slug, post.Status, post.PublishedAt, sqlmock.AnyArg(), post.ID, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Update(post)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), post.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLPostRepository_UpdateVersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	post := &entity.Post{ID: 1, Title: "更新标题", Content: "更新内容", Slug: "geng-xin-biao-ti", Version: 2}
+	mock.ExpectExec("UPDATE posts SET (.+) WHERE id = \\? AND version = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Update(post)
+	assert.ErrorIs(t, err, repository.ErrVersionConflict)
+	assert.Equal(t, uint(2), post.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLPostRepository_IncrementViewCount(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	mock.ExpectExec("UPDATE posts SET view_count = view_count \\+ 1, updated_at = updated_at WHERE id = \\?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.IncrementViewCount(1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLPostRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	mock.ExpectExec("UPDATE posts SET deleted_at = \\?, updated_at = updated_at WHERE id = \\? AND deleted_at IS NULL").
+		WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Delete(1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLPostRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	now := time.Now()
+	query := &repository.PostQuery{
+		Page:       2,
+		PageSize:   5,
+		Author:     "测试作者",
+		CategoryID: 3,
+		SortBy:     repository.SortByViewCount,
+	}
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM posts p WHERE p.deleted_at IS NULL AND p.author = \\? AND EXISTS").
+		WithArgs("测试作者", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(6))
+
+	rows := sqlmock.NewRows([]string{"id", "title", "author", "title_url", "slug", "view_count", "status", "published_at", "created_at", "updated_at", "version"}).
+		AddRow(6, "测试标题", "测试作者", "", "ce-shi", 10, "published", now, now, now, 1)
+	mock.ExpectQuery("ORDER BY p.view_count DESC, p.id DESC\\s+LIMIT \\? OFFSET \\?").
+		WithArgs("测试作者", 3, 5, 5).
+		WillReturnRows(rows)
+
+	posts, total, err := repo.List(query)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), total)
+	assert.Len(t, posts, 1)
+	assert.Equal(t, uint(6), posts[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLPostRepository_ListCategoryWithDescendants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM posts p WHERE p.deleted_at IS NULL AND EXISTS (.+) WITH RECURSIVE category_tree (.+) JOIN category_tree ct ON c.parent_id = ct.id").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	rows := sqlmock.NewRows([]string{"id", "title", "author", "title_url", "slug", "view_count", "status", "published_at", "created_at", "updated_at", "version"}).
+		AddRow(2, "子分类文章", "测试作者", "", "zi-fen-lei", 0, "published", now, now, now, 1)
+	mock.ExpectQuery("WITH RECURSIVE category_tree (.+) LIMIT \\? OFFSET \\?").
+		WithArgs(1, 10, 0).
+		WillReturnRows(rows)
+
+	posts, total, err := repo.List(&repository.PostQuery{CategoryID: 1, IncludeDescendants: true})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, posts, 1)
+	assert.Equal(t, "子分类文章", posts[0].Title)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLPostRepository_ListByCursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	now := time.Now()
+	cursor := &repository.Cursor{CreatedAt: now, ID: 10, Backward: true}
+
+	// 向前翻页时按正序查询，多取一条判断是否还有更多
+	rows := sqlmock.NewRows([]string{"id", "title", "author", "title_url", "slug", "view_count", "status", "published_at", "created_at", "updated_at", "version"}).
+		AddRow(11, "标题11", "作者", "", "biao-ti-11", 0, "published", nil, now, now, 1).
+		AddRow(12, "标题12", "作者", "", "biao-ti-12", 0, "published", nil, now.Add(time.Second), now, 1).
+		AddRow(13, "标题13", "作者", "", "biao-ti-13", 0, "published", nil, now.Add(2*time.Second), now, 1)
+	mock.ExpectQuery("WHERE p.deleted_at IS NULL AND \\(p.created_at > \\? OR \\(p.created_at = \\? AND p.id > \\?\\)\\)\\s+ORDER BY p.created_at ASC, p.id ASC\\s+LIMIT \\?").
+		WithArgs(now, now, 10, 3).
+		WillReturnRows(rows)
+
+	posts, hasMore, err := repo.ListByCursor(&repository.PostQuery{}, &repository.CursorQuery{Limit: 2, Cursor: cursor})
+	assert.NoError(t, err)
+	assert.True(t, hasMore)
+	assert.Len(t, posts, 2)
+	// 结果需恢复为倒序
+	assert.Equal(t, uint(12), posts[0].ID)
+	assert.Equal(t, uint(11), posts[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLPostRepository_GetDueScheduled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	now := time.Now()
+	publishAt := now.Add(-time.Minute)
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "title_url", "slug", "view_count", "status", "published_at", "created_at", "updated_at", "version"}).
+		AddRow(1, "定时文章", "内容", "作者", "", "ding-shi-wen-zhang", 0, "scheduled", publishAt, now, now, 1)
+	mock.ExpectQuery("SELECT (.+) FROM posts p WHERE p.status = \\? AND p.published_at <= \\?").
+		WithArgs(entity.PostStatusScheduled, now).
+		WillReturnRows(rows)
+
+	posts, err := repo.GetDueScheduled(now)
+	assert.NoError(t, err)
+	assert.Len(t, posts, 1)
+	assert.Equal(t, entity.PostStatusScheduled, posts[0].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLPostRepository_Search(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM posts p WHERE p.deleted_at IS NULL AND p.status = \\? AND MATCH\\(p.title, p.content\\) AGAINST \\(\\? IN NATURAL LANGUAGE MODE\\)").
+		WithArgs(entity.PostStatusPublished, "数据库").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "title_url", "slug", "view_count", "status", "published_at", "created_at", "updated_at", "version", "score"}).
+		AddRow(1, "数据库优化", "索引与数据库", "作者", "", "shu-ju-ku-you-hua", 0, "published", now, now, now, 1, 2.5)
+	mock.ExpectQuery("AS score\\s+FROM posts p WHERE (.+)ORDER BY score DESC, p.id DESC\\s+LIMIT \\? OFFSET \\?").
+		WithArgs("数据库", entity.PostStatusPublished, "数据库", 10, 0).
+		WillReturnRows(rows)
+
+	query := &repository.PostSearchQuery{Keyword: "数据库"}
+	query.Status = entity.PostStatusPublished
+	hits, total, err := repo.Search(query)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, "数据库优化", hits[0].Post.Title)
+	assert.Equal(t, 2.5, hits[0].Score)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCursorEncodeDecode(t *testing.T) {
+	cursor := repository.Cursor{CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), ID: 42}
+
+	decoded, err := repository.DecodeCursor(repository.EncodeCursor(cursor))
+	assert.NoError(t, err)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.ID, decoded.ID)
+
+	_, err = repository.DecodeCursor("not-a-cursor")
+	assert.ErrorIs(t, err, repository.ErrInvalidCursor)
+}
+
+func TestSQLPostRepository_ListTrashed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM posts p WHERE p.deleted_at IS NOT NULL AND p.author = \\?").
+		WithArgs("作者").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	rows := sqlmock.NewRows([]string{"id", "title", "author", "title_url", "slug", "view_count", "status", "published_at", "created_at", "updated_at", "version", "deleted_at"}).
+		AddRow(1, "标题", "作者", "", "biao-ti", 0, "draft", nil, now, now, 1, now)
+	mock.ExpectQuery("ORDER BY p.deleted_at DESC, p.id DESC LIMIT \\? OFFSET \\?").
+		WithArgs("作者", 10, 0).
+		WillReturnRows(rows)
+
+	posts, total, err := repo.ListTrashed(&repository.TrashQuery{Author: "作者"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, posts, 1)
+	assert.NotNil(t, posts[0].DeletedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLPostRepository_RestoreNotTrashed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	mock.ExpectExec("UPDATE posts SET deleted_at = NULL, updated_at = updated_at WHERE id = \\? AND deleted_at IS NOT NULL").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Restore(1)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLPostRepository_PurgeTrashed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRepository(conn)
+
+	before := time.Now().Add(-30 * 24 * time.Hour)
+	mock.ExpectExec("DELETE FROM posts WHERE deleted_at IS NOT NULL AND deleted_at < \\?").
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	purged, err := repo.PurgeTrashed(before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
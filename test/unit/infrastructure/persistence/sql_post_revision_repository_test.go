@@ -0,0 +1,63 @@
+package persistence_test
+
+import (
+	"testing"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/infrastructure/persistence"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLPostRevisionRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRevisionRepository(conn)
+
+	revision := entity.NewPostRevision(&entity.Post{ID: 3, Title: "标题", Content: "内容"}, "作者")
+	mock.ExpectExec("INSERT INTO post_revisions (.+) SELECT \\?, COALESCE\\(MAX\\(revision\\), 0\\) \\+ 1, (.+) FROM post_revisions WHERE post_id = \\?").
+		WithArgs(3, "标题", "内容", "", "作者", revision.CreatedAt, 3).
+		WillReturnResult(sqlmock.NewResult(8, 1))
+	mock.ExpectQuery("SELECT revision FROM post_revisions WHERE id = \\?").
+		WithArgs(8).
+		WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(4))
+
+	err = repo.Create(revision)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(8), revision.ID)
+	assert.Equal(t, 4, revision.Revision)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLPostRevisionRepository_ListByPostID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLPostRevisionRepository(conn)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "post_id", "revision", "title", "content", "title_url", "author", "created_at"}).
+		AddRow(9, 3, 2, "新标题", "新内容", "", "编辑", now).
+		AddRow(8, 3, 1, "标题", "内容", "", "作者", now.Add(-time.Hour))
+	mock.ExpectQuery("FROM post_revisions WHERE post_id = \\? ORDER BY revision DESC").
+		WithArgs(3).
+		WillReturnRows(rows)
+
+	revisions, err := repo.ListByPostID(3)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, 2, revisions[0].Revision)
+	assert.Equal(t, "编辑", revisions[0].Author)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
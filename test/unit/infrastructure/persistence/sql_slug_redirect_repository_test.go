@@ -0,0 +1,58 @@
+package persistence_test
+
+import (
+	"testing"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/infrastructure/persistence"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLSlugRedirectRepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLSlugRedirectRepository(conn)
+
+	redirect := entity.NewSlugRedirect("old-slug", 1)
+	mock.ExpectExec("INSERT INTO post_slug_redirects (.+) ON DUPLICATE KEY UPDATE").
+		WithArgs(redirect.OldSlug, redirect.PostID, redirect.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	err = repo.Save(redirect)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), redirect.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLSlugRedirectRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLSlugRedirectRepository(conn)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "old_slug", "post_id", "created_at"}).
+		AddRow(2, "older-slug", 1, now).
+		AddRow(1, "oldest-slug", 1, now.Add(-time.Hour))
+	mock.ExpectQuery("SELECT (.+) FROM post_slug_redirects WHERE post_id = \\? ORDER BY created_at DESC").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	redirects, err := repo.List(1)
+	assert.NoError(t, err)
+	assert.Len(t, redirects, 2)
+	assert.Equal(t, "older-slug", redirects[0].OldSlug)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,94 @@
+package persistence_test
+
+import (
+	"testing"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/infrastructure/persistence"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLTagRepository_GetOrCreate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLTagRepository(conn)
+
+	mock.ExpectExec("INSERT INTO tags (.+) ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID\\(id\\)").
+		WithArgs("go", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(3, 0))
+	mock.ExpectExec("INSERT INTO tags (.+) ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID\\(id\\)").
+		WithArgs("mysql", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "name", "created_at"}).
+		AddRow(3, "go", now).
+		AddRow(7, "mysql", now)
+	mock.ExpectQuery("SELECT id, name, created_at FROM tags WHERE id IN \\(\\?, \\?\\)").
+		WithArgs(3, 7).
+		WillReturnRows(rows)
+
+	tags, err := repo.GetOrCreate([]string{"go", "mysql"})
+	assert.NoError(t, err)
+	assert.Len(t, tags, 2)
+	assert.Equal(t, uint(7), tags[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLTagRepository_SetPostTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLTagRepository(conn)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM post_tags WHERE post_id = \\?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO post_tags \\(post_id, tag_id\\) VALUES \\(\\?, \\?\\), \\(\\?, \\?\\)").
+		WithArgs(1, 3, 1, 7).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err = repo.SetPostTags(1, []uint{3, 7})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLTagRepository_GetCloud(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLTagRepository(conn)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "name", "created_at", "post_count"}).
+		AddRow(3, "go", now, 5).
+		AddRow(7, "mysql", now, 2)
+	mock.ExpectQuery("SELECT (.+) FROM tags t JOIN post_tags pt (.+) WHERE p.status = \\? AND p.deleted_at IS NULL GROUP BY (.+) ORDER BY post_count DESC, t.name LIMIT \\?").
+		WithArgs(entity.PostStatusPublished, 10).
+		WillReturnRows(rows)
+
+	counts, err := repo.GetCloud(10)
+	assert.NoError(t, err)
+	assert.Len(t, counts, 2)
+	assert.Equal(t, "go", counts[0].Tag.Name)
+	assert.Equal(t, int64(5), counts[0].PostCount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,31 @@
+package persistence_test
+
+import (
+	"testing"
+
+	"blog/internal/domain/entity"
+	"blog/internal/infrastructure/persistence"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLUserRepository_UpdateRoleUnchanged(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建mock数据库连接失败: %v", err)
+	}
+	defer db.Close()
+
+	conn := persistence.NewConnection(db, persistence.MySQL)
+	repo := persistence.NewSQLUserRepository(conn)
+
+	// MySQL在新角色与原角色相同时报告0行受影响
+	mock.ExpectExec("UPDATE users SET role = \\? WHERE id = \\?").
+		WithArgs(entity.RoleEditor, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.UpdateRole(2, entity.RoleEditor)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
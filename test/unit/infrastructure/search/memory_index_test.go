@@ -0,0 +1,109 @@
+package search_test
+
+import (
+	"testing"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/internal/infrastructure/search"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"go", "语言", "言入", "入门", "v2"}, search.Tokenize("Go语言入门, v2"))
+	assert.Equal(t, []string{"库"}, search.Tokenize("库"))
+}
+
+func newDoc(id uint, title, content string, status entity.PostStatus, categoryIDs ...uint) *repository.SearchDocument {
+	return &repository.SearchDocument{
+		Post: &entity.Post{
+			ID:        id,
+			Title:     title,
+			Content:   content,
+			Author:    "作者",
+			Status:    status,
+			CreatedAt: time.Date(2026, 1, int(id), 0, 0, 0, 0, time.UTC),
+		},
+		CategoryIDs: categoryIDs,
+	}
+}
+
+func searchQuery(keyword string) *repository.PostSearchQuery {
+	query := &repository.PostSearchQuery{Keyword: keyword}
+	query.Status = entity.PostStatusPublished
+	return query
+}
+
+func TestMemoryIndexSearch(t *testing.T) {
+	idx := search.NewMemoryIndex()
+	assert.NoError(t, idx.Rebuild([]*repository.SearchDocument{
+		newDoc(1, "数据库索引优化", "介绍MySQL索引", entity.PostStatusPublished, 1),
+		newDoc(2, "Go语言入门", "顺便聊聊数据库连接池", entity.PostStatusPublished, 2),
+		newDoc(3, "数据库草稿", "未发布", entity.PostStatusDraft),
+		newDoc(4, "数据结构", "链表与树", entity.PostStatusPublished),
+	}))
+
+	hits, total, err := idx.Search(searchQuery("数据库"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	// 标题命中排在正文命中之前，草稿和只包含部分词项的文章不出现
+	assert.Equal(t, uint(1), hits[0].Post.ID)
+	assert.Equal(t, uint(2), hits[1].Post.ID)
+	assert.Greater(t, hits[0].Score, hits[1].Score)
+
+	// 英文不区分大小写
+	hits, _, _ = idx.Search(searchQuery("mysql"))
+	assert.Len(t, hits, 1)
+
+	// 单字查询
+	hits, _, _ = idx.Search(searchQuery("树"))
+	assert.Len(t, hits, 1)
+
+	// 按分类筛选
+	query := searchQuery("数据库")
+	query.CategoryID = 2
+	hits, _, _ = idx.Search(query)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, uint(2), hits[0].Post.ID)
+}
+
+func TestMemoryIndexUpdateAndRemove(t *testing.T) {
+	idx := search.NewMemoryIndex()
+	assert.NoError(t, idx.Index(newDoc(1, "旧标题", "内容", entity.PostStatusPublished)))
+	assert.NoError(t, idx.Index(newDoc(1, "新标题", "内容", entity.PostStatusPublished)))
+	assert.Equal(t, 1, idx.Len())
+
+	hits, _, _ := idx.Search(searchQuery("旧标题"))
+	assert.Empty(t, hits)
+	hits, _, _ = idx.Search(searchQuery("新标题"))
+	assert.Len(t, hits, 1)
+
+	// 修改返回结果不影响索引内的数据
+	hits[0].Post.Title = "被修改"
+	hits, _, _ = idx.Search(searchQuery("新标题"))
+	assert.Equal(t, "新标题", hits[0].Post.Title)
+
+	assert.NoError(t, idx.Remove(1))
+	hits, total, _ := idx.Search(searchQuery("新标题"))
+	assert.Empty(t, hits)
+	assert.Equal(t, int64(0), total)
+}
+
+func TestMemoryIndexPagination(t *testing.T) {
+	idx := search.NewMemoryIndex()
+	for i := uint(1); i <= 5; i++ {
+		assert.NoError(t, idx.Index(newDoc(i, "博客", "内容", entity.PostStatusPublished)))
+	}
+
+	query := searchQuery("博客")
+	query.Page = 2
+	query.PageSize = 2
+	hits, total, err := idx.Search(query)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	assert.Len(t, hits, 2)
+	// 得分相同按ID倒序
+	assert.Equal(t, uint(3), hits[0].Post.ID)
+}
@@ -0,0 +1,154 @@
+package spam_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/internal/infrastructure/spam"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// akismetRequests 模拟的Akismet服务收到的请求
+type akismetRequests struct {
+	paths []string
+	forms []url.Values
+}
+
+// newAkismetServer 创建模拟的Akismet服务，记录收到的请求路径和参数
+func newAkismetServer(t *testing.T, handler func(w http.ResponseWriter, form url.Values)) (*httptest.Server, *akismetRequests) {
+	requests := &akismetRequests{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, r.ParseForm())
+		requests.paths = append(requests.paths, r.URL.Path)
+		requests.forms = append(requests.forms, r.PostForm)
+		handler(w, r.PostForm)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func akismetInput() *repository.SpamCheckInput {
+	parentID := uint(1)
+	comment := &entity.Comment{
+		ParentID:  &parentID,
+		Content:   "写得很好",
+		Author:    "读者",
+		AuthorIP:  "203.0.113.7",
+		UserAgent: "Mozilla/5.0",
+		CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	return &repository.SpamCheckInput{
+		Comment:  comment,
+		Post:     &entity.Post{ID: 1, Slug: "hello-world"},
+		Referrer: "http://blog.example/posts/hello-world",
+	}
+}
+
+func TestAkismetChecker_CommentCheck(t *testing.T) {
+	server, requests := newAkismetServer(t, func(w http.ResponseWriter, form url.Values) {
+		switch form.Get("comment_content") {
+		case "写得很好":
+			w.Write([]byte("false"))
+		case "垃圾广告":
+			w.Header().Set("X-akismet-pro-tip", "discard")
+			w.Write([]byte("true"))
+		default:
+			w.Header().Set("X-akismet-debug-help", "Empty \"api_key\" value")
+			w.Write([]byte("invalid"))
+		}
+	})
+	checker := spam.NewAkismetChecker(server.URL+"/", "test-key", "http://blog.example/", time.Second)
+
+	input := akismetInput()
+	result, err := checker.Check(input)
+	assert.NoError(t, err)
+	assert.False(t, result.Spam)
+	assert.Equal(t, []string{"/1.1/comment-check"}, requests.paths)
+	form := requests.forms[0]
+	assert.Equal(t, "test-key", form.Get("api_key"))
+	assert.Equal(t, "http://blog.example", form.Get("blog"))
+	assert.Equal(t, "203.0.113.7", form.Get("user_ip"))
+	assert.Equal(t, "Mozilla/5.0", form.Get("user_agent"))
+	assert.Equal(t, "reply", form.Get("comment_type"))
+	assert.Equal(t, "http://blog.example/posts/hello-world", form.Get("permalink"))
+	assert.Equal(t, "2024-01-01T12:00:00Z", form.Get("comment_date_gmt"))
+
+	input.Comment.Content = "垃圾广告"
+	result, err = checker.Check(input)
+	assert.NoError(t, err)
+	assert.True(t, result.Spam)
+	assert.Contains(t, result.Reason, "明显")
+
+	input.Comment.Content = "其他"
+	_, err = checker.Check(input)
+	assert.ErrorContains(t, err, "api_key")
+}
+
+func TestAkismetChecker_SubmitSpamAndHam(t *testing.T) {
+	server, requests := newAkismetServer(t, func(w http.ResponseWriter, form url.Values) {
+		w.Write([]byte("Thanks for making the web a better place."))
+	})
+	checker := spam.NewAkismetChecker(server.URL, "test-key", "http://blog.example", time.Second)
+
+	assert.NoError(t, checker.Learn(akismetInput(), true))
+	assert.NoError(t, checker.Learn(akismetInput(), false))
+	assert.Equal(t, []string{"/1.1/submit-spam", "/1.1/submit-ham"}, requests.paths)
+}
+
+func TestAkismetChecker_FailsWithError(t *testing.T) {
+	release := make(chan struct{})
+	slow, _ := newAkismetServer(t, func(w http.ResponseWriter, form url.Values) {
+		<-release
+		w.Write([]byte("true"))
+	})
+	defer close(release)
+	checker := spam.NewAkismetChecker(slow.URL, "test-key", "http://blog.example", 50*time.Millisecond)
+	_, err := checker.Check(akismetInput())
+	assert.Error(t, err)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	checker = spam.NewAkismetChecker(failing.URL, "test-key", "http://blog.example", time.Second)
+	_, err = checker.Check(akismetInput())
+	assert.ErrorContains(t, err, "500")
+	assert.Error(t, checker.Learn(akismetInput(), true))
+}
+
+func TestChainChecker_FailOpen(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	local := spam.NewLocalChecker(spam.Settings{BannedWords: []string{"casino"}, RateLimit: 1, RateWindow: time.Minute})
+	chain := spam.NewChainChecker(local, spam.NewAkismetChecker(down.URL, "test-key", "http://blog.example", time.Second))
+
+	// 外部服务不可用时放行
+	result, err := chain.Check(akismetInput())
+	assert.NoError(t, err)
+	assert.False(t, result.Spam)
+
+	// 本地判定为垃圾评论时不再请求外部服务，限流错误直接返回
+	input := akismetInput()
+	input.Comment.AuthorIP = "203.0.113.8"
+	input.Comment.Content = "casino"
+	result, err = chain.Check(input)
+	assert.NoError(t, err)
+	assert.True(t, result.Spam)
+	_, err = chain.Check(input)
+	assert.ErrorIs(t, err, repository.ErrCommentRateLimited)
+
+	// 学习时汇总各检测器的错误
+	err = chain.Learn(akismetInput(), true)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, repository.ErrCommentRateLimited))
+}
@@ -0,0 +1,109 @@
+package spam_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"blog/internal/domain/entity"
+	"blog/internal/domain/repository"
+	"blog/internal/infrastructure/spam"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newInput(content string) *repository.SpamCheckInput {
+	return &repository.SpamCheckInput{Comment: &entity.Comment{Content: content, Author: "读者"}}
+}
+
+func TestLocalChecker_Heuristics(t *testing.T) {
+	checker := spam.NewLocalChecker(spam.Settings{
+		MaxLinks:    2,
+		BannedWords: []string{"Casino", " "},
+	})
+
+	result, err := checker.Check(newInput("写得很好，感谢分享 https://example.com"))
+	assert.NoError(t, err)
+	assert.False(t, result.Spam)
+
+	input := newInput("写得很好")
+	input.Honeypot = "http://spam.example"
+	result, err = checker.Check(input)
+	assert.NoError(t, err)
+	assert.True(t, result.Spam)
+
+	// 违禁词不区分大小写
+	result, err = checker.Check(newInput("best CASINO online"))
+	assert.NoError(t, err)
+	assert.True(t, result.Spam)
+	assert.Contains(t, result.Reason, "casino")
+
+	// a标签和其中的地址只计为一个链接
+	result, err = checker.Check(newInput(`<a href="https://a.example">a</a> https://www.b.example`))
+	assert.NoError(t, err)
+	assert.False(t, result.Spam)
+
+	result, err = checker.Check(newInput("http://a.example www.b.example https://c.example"))
+	assert.NoError(t, err)
+	assert.True(t, result.Spam)
+}
+
+func TestLocalChecker_RateLimit(t *testing.T) {
+	checker := spam.NewLocalChecker(spam.Settings{RateLimit: 2, RateWindow: time.Minute})
+
+	input := newInput("评论内容")
+	input.Comment.AuthorIP = "203.0.113.7"
+	for i := 0; i < 2; i++ {
+		_, err := checker.Check(input)
+		assert.NoError(t, err)
+	}
+	_, err := checker.Check(input)
+	assert.ErrorIs(t, err, repository.ErrCommentRateLimited)
+
+	// 其他IP不受影响
+	other := newInput("评论内容")
+	other.Comment.AuthorIP = "203.0.113.8"
+	_, err = checker.Check(other)
+	assert.NoError(t, err)
+}
+
+func TestRateLimiter_SlidingWindow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := spam.NewRateLimiter(2, time.Minute)
+	limiter.SetClock(func() time.Time { return now })
+
+	assert.True(t, limiter.Allow("ip"))
+	now = now.Add(30 * time.Second)
+	assert.True(t, limiter.Allow("ip"))
+	assert.False(t, limiter.Allow("ip"))
+
+	// 第一次请求移出窗口后恢复一个名额
+	now = now.Add(31 * time.Second)
+	assert.True(t, limiter.Allow("ip"))
+	assert.False(t, limiter.Allow("ip"))
+}
+
+func TestLocalChecker_LearnsFromModeration(t *testing.T) {
+	checker := spam.NewLocalChecker(spam.Settings{BayesThreshold: 0.9})
+
+	spamText := "便宜代购 免费领取 优惠券 点击领取"
+	result, err := checker.Check(newInput(spamText))
+	assert.NoError(t, err)
+	assert.False(t, result.Spam, "训练样本不足时不参与判定")
+
+	hams := []string{"文章写得很清楚", "感谢作者的分享", "这个例子很有帮助", "请问第二步怎么配置", "期待下一篇文章"}
+	spams := []string{"免费领取优惠券", "便宜代购点击领取", "优惠券免费送", "点击领取免费礼品", "代购便宜优惠"}
+	for i := range hams {
+		assert.NoError(t, checker.Learn(newInput(hams[i]), false))
+		assert.NoError(t, checker.Learn(newInput(spams[i]), true))
+	}
+
+	result, err = checker.Check(newInput(spamText))
+	assert.NoError(t, err)
+	assert.True(t, result.Spam)
+	assert.True(t, strings.HasPrefix(result.Reason, "贝叶斯"))
+
+	result, err = checker.Check(newInput("感谢分享，文章很有帮助"))
+	assert.NoError(t, err)
+	assert.False(t, result.Spam)
+}
@@ -0,0 +1,65 @@
+package middleware_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"blog/pkg/middleware"
+	"blog/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret"
+
+// 使用lookup发起携带令牌的POST请求，返回响应和处理器看到的角色
+func performAuth(t *testing.T, lookup middleware.UserLookup, token string) (*httptest.ResponseRecorder, string) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middleware.ErrorHandler())
+	var role string
+	engine.POST("/", middleware.Auth(testSecret, lookup), func(c *gin.Context) {
+		role = middleware.CurrentRole(c)
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, req)
+	return recorder, role
+}
+
+func TestAuthUsesCurrentRole(t *testing.T) {
+	// 令牌签发时为编辑，之后被降级为读者
+	token, _, err := utils.GenerateToken(testSecret, 2, "lisi", "editor", time.Hour)
+	require.NoError(t, err)
+
+	recorder, role := performAuth(t, func(userID uint) (string, string, error) {
+		assert.Equal(t, uint(2), userID)
+		return "lisi", "reader", nil
+	}, token)
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Equal(t, "reader", role)
+}
+
+func TestAuthRejectsUnknownUser(t *testing.T) {
+	token, _, err := utils.GenerateToken(testSecret, 2, "lisi", "editor", time.Hour)
+	require.NoError(t, err)
+
+	recorder, _ := performAuth(t, func(userID uint) (string, string, error) {
+		return "", "", fmt.Errorf("用户不存在: %d: %w", userID, utils.ErrNotFound)
+	}, token)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder, _ = performAuth(t, func(userID uint) (string, string, error) {
+		return "", "", errors.New("连接失败")
+	}, token)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
@@ -0,0 +1,79 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blog/pkg/middleware"
+	"blog/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// 发起请求，处理器通过c.Error记录err
+func performError(err error, message string) (*httptest.ResponseRecorder, utils.Response) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middleware.ErrorHandler())
+	engine.GET("/", func(c *gin.Context) {
+		_ = c.Error(err).SetMeta(message)
+	})
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var resp utils.Response
+	_ = json.Unmarshal(recorder.Body.Bytes(), &resp)
+	return recorder, resp
+}
+
+func TestErrorHandlerMapsAppError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{fmt.Errorf("文章不存在: 1: %w", utils.ErrNotFound), http.StatusNotFound, "not_found"},
+		{fmt.Errorf("slug已被使用: %w", utils.ErrConflict), http.StatusConflict, "conflict"},
+		{utils.NewValidationError(errors.New("缺少标题")), http.StatusBadRequest, "validation_failed"},
+		{utils.ErrForbidden, http.StatusForbidden, "forbidden"},
+		{utils.NewAppError(http.StatusTooManyRequests, "rate_limited", "请求过于频繁", nil), http.StatusTooManyRequests, "rate_limited"},
+	}
+
+	for _, tt := range tests {
+		recorder, resp := performError(tt.err, "请求失败")
+		assert.Equal(t, tt.status, recorder.Code)
+		assert.Equal(t, tt.code, resp.Code)
+		assert.Equal(t, "请求失败", resp.Message)
+		assert.Equal(t, tt.err.Error(), resp.Error)
+		assert.False(t, resp.Success)
+	}
+}
+
+func TestErrorHandlerUnknownError(t *testing.T) {
+	recorder, resp := performError(errors.New("连接失败"), "")
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "internal_error", resp.Code)
+	assert.Equal(t, "服务器内部错误", resp.Message)
+}
+
+func TestErrorHandlerSkipsWrittenResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middleware.ErrorHandler())
+	engine.GET("/", func(c *gin.Context) {
+		_ = c.Error(utils.ErrNotFound)
+		c.JSON(http.StatusOK, utils.NewSuccessResponse(nil, "成功"))
+	})
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,73 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+
+	"blog/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// applyDiff 按差异结果还原两边的文本，用于验证差异的正确性
+func applyDiff(lines []utils.DiffLine) (string, string) {
+	var oldLines, newLines []string
+	for _, line := range lines {
+		if line.Op != utils.DiffInsert {
+			oldLines = append(oldLines, line.Text)
+		}
+		if line.Op != utils.DiffDelete {
+			newLines = append(newLines, line.Text)
+		}
+	}
+	return strings.Join(oldLines, "\n"), strings.Join(newLines, "\n")
+}
+
+func TestDiffLines(t *testing.T) {
+	oldText := "第一行\n第二行\n第三行\n第四行"
+	newText := "第一行\n第二行（修改）\n第三行\n第五行\n第四行"
+
+	lines := utils.DiffLines(oldText, newText)
+	assert.Equal(t, []utils.DiffLine{
+		{Op: utils.DiffEqual, Text: "第一行", OldLine: 1, NewLine: 1},
+		{Op: utils.DiffDelete, Text: "第二行", OldLine: 2},
+		{Op: utils.DiffInsert, Text: "第二行（修改）", NewLine: 2},
+		{Op: utils.DiffEqual, Text: "第三行", OldLine: 3, NewLine: 3},
+		{Op: utils.DiffInsert, Text: "第五行", NewLine: 4},
+		{Op: utils.DiffEqual, Text: "第四行", OldLine: 4, NewLine: 5},
+	}, lines)
+}
+
+func TestDiffLines_EdgeCases(t *testing.T) {
+	assert.Empty(t, utils.DiffLines("", ""))
+
+	lines := utils.DiffLines("", "a\nb")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, utils.DiffInsert, lines[0].Op)
+
+	lines = utils.DiffLines("a\r\nb", "a\nb")
+	for _, line := range lines {
+		assert.Equal(t, utils.DiffEqual, line.Op)
+	}
+
+	cases := [][2]string{
+		{"a\nb\nc\na\nb\nb\na", "c\nb\na\nb\na\nc"},
+		{"x\ny\nz", "1\n2\n3"},
+		{"same", "same"},
+	}
+	for _, c := range cases {
+		lines := utils.DiffLines(c[0], c[1])
+		oldText, newText := applyDiff(lines)
+		assert.Equal(t, c[0], oldText)
+		assert.Equal(t, c[1], newText)
+	}
+
+	// 经典示例的最短编辑距离为5
+	changes := 0
+	for _, line := range utils.DiffLines("a\nb\nc\na\nb\nb\na", "c\nb\na\nb\na\nc") {
+		if line.Op != utils.DiffEqual {
+			changes++
+		}
+	}
+	assert.Equal(t, 5, changes)
+}
@@ -0,0 +1,35 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+
+	"blog/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTerms(t *testing.T) {
+	terms := utils.SearchTerms("Go 数据库")
+	assert.Equal(t, []string{"go", "数据库", "数据", "据库"}, terms)
+}
+
+func TestHighlight(t *testing.T) {
+	terms := utils.SearchTerms("go 数据库")
+
+	assert.Equal(t, "学习<mark>Go</mark>和<mark>数据库</mark>", utils.Highlight("学习Go和数据库", terms, 0))
+	// 只命中部分二元组时也应高亮
+	assert.Equal(t, "<mark>数据</mark>结构", utils.Highlight("数据结构", terms, 0))
+	// 非命中部分需要转义
+	assert.Equal(t, "&lt;b&gt;<mark>go</mark>", utils.Highlight("<b>go", terms, 0))
+}
+
+func TestHighlightSnippetWindow(t *testing.T) {
+	text := strings.Repeat("前", 100) + "数据库" + strings.Repeat("后", 100)
+	snippet := utils.Highlight(text, utils.SearchTerms("数据库"), 40)
+
+	assert.True(t, strings.HasPrefix(snippet, "…"))
+	assert.True(t, strings.HasSuffix(snippet, "…"))
+	assert.Contains(t, snippet, "<mark>数据库</mark>")
+	assert.Equal(t, 40+2, len([]rune(strings.NewReplacer("<mark>", "", "</mark>", "").Replace(snippet))))
+}
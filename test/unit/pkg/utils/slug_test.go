@@ -0,0 +1,24 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+
+	"blog/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "hello-world", utils.Slugify("Hello, World!"))
+	assert.Equal(t, "go-yu-yan-ru-men", utils.Slugify("Go语言入门"))
+	assert.Equal(t, "mysql-8-0-xin-te-xing", utils.Slugify("  MySQL 8.0 新特性  "))
+	assert.Equal(t, "", utils.Slugify("!!!"))
+}
+
+func TestSlugifyTruncate(t *testing.T) {
+	slug := utils.Slugify(strings.Repeat("word ", 30))
+	assert.LessOrEqual(t, len(slug), utils.MaxSlugLength)
+	assert.False(t, strings.HasSuffix(slug, "-"))
+	assert.True(t, strings.HasSuffix(slug, "word"))
+}